@@ -0,0 +1,53 @@
+package main
+
+import (
+	"context"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/stones-hub/taurus-pro-core/pkg/dev"
+)
+
+func newDevCmd() *cobra.Command {
+	opts := dev.Options{}
+
+	devCmd := &cobra.Command{
+		Use:   "dev [project-path] [-- service-args...]",
+		Short: "Watch the project, regenerate wire and restart the service on changes",
+		Long: `监听 app、bin、internal、pkg、config 目录的变化：
+provider set 或构造函数签名变化时重新生成 app/wire.go 与注入器，随后重新编译 bin/ 下的服务并重启。
+重启时向旧进程发送 SIGTERM 以执行优雅关闭，新版本编译成功之前旧进程保持运行。`,
+		Args: cobra.ArbitraryArgs,
+		Example: `  # 在项目根目录启动开发模式
+  taurus dev
+
+  # 指定项目路径、环境变量文件和配置目录
+  taurus dev ./my-project --env .env.local --config ./config`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			opts.ProjectRoot = "."
+			if dash := cmd.ArgsLenAtDash(); dash >= 0 {
+				opts.Args = args[dash:]
+				args = args[:dash]
+			}
+			if len(args) > 0 {
+				opts.ProjectRoot = args[0]
+			}
+
+			ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+			defer stop()
+
+			return dev.Run(ctx, opts)
+		},
+	}
+
+	devCmd.Flags().StringVarP(&opts.Env, "env", "e", ".env.local", "传递给服务的环境变量文件")
+	devCmd.Flags().StringVarP(&opts.ConfigPath, "config", "c", "./config", "传递给服务的配置目录")
+	devCmd.Flags().StringVar(&opts.MainPath, "main", "./bin/taurus.go", "服务入口文件")
+	devCmd.Flags().DurationVar(&opts.Interval, "interval", 500*time.Millisecond, "文件变化轮询间隔")
+	devCmd.Flags().DurationVar(&opts.StopTimeout, "stop-timeout", 15*time.Second, "等待服务优雅关闭的最长时间")
+
+	return devCmd
+}
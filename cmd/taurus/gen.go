@@ -0,0 +1,159 @@
+package main
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+	"github.com/stones-hub/taurus-pro-core/pkg/gen"
+)
+
+func newGenCmd() *cobra.Command {
+	genCmd := &cobra.Command{
+		Use:   "gen",
+		Short: "Generate code for an existing Taurus Pro project",
+		Run: func(cmd *cobra.Command, args []string) {
+			cmd.Help()
+		},
+	}
+
+	genCmd.AddCommand(newGenResourceCmd())
+	genCmd.AddCommand(newGenFromDBCmd())
+	return genCmd
+}
+
+func newGenResourceCmd() *cobra.Command {
+	var (
+		fields string
+		dbName string
+		opts   gen.Options
+	)
+
+	resourceCmd := &cobra.Command{
+		Use:   "resource <Name> [project-path]",
+		Short: "Generate model, DTOs, service, controller, routes and test for a CRUD resource",
+		Long: `生成一个完整的 CRUD 资源：
+  app/model/<name>_model.go             GORM 实体与 Repository
+  app/model/dto/<name>_dto.go           创建、更新、列表的请求与响应
+  app/service/<name>_service.go         增删改查服务，使用 --db 指定的数据库连接
+  app/controller/<name>_controller.go   HTTP 控制器
+  app/controller/<name>_controller_test.go  基于内存 sqlite 的测试
+随后在 bin/taurus.go 中注册 /<name>/create|get|list|update|delete 路由，并刷新 app/wire.go。
+
+字段格式为 name:type[:modifier...]，多个字段用逗号分隔。
+支持的类型: string text int int8 int16 int32 int64 uint uint8 uint16 uint32 uint64
+            float32 float64 decimal bool time date bytes
+支持的修饰符: unique index null`,
+		Args: cobra.RangeArgs(1, 2),
+		Example: `  # 生成 Article 资源
+  taurus gen resource Article --fields "title:string,content:text,views:int"
+
+  # 指定项目路径与数据库连接，email 唯一、bio 可为空
+  taurus gen resource Member ./my-project --fields "email:string:unique,bio:text:null" --db user_db`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			opts.ProjectRoot = "."
+			if len(args) > 1 {
+				opts.ProjectRoot = args[1]
+			}
+
+			res, err := gen.NewResource(args[0], fields, dbName)
+			if err != nil {
+				return err
+			}
+			return gen.NewGenerator(opts).Generate(res)
+		},
+	}
+
+	resourceCmd.Flags().StringVar(&fields, "fields", "", "资源字段，如 \"name:string,age:int\"")
+	resourceCmd.Flags().StringVar(&dbName, "db", "default", "使用的数据库连接名，对应 databases.list 中的 dbname")
+	resourceCmd.Flags().StringVar(&opts.MainPath, "main", "bin/taurus.go", "注册路由的入口文件")
+	resourceCmd.Flags().BoolVar(&opts.Force, "force", false, "覆盖已存在的文件")
+	resourceCmd.Flags().BoolVar(&opts.SkipRoutes, "skip-routes", false, "不在入口文件中注册路由")
+	resourceCmd.Flags().BoolVar(&opts.SkipWire, "skip-wire", false, "不刷新 app/wire.go")
+	resourceCmd.MarkFlagRequired("fields")
+
+	return resourceCmd
+}
+
+func newGenFromDBCmd() *cobra.Command {
+	var (
+		dbName     string
+		tables     []string
+		name       string
+		configPath string
+		envFile    string
+		opts       gen.Options
+	)
+
+	fromDBCmd := &cobra.Command{
+		Use:   "from-db [project-path]",
+		Short: "Generate CRUD scaffolding by introspecting existing database tables",
+		Long: `连接 databases.list 中 --db 指定的数据库（与存储组件使用同一份配置与 DSN），
+读取 --table 的列、主键、索引与注释，生成 model、dto、service、controller，
+注册路由并刷新 app/wire.go。可为空的列使用指针类型，created_at/updated_at 由 GORM 自动维护。
+支持 mysql、postgres、sqlite。`,
+		Args: cobra.MaximumNArgs(1),
+		Example: `  # 从 admin 数据库的 admin_users 表生成 AdminUser 资源
+  taurus gen from-db --db admin --table admin_users
+
+  # 一次生成多张表，指定配置目录与环境变量文件
+  taurus gen from-db ./my-project --db admin --table admin_roles --table admin_depts --config ./config --env .env.local
+
+  # 自定义资源名
+  taurus gen from-db --db admin --table t_member --name Member`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			opts.ProjectRoot = "."
+			if len(args) > 0 {
+				opts.ProjectRoot = args[0]
+			}
+			if name != "" && len(tables) > 1 {
+				return fmt.Errorf("--name 只能在生成单张表时使用")
+			}
+			if !filepath.IsAbs(configPath) {
+				configPath = filepath.Join(opts.ProjectRoot, configPath)
+			}
+			if envFile != "" && !filepath.IsAbs(envFile) {
+				envFile = filepath.Join(opts.ProjectRoot, envFile)
+			}
+
+			database, err := gen.FindDatabase(configPath, envFile, dbName)
+			if err != nil {
+				return err
+			}
+			database.ResolvePath(opts.ProjectRoot)
+			db, err := database.Open()
+			if err != nil {
+				return err
+			}
+			if sqlDB, err := db.DB(); err == nil {
+				defer sqlDB.Close()
+			}
+
+			// 表结构不一定能在 sqlite 中还原，不生成基于 sqlite 的测试
+			opts.SkipTest = true
+			resources := make([]*gen.Resource, 0, len(tables))
+			for _, table := range tables {
+				res, err := gen.NewResourceFromTable(db, table, name, dbName)
+				if err != nil {
+					return err
+				}
+				resources = append(resources, res)
+			}
+			return gen.NewGenerator(opts).Generate(resources...)
+		},
+	}
+
+	fromDBCmd.Flags().StringVar(&dbName, "db", "", "数据库连接名，对应 databases.list 中的 dbname")
+	fromDBCmd.Flags().StringArrayVar(&tables, "table", nil, "要生成的数据表，可重复指定")
+	fromDBCmd.Flags().StringVar(&name, "name", "", "资源名，默认由表名推导，如 admin_users -> AdminUser")
+	fromDBCmd.Flags().StringVarP(&configPath, "config", "c", "config", "配置目录，相对于项目路径")
+	fromDBCmd.Flags().StringVarP(&envFile, "env", "e", ".env.local", "展开配置中 ${NAME:default} 使用的环境变量文件，相对于项目路径")
+	fromDBCmd.Flags().StringVar(&opts.MainPath, "main", "bin/taurus.go", "注册路由的入口文件")
+	fromDBCmd.Flags().BoolVar(&opts.Force, "force", false, "覆盖已存在的文件")
+	fromDBCmd.Flags().BoolVar(&opts.SkipRoutes, "skip-routes", false, "不在入口文件中注册路由")
+	fromDBCmd.Flags().BoolVar(&opts.SkipWire, "skip-wire", false, "不刷新 app/wire.go")
+	fromDBCmd.MarkFlagRequired("db")
+	fromDBCmd.MarkFlagRequired("table")
+
+	return fromDBCmd
+}
@@ -0,0 +1,76 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/stones-hub/taurus-pro-core/pkg/components"
+	"github.com/stones-hub/taurus-pro-core/pkg/graph"
+)
+
+func newGraphCmd() *cobra.Command {
+	var (
+		format string
+		output string
+	)
+
+	graphCmd := &cobra.Command{
+		Use:   "graph [project-path]",
+		Short: "Export the dependency graph of components and app providers",
+		Long: `合并组件注入器(internal/taurus)与应用注入器(app)的依赖关系，输出 DOT、Mermaid 或 JSON 格式的依赖图。
+虚线表示通过全局 taurus.Container 访问组件，处于循环依赖中的节点会被标红。`,
+		Args:                  cobra.MaximumNArgs(1),
+		DisableFlagsInUseLine: true,
+		Example: `  # 输出 DOT 格式并渲染为图片
+  taurus graph ./my-project | dot -Tpng -o graph.png
+
+  # 输出 Mermaid 格式，嵌入服务文档
+  taurus graph ./my-project -f mermaid -o docs/graph.mmd
+
+  # 输出 JSON 格式
+  taurus graph -f json`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			projectRoot := "."
+			if len(args) > 0 {
+				projectRoot = args[0]
+			}
+			return runGraph(projectRoot, graph.Format(strings.ToLower(format)), output)
+		},
+	}
+
+	graphCmd.Flags().StringVarP(&format, "format", "f", string(graph.FormatDOT), "输出格式: dot, mermaid, json")
+	graphCmd.Flags().StringVarP(&output, "output", "o", "", "输出文件路径，默认输出到标准输出")
+
+	return graphCmd
+}
+
+func runGraph(projectRoot string, format graph.Format, output string) error {
+	g, err := graph.Build(projectRoot, components.AllComponents)
+	if err != nil {
+		return fmt.Errorf("构建依赖图失败: %v", err)
+	}
+
+	var w io.Writer = os.Stdout
+	if output != "" {
+		f, err := os.Create(output)
+		if err != nil {
+			return fmt.Errorf("创建输出文件失败: %v", err)
+		}
+		defer f.Close()
+		w = f
+	}
+
+	if err := g.Write(w, format); err != nil {
+		return err
+	}
+
+	// 循环依赖输出到标准错误，不影响重定向的图数据
+	for _, cycle := range g.Cycles() {
+		fmt.Fprintf(os.Stderr, "⚠️  检测到循环依赖: %s\n", strings.Join(cycle, " <-> "))
+	}
+
+	return nil
+}
@@ -30,6 +30,18 @@ func main() {
 		Example: `  # 创建新项目
   taurus create my-project
 
+  # 生成 CRUD 资源
+  taurus gen resource Article --fields "title:string,content:text"
+
+  # 生成 OpenAPI 文档
+  taurus openapi ./my-project
+
+  # 导出项目依赖图
+  taurus graph ./my-project -f mermaid
+
+  # 树形查看本地导出的追踪
+  taurus trace view logs/otel/traces.jsonl
+
   # 查看帮助
   taurus --help
   taurus create --help`,
@@ -52,6 +64,11 @@ func main() {
 	}
 
 	rootCmd.AddCommand(createCmd)
+	rootCmd.AddCommand(newGenCmd())
+	rootCmd.AddCommand(newOpenAPICmd())
+	rootCmd.AddCommand(newGraphCmd())
+	rootCmd.AddCommand(newDevCmd())
+	rootCmd.AddCommand(newTraceCmd())
 
 	if err := rootCmd.Execute(); err != nil {
 		fmt.Println(err)
@@ -0,0 +1,78 @@
+package main
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+	"github.com/stones-hub/taurus-pro-core/pkg/openapi"
+)
+
+func newOpenAPICmd() *cobra.Command {
+	var (
+		output string
+		opts   openapi.Options
+	)
+
+	openapiCmd := &cobra.Command{
+		Use:   "openapi [project-path]",
+		Short: "Generate an OpenAPI 3 document from the project's routes and controllers",
+		Long: `静态分析项目源码生成 OpenAPI 3 文档，不需要编译或运行服务：
+  路由        bin/ 中的 AddRouter 与 AddRouterGroup 调用，包括路由组前缀与中间件
+  处理函数    http.HandlerFunc(app.Core.XxxController.Method) 指向的控制器方法，或匿名函数
+  请求方法    r.Method 的比较，未限制时有请求体为 POST，否则为 GET
+  请求参数    json.NewDecoder(r.Body).Decode、json.Unmarshal、httpx.ParseJson + tmap.GetXxx、
+              tstruct.MapToStruct、r.URL.Query().Get 与 r.FormValue
+  响应        httpx.SendResponse 的状态码与数据，BaseController.Response 的 {code, message, data} 包装
+  认证        路由中间件包含 JWT 时标记为 bearerAuth
+
+输出文件扩展名为 .json 时输出 JSON，否则输出 YAML。
+开启 http.openapi.enabled 后，服务会在 http.openapi.path 提供文档与 UI。`,
+		Args: cobra.MaximumNArgs(1),
+		Example: `  # 生成 docs/openapi.yaml
+  taurus openapi
+
+  # 指定项目路径、标题、版本与服务地址
+  taurus openapi ./my-project --title "My API" --version 1.2.0 --server http://localhost:9080
+
+  # 输出 JSON
+  taurus openapi -o docs/openapi.json`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			opts.ProjectRoot = "."
+			if len(args) > 0 {
+				opts.ProjectRoot = args[0]
+			}
+			if output == "" {
+				output = filepath.Join(opts.ProjectRoot, "docs", "openapi.yaml")
+			}
+
+			doc, err := openapi.Generate(opts)
+			if err != nil {
+				return fmt.Errorf("生成 OpenAPI 文档失败: %v", err)
+			}
+			if err := openapi.Write(doc, output); err != nil {
+				return err
+			}
+
+			operations := 0
+			for _, item := range doc.Paths {
+				for _, op := range []*openapi.Operation{item.Get, item.Post, item.Put, item.Patch, item.Delete} {
+					if op != nil {
+						operations++
+					}
+				}
+			}
+			fmt.Printf("已生成 %s: %d 个路径, %d 个接口, %d 个 schema\n", output, len(doc.Paths), operations, len(doc.Components.Schemas))
+			return nil
+		},
+	}
+
+	openapiCmd.Flags().StringVarP(&output, "output", "o", "", "输出文件路径，默认 <project-path>/docs/openapi.yaml")
+	openapiCmd.Flags().StringVar(&opts.EntryDir, "entry", "bin", "注册路由的入口目录")
+	openapiCmd.Flags().StringVar(&opts.Title, "title", "", "文档标题，默认使用模块名")
+	openapiCmd.Flags().StringVar(&opts.Version, "version", "1.0.0", "文档版本")
+	openapiCmd.Flags().StringVar(&opts.Description, "description", "", "文档描述")
+	openapiCmd.Flags().StringArrayVar(&opts.Servers, "server", nil, "服务地址，可重复指定")
+
+	return openapiCmd
+}
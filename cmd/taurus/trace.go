@@ -0,0 +1,66 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/stones-hub/taurus-pro-core/pkg/trace"
+)
+
+func newTraceCmd() *cobra.Command {
+	traceCmd := &cobra.Command{
+		Use:   "trace",
+		Short: "Inspect traces exported by the otel component",
+	}
+	traceCmd.AddCommand(newTraceViewCmd())
+	return traceCmd
+}
+
+func newTraceViewCmd() *cobra.Command {
+	var opts trace.Options
+
+	viewCmd := &cobra.Command{
+		Use:   "view <file>",
+		Short: "Render JSONL spans as a tree",
+		Long: `读取 otel.export.protocol 为 file 或 stdout 时导出的 JSONL span，按 trace 分组后以树形输出。
+每个 span 显示类型、耗时与相对 trace 开始的偏移，错误的 span 标记为 ✗。文件为 - 时从标准输入读取。`,
+		Args:                  cobra.ExactArgs(1),
+		DisableFlagsInUseLine: true,
+		Example: `  # 查看本地导出的追踪
+  taurus trace view logs/otel/traces.jsonl
+
+  # 只看某条 trace 并显示属性
+  taurus trace view logs/otel/traces.jsonl -t 4bf92f35 -a
+
+  # 读取 stdout 协议输出的服务日志
+  go run ./bin | taurus trace view -`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runTraceView(args[0], opts)
+		},
+	}
+
+	viewCmd.Flags().StringVarP(&opts.TraceID, "trace", "t", "", "只显示以此开头的 trace ID")
+	viewCmd.Flags().BoolVarP(&opts.Attributes, "attrs", "a", false, "显示 span 属性")
+
+	return viewCmd
+}
+
+func runTraceView(file string, opts trace.Options) error {
+	var r io.Reader = os.Stdin
+	if file != "-" {
+		f, err := os.Open(file)
+		if err != nil {
+			return fmt.Errorf("打开文件失败: %v", err)
+		}
+		defer f.Close()
+		r = f
+	}
+
+	spans, err := trace.Read(r)
+	if err != nil {
+		return err
+	}
+	return trace.Render(os.Stdout, trace.Build(spans), opts)
+}
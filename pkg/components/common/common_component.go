@@ -3,6 +3,8 @@ package common
 import (
 	"context"
 	"log"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/stones-hub/taurus-pro-common/pkg/cmd"
@@ -11,6 +13,9 @@ import (
 	"github.com/stones-hub/taurus-pro-common/pkg/logx"
 	"github.com/stones-hub/taurus-pro-common/pkg/templates"
 	"github.com/stones-hub/taurus-pro-config/pkg/config"
+	coreconfig "github.com/stones-hub/taurus-pro-core/pkg/components/config"
+	"github.com/stones-hub/taurus-pro-core/pkg/components/lifecycle"
+	"github.com/stones-hub/taurus-pro-core/pkg/components/reload"
 	"github.com/stones-hub/taurus-pro-core/pkg/components/types"
 )
 
@@ -22,15 +27,15 @@ var CommonComponent = types.Component{
 	IsCustom:     true,
 	Required:     true,
 	Dependencies: []string{"config"},
-	Wire:         []*types.Wire{cronWire, loggerWire, templateWire, hookWire, cmdWire},
+	Wire:         []*types.Wire{cronWire, loggerWire, loggerAccessorWire, templateWire, hookWire, cmdWire},
 }
 
 var cronWire = &types.Wire{
-	RequirePath:  []string{"github.com/stones-hub/taurus-pro-common/pkg/cron", "time", "log"},
+	RequirePath:  []string{"github.com/stones-hub/taurus-pro-common/pkg/cron", "context", "sync", "time", "log"},
 	Name:         "Cron",
 	Type:         "*cron.CronManager",
 	ProviderName: "ProvideCronComponent",
-	Provider: `func {{.ProviderName}}(cfg *config.Config) ({{.Type}}, func(), error) {
+	Provider: `func {{.ProviderName}}(cfg *config.Config, lc *LifecycleManager) ({{.Type}}, func(), error) {
 enable := cfg.GetBool("cron.enable")
 if !enable {
 return nil, func() {}, nil
@@ -56,14 +61,39 @@ cm := cron.New(cronOptions...)
 
 log.Printf("%s🔗 -> Cron all initialized successfully. %s\n", "\033[32m", "\033[0m")
 
-return cm, func() {
-cm.GracefulStop(time.Second * 3)
+// 定时任务在关闭的 worker 阶段停止，等待正在执行的任务的时间为阶段剩余的预算，wire 的清理函数不再重复停止
+var once sync.Once
+stop := func(timeout time.Duration) {
+once.Do(func() {
+cm.GracefulStop(timeout)
 log.Printf("%s🔗 -> Clean up cron components successfully. %s\n", "\033[32m", "\033[0m")
+})
+}
+lc.OnShutdown(LifecyclePhaseWorker, "cron", func(ctx context.Context) error {
+timeout := time.Second * 3
+if deadline, ok := ctx.Deadline(); ok {
+timeout = time.Until(deadline)
+}
+stop(timeout)
+return nil
+})
+
+return cm, func() {
+stop(time.Second * 3)
 }, nil
+}`,
+	TestRequirePath:  []string{"github.com/stones-hub/taurus-pro-common/pkg/cron"},
+	TestProviderName: "ProvideTestCronComponent",
+	TestProvider: `// ProvideTestCronComponent 创建不启动的 cron 管理器，定时任务通过 Scheduler.Schedule(h.Clock.Add) 交给假时钟执行
+func {{.TestProviderName}}(t testing.TB, c *taurus.Components, h *Harness) (*cron.CronManager, error) {
+	if !c.Config.GetBool("cron.enable") {
+		return nil, nil
+	}
+	return cron.New(cron.WithLocation(h.Clock.Now().Location())), nil
 }`,
 }
 
-func ProvideCronComponent(cfg *config.Config) (*cron.CronManager, func(), error) {
+func ProvideCronComponent(cfg *config.Config, lc *lifecycle.Manager) (*cron.CronManager, func(), error) {
 	enable := cfg.GetBool("cron.enable")
 	if !enable {
 		return nil, func() {}, nil
@@ -89,9 +119,25 @@ func ProvideCronComponent(cfg *config.Config) (*cron.CronManager, func(), error)
 
 	log.Printf("%s🔗 -> Cron all initialized successfully. %s\n", "\033[32m", "\033[0m")
 
+	// 定时任务在关闭的 worker 阶段停止，等待正在执行的任务的时间为阶段剩余的预算，wire 的清理函数不再重复停止
+	var once sync.Once
+	stop := func(timeout time.Duration) {
+		once.Do(func() {
+			cm.GracefulStop(timeout)
+			log.Printf("%s🔗 -> Clean up cron components successfully. %s\n", "\033[32m", "\033[0m")
+		})
+	}
+	lc.OnShutdown(lifecycle.PhaseWorker, "cron", func(ctx context.Context) error {
+		timeout := time.Second * 3
+		if deadline, ok := ctx.Deadline(); ok {
+			timeout = time.Until(deadline)
+		}
+		stop(timeout)
+		return nil
+	})
+
 	return cm, func() {
-		cm.GracefulStop(time.Second * 3)
-		log.Printf("%s🔗 -> Clean up cron components successfully. %s\n", "\033[32m", "\033[0m")
+		stop(time.Second * 3)
 	}, nil
 }
 
@@ -137,6 +183,17 @@ var loggerWire = &types.Wire{
 	}, err
 }
 `,
+	TestRequirePath:  []string{"github.com/stones-hub/taurus-pro-common/pkg/logx"},
+	TestProviderName: "ProvideTestLoggerComponent",
+	TestProvider: `// ProvideTestLoggerComponent 使用配置中的日志管理器，与 serve 子命令一致
+func {{.TestProviderName}}(t testing.TB, c *taurus.Components, h *Harness) (*logx.Manager, error) {
+	manager, cleanup, err := taurus.ProvideLoggerComponent(c.Config)
+	if err != nil {
+		return nil, err
+	}
+	t.Cleanup(cleanup)
+	return manager, nil
+}`,
 }
 
 func ProvideLoggerComponent(cfg *config.Config) (*logx.Manager, func(), error) {
@@ -176,6 +233,113 @@ func ProvideLoggerComponent(cfg *config.Config) (*logx.Manager, func(), error) {
 	}, err
 }
 
+var loggerAccessorWire = &types.Wire{
+	RequirePath:  []string{"github.com/stones-hub/taurus-pro-common/pkg/logx", "sync", "sync/atomic", "time"},
+	Name:         "LoggerAccessor",
+	Type:         "*LoggerAccessor",
+	ProviderName: "ProvideLoggerAccessorComponent",
+	Provider: `// LoggerAccessor 可重载的日志管理器，每次 SIGHUP 都重新创建日志管理器并原子替换
+// 持有 LoggerAccessor 的代码每次写日志时通过 Load 读取，重载后使用新的管理器；Logger 字段是启动时创建的管理器，不会更新
+type LoggerAccessor struct {
+	current atomic.Pointer[logx.Manager]
+}
+
+// Load 返回当前的日志管理器
+func (a *LoggerAccessor) Load() *logx.Manager {
+	return a.current.Load()
+}
+
+// {{.ProviderName}} 注册日志的重载：应用新的日志级别与输出配置并重新打开日志文件，
+// 配合 logrotate 等工具移动日志文件后发送 SIGHUP 即可完成轮转；旧的管理器延迟关闭，正在写入的日志不会丢失
+func {{.ProviderName}}(configs *ConfigAccessor, manager *logx.Manager, rl *ConfigReloader) ({{.Type}}, func()) {
+	accessor := &LoggerAccessor{}
+	accessor.current.Store(manager)
+
+	var (
+		mu      sync.Mutex
+		cleanup func() // 最近一次重载创建的管理器的清理函数，启动时的管理器由 Logger 组件清理
+	)
+	rl.RegisterEvery("logger", func(changed []string) error {
+		next, nextCleanup, err := ProvideLoggerComponent(configs.Load())
+		if err != nil {
+			return err
+		}
+		accessor.current.Store(next)
+
+		mu.Lock()
+		previous := cleanup
+		cleanup = nextCleanup
+		mu.Unlock()
+		if previous != nil {
+			time.AfterFunc(5*time.Second, previous)
+		}
+		return nil
+	}, "loggers")
+
+	return accessor, func() {
+		mu.Lock()
+		defer mu.Unlock()
+		if cleanup != nil {
+			cleanup()
+		}
+	}
+}`,
+	TestProviderName: "ProvideTestLoggerAccessorComponent",
+	TestProvider: `// ProvideTestLoggerAccessorComponent 与 serve 子命令一致，测试中同样可以通过 Reload 重新创建日志管理器
+func {{.TestProviderName}}(t testing.TB, c *taurus.Components, h *Harness) (*taurus.LoggerAccessor, error) {
+	accessor, cleanup := taurus.ProvideLoggerAccessorComponent(c.ConfigAccessor, c.Logger, c.Reload)
+	t.Cleanup(cleanup)
+	return accessor, nil
+}`,
+}
+
+// LoggerAccessor 可重载的日志管理器，每次 SIGHUP 都重新创建日志管理器并原子替换
+// 持有 LoggerAccessor 的代码每次写日志时通过 Load 读取，重载后使用新的管理器；Logger 字段是启动时创建的管理器，不会更新
+type LoggerAccessor struct {
+	current atomic.Pointer[logx.Manager]
+}
+
+// Load 返回当前的日志管理器
+func (a *LoggerAccessor) Load() *logx.Manager {
+	return a.current.Load()
+}
+
+// ProvideLoggerAccessorComponent 注册日志的重载：应用新的日志级别与输出配置并重新打开日志文件，
+// 配合 logrotate 等工具移动日志文件后发送 SIGHUP 即可完成轮转；旧的管理器延迟关闭，正在写入的日志不会丢失
+func ProvideLoggerAccessorComponent(configs *coreconfig.Accessor, manager *logx.Manager, rl *reload.Reloader) (*LoggerAccessor, func()) {
+	accessor := &LoggerAccessor{}
+	accessor.current.Store(manager)
+
+	var (
+		mu      sync.Mutex
+		cleanup func() // 最近一次重载创建的管理器的清理函数，启动时的管理器由 Logger 组件清理
+	)
+	rl.RegisterEvery("logger", func(changed []string) error {
+		next, nextCleanup, err := ProvideLoggerComponent(configs.Load())
+		if err != nil {
+			return err
+		}
+		accessor.current.Store(next)
+
+		mu.Lock()
+		previous := cleanup
+		cleanup = nextCleanup
+		mu.Unlock()
+		if previous != nil {
+			time.AfterFunc(5*time.Second, previous)
+		}
+		return nil
+	}, "loggers")
+
+	return accessor, func() {
+		mu.Lock()
+		defer mu.Unlock()
+		if cleanup != nil {
+			cleanup()
+		}
+	}
+}
+
 var templateWire = &types.Wire{
 	RequirePath:  []string{"github.com/stones-hub/taurus-pro-common/pkg/templates"},
 	Name:         "Templates",
@@ -209,6 +373,17 @@ var templateWire = &types.Wire{
 		cleanup()
 		log.Printf("%s🔗 -> Clean up templates components successfully. %s\n", "\033[32m", "\033[0m")
 	}, err
+}`,
+	TestRequirePath:  []string{"github.com/stones-hub/taurus-pro-common/pkg/templates"},
+	TestProviderName: "ProvideTestTemplateComponent",
+	TestProvider: `// ProvideTestTemplateComponent 使用配置中的模板管理器，模板路径相对于项目根目录
+func {{.TestProviderName}}(t testing.TB, c *taurus.Components, h *Harness) (*templates.Manager, error) {
+	manager, cleanup, err := taurus.ProvideTemplateComponent(c.Config)
+	if err != nil {
+		return nil, err
+	}
+	t.Cleanup(cleanup)
+	return manager, nil
 }`,
 }
 
@@ -243,38 +418,73 @@ func ProvideTemplateComponent(cfg *config.Config) (*templates.Manager, func(), e
 }
 
 var hookWire = &types.Wire{
-	RequirePath:  []string{"github.com/stones-hub/taurus-pro-common/pkg/hook", "context", "time", "log"},
+	RequirePath:  []string{"github.com/stones-hub/taurus-pro-common/pkg/hook", "context", "sync", "time", "log"},
 	Name:         "Hook",
 	Type:         "*hook.HookManager",
 	ProviderName: "ProvideHookComponent",
-	Provider: `func {{.ProviderName}}() ({{.Type}}, func(), error) {
+	Provider: `func {{.ProviderName}}(lc *LifecycleManager) ({{.Type}}, func(), error) {
 	hook := hook.NewHookManager()
 	log.Printf("%s🔗 -> Hook all initialized successfully. %s\n", "\033[32m", "\033[0m")
+
+	// 停止钩子（如停止队列）在关闭的 worker 阶段执行，wire 的清理函数不再重复执行
+	var once sync.Once
+	stop := func(ctx context.Context) error {
+		var err error
+		once.Do(func() {
+			err = hook.Stop(ctx)
+			if err != nil {
+				log.Printf("%s🔗 -> Clean up hook components failed, error: %v %s\n", "\033[31m", err, "\033[0m")
+			} else {
+				log.Printf("%s🔗 -> Clean up hook components successfully. %s\n", "\033[32m", "\033[0m")
+			}
+		})
+		return err
+	}
+	lc.OnShutdown(LifecyclePhaseWorker, "hook", stop)
+
 	return hook, func() {
 		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
 		defer cancel()
-		err := hook.Stop(ctx)
-		if err != nil {
-			log.Printf("%s🔗 -> Clean up hook components failed, error: %v %s\n", "\033[31m", err, "\033[0m")
-		} else {
-			log.Printf("%s🔗 -> Clean up hook components successfully. %s\n", "\033[32m", "\033[0m")
-		}
+		stop(ctx)
 	}, nil
+}`,
+	TestRequirePath:  []string{"github.com/stones-hub/taurus-pro-common/pkg/hook"},
+	TestProviderName: "ProvideTestHookComponent",
+	TestProvider: `// ProvideTestHookComponent 创建钩子管理器，测试结束时执行停止钩子
+func {{.TestProviderName}}(t testing.TB, c *taurus.Components, h *Harness) (*hook.HookManager, error) {
+	manager, cleanup, err := taurus.ProvideHookComponent(c.Lifecycle)
+	if err != nil {
+		return nil, err
+	}
+	t.Cleanup(cleanup)
+	return manager, nil
 }`,
 }
 
-func ProvideHookComponent() (*hook.HookManager, func(), error) {
+func ProvideHookComponent(lc *lifecycle.Manager) (*hook.HookManager, func(), error) {
 	hook := hook.NewHookManager()
 	log.Printf("%s🔗 -> Hook all initialized successfully. %s\n", "\033[32m", "\033[0m")
+
+	// 停止钩子（如停止队列）在关闭的 worker 阶段执行，wire 的清理函数不再重复执行
+	var once sync.Once
+	stop := func(ctx context.Context) error {
+		var err error
+		once.Do(func() {
+			err = hook.Stop(ctx)
+			if err != nil {
+				log.Printf("%s🔗 -> Clean up hook components failed, error: %v %s\n", "\033[31m", err, "\033[0m")
+			} else {
+				log.Printf("%s🔗 -> Clean up hook components successfully. %s\n", "\033[32m", "\033[0m")
+			}
+		})
+		return err
+	}
+	lc.OnShutdown(lifecycle.PhaseWorker, "hook", stop)
+
 	return hook, func() {
 		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
 		defer cancel()
-		err := hook.Stop(ctx)
-		if err != nil {
-			log.Printf("%s🔗 -> Clean up hook components failed, error: %v %s\n", "\033[31m", err, "\033[0m")
-		} else {
-			log.Printf("%s🔗 -> Clean up hook components successfully. %s\n", "\033[32m", "\033[0m")
-		}
+		stop(ctx)
 	}, nil
 }
 
@@ -290,6 +500,17 @@ var cmdWire = &types.Wire{
 		command.Clear()
 		log.Printf("%s🔗 -> Clean up command manager successfully. %s\n", "\033[32m", "\033[0m")
 	}, nil
+}`,
+	TestRequirePath:  []string{"github.com/stones-hub/taurus-pro-common/pkg/cmd"},
+	TestProviderName: "ProvideTestCmdComponent",
+	TestProvider: `// ProvideTestCmdComponent 创建脚本命令管理器
+func {{.TestProviderName}}(t testing.TB, c *taurus.Components, h *Harness) (*cmd.Manager, error) {
+	manager, cleanup, err := taurus.ProvideCmdComponent()
+	if err != nil {
+		return nil, err
+	}
+	t.Cleanup(cleanup)
+	return manager, nil
 }`,
 }
 
@@ -9,6 +9,7 @@ import (
 	"github.com/stones-hub/taurus-pro-core/pkg/components/consul"
 	"github.com/stones-hub/taurus-pro-core/pkg/components/grpc"
 	"github.com/stones-hub/taurus-pro-core/pkg/components/http"
+	"github.com/stones-hub/taurus-pro-core/pkg/components/metrics"
 	"github.com/stones-hub/taurus-pro-core/pkg/components/otel"
 	"github.com/stones-hub/taurus-pro-core/pkg/components/storage"
 	"github.com/stones-hub/taurus-pro-core/pkg/components/tcp"
@@ -31,12 +32,13 @@ var (
 	// 所有组件列表
 	AllComponents = []types.Component{
 		config.ConfigComponent,   // 自动加载，无需手动选择
+		common.CommonComponent,   // 自动加载，无需手动选择，http 的访问日志依赖其中的日志组件
 		http.HttpComponent,       // 自动加载，无需手动选择
 		grpc.GrpcComponent,       // 需要手动选择才能添加的组件
-		common.CommonComponent,   // 自动加载，无需手动选择
 		storage.StorageComponent, // 需要手动选择才能添加的组件
 		tcp.TcpComponent,         // 需要手动选择才能添加的组件
 		otel.OtelComponent,       // 需要手动选择才能添加的组件
+		metrics.MetricsComponent, // 需要手动选择才能添加的组件
 		consul.ConsulComponent,   // 需要手动选择才能添加的组件
 		tmilvus.MilvusComponent,  // 需要手动选择才能添加的组件
 		wireComponent,            // 自动加载，无需手动选择
@@ -0,0 +1,30 @@
+package config
+
+import (
+	"sync/atomic"
+
+	"github.com/stones-hub/taurus-pro-config/pkg/config"
+)
+
+// Accessor 是生成项目中 internal/taurus 的 ConfigAccessor 的对应实现，供组件的 Go 版本 Provider 使用
+// SIGHUP 时把配置加载到新的实例中，成功后原子替换，正在使用的实例不会被修改
+type Accessor struct {
+	current atomic.Pointer[config.Config]
+}
+
+// NewAccessor 创建可重载的配置，初始为 cfg
+func NewAccessor(cfg *config.Config) *Accessor {
+	a := &Accessor{}
+	a.Store(cfg)
+	return a
+}
+
+// Load 返回最近一次加载成功的配置
+func (a *Accessor) Load() *config.Config {
+	return a.current.Load()
+}
+
+// Store 替换当前的配置，由配置重载器在加载成功后调用
+func (a *Accessor) Store(cfg *config.Config) {
+	a.current.Store(cfg)
+}
@@ -1,11 +1,17 @@
 package consul
 
 import (
+	"context"
+	"encoding/json"
 	"log"
+	"sync"
 	"time"
 
+	"github.com/hashicorp/consul/api"
 	"github.com/stones-hub/taurus-pro-config/pkg/config"
 	"github.com/stones-hub/taurus-pro-consul/pkg/consul"
+	"github.com/stones-hub/taurus-pro-core/pkg/components/health"
+	"github.com/stones-hub/taurus-pro-core/pkg/components/lifecycle"
 	"github.com/stones-hub/taurus-pro-core/pkg/components/types"
 )
 
@@ -21,11 +27,11 @@ var ConsulComponent = types.Component{
 }
 
 var consulWire = &types.Wire{
-	RequirePath:  []string{"github.com/stones-hub/taurus-pro-consul/pkg/consul", "time", "log"},
+	RequirePath:  []string{"github.com/stones-hub/taurus-pro-consul/pkg/consul", "github.com/hashicorp/consul/api", "context", "encoding/json", "sync", "time", "log"},
 	Name:         "Consul",
 	Type:         "*consul.Client",
 	ProviderName: "ProvideConsulComponent",
-	Provider: `func {{.ProviderName}}(cfg *config.Config) ({{.Type}}, func(), error) {
+	Provider: `func {{.ProviderName}}(cfg *config.Config, hc *HealthRegistry, lc *LifecycleManager) ({{.Type}}, func(), error) {
 	if !cfg.GetBool("consul.enable") {
 		return nil, func() {}, nil
 	}
@@ -113,19 +119,92 @@ var consulWire = &types.Wire{
 		return nil, func() {}, err
 	}
 
-	log.Printf("%s🔗 -> Initialize consul components successfully. %s\n", "\033[32m", "\033[0m")
+	// consul 只作为非关键检查，consul 不可用时不影响服务对外提供服务
+	hc.RegisterOptional("consul", func(ctx context.Context) error {
+		_, err := client.GetAllServices()
+		return err
+	})
 
-	return client, func() {
+	// TTL 健康检查: 服务定时把健康检查注册表的结果上报给 consul，无需手写健康检查地址
+	if cfg.GetBool("consul.service.ttl.enable") {
+		agentConfig := &api.Config{
+			Address:    cfg.GetString("consul.client.address"),
+			Scheme:     cfg.GetString("consul.client.scheme"),
+			Datacenter: cfg.GetString("consul.client.datacenter"),
+			Token:      cfg.GetString("consul.client.token"),
+		}
+		if cfg.GetString("consul.client.http_basic_auth.username") != "" {
+			agentConfig.HttpAuth = &api.HttpBasicAuth{
+				Username: cfg.GetString("consul.client.http_basic_auth.username"),
+				Password: cfg.GetString("consul.client.http_basic_auth.password"),
+			}
+		}
+		agentClient, err := api.NewClient(agentConfig)
+		if err != nil {
+			log.Printf("consul api.NewClient error: %v", err)
+			return nil, func() {}, err
+		}
+
+		interval := time.Duration(cfg.GetInt("consul.service.ttl.interval")) * time.Second
+		if interval <= 0 {
+			interval = 10 * time.Second
+		}
+		checkID := "service:" + serviceConfig.ID + ":ttl"
+		if err := agentClient.Agent().CheckRegister(&api.AgentCheckRegistration{
+			ID:        checkID,
+			Name:      "service:" + serviceConfig.ID + " ttl check",
+			ServiceID: serviceConfig.ID,
+			AgentServiceCheck: api.AgentServiceCheck{
+				TTL:                            (interval * 3).String(),
+				DeregisterCriticalServiceAfter: (time.Duration(cfg.GetInt("consul.service.ttl.deregister_after")) * time.Second).String(),
+			},
+		}); err != nil {
+			log.Printf("consul.CheckRegister error: %v", err)
+			return nil, func() {}, err
+		}
+
+		// 所有服务启动之后开始上报，关闭时先于服务停止并上报 critical，consul 不再把流量分配到当前实例
+		watchCtx, cancelWatch := context.WithCancel(context.Background())
+		lc.RegisterPhase(LifecyclePhaseDeregister, "consul-ttl", &LifecycleHook{
+			OnStart: func(ctx context.Context) error {
+				go hc.Watch(watchCtx, interval, func(report *HealthReport) {
+					status := api.HealthPassing
+					if !report.Healthy() {
+						status = api.HealthCritical
+					}
+					output, _ := json.Marshal(report)
+					if err := agentClient.Agent().UpdateTTL(checkID, string(output), status); err != nil {
+						log.Printf("consul.UpdateTTL error: %v", err)
+					}
+				})
+				return nil
+			},
+			OnStop: func(ctx context.Context) error {
+				cancelWatch()
+				return agentClient.Agent().UpdateTTL(checkID, "service is shutting down", api.HealthCritical)
+			},
+		}, "http", "grpc", "tcp", "mcp", "debug")
+	}
+
+	log.Printf("%s🔗 -> Initialize consul components successfully. %s\n", "\033[32m", "\033[0m")
 
-		client.DeregisterService(cfg.GetString("consul.service.id"))
+	// 关闭的 deregister 阶段先注销服务，consul 不再把流量分配到当前实例之后再停止服务
+	deregister := sync.OnceValue(func() error {
+		return client.DeregisterService(cfg.GetString("consul.service.id"))
+	})
+	lc.OnShutdown(LifecyclePhaseDeregister, "consul", func(ctx context.Context) error {
+		return deregister()
+	})
 
+	return client, func() {
+		deregister()
 		client.Close()
 		log.Printf("%s🔗 -> Clean up consul components successfully. %s\n", "\033[32m", "\033[0m")
 	}, nil
 }`,
 }
 
-func ProvideConsulComponent(cfg *config.Config) (*consul.Client, func(), error) {
+func ProvideConsulComponent(cfg *config.Config, hc *health.Registry, lc *lifecycle.Manager) (*consul.Client, func(), error) {
 	if !cfg.GetBool("consul.enable") {
 		return nil, func() {}, nil
 	}
@@ -213,10 +292,85 @@ func ProvideConsulComponent(cfg *config.Config) (*consul.Client, func(), error)
 		return nil, func() {}, err
 	}
 
+	// consul 只作为非关键检查，consul 不可用时不影响服务对外提供服务
+	hc.RegisterOptional("consul", func(ctx context.Context) error {
+		_, err := client.GetAllServices()
+		return err
+	})
+
+	// TTL 健康检查: 服务定时把健康检查注册表的结果上报给 consul，无需手写健康检查地址
+	if cfg.GetBool("consul.service.ttl.enable") {
+		agentConfig := &api.Config{
+			Address:    cfg.GetString("consul.client.address"),
+			Scheme:     cfg.GetString("consul.client.scheme"),
+			Datacenter: cfg.GetString("consul.client.datacenter"),
+			Token:      cfg.GetString("consul.client.token"),
+		}
+		if cfg.GetString("consul.client.http_basic_auth.username") != "" {
+			agentConfig.HttpAuth = &api.HttpBasicAuth{
+				Username: cfg.GetString("consul.client.http_basic_auth.username"),
+				Password: cfg.GetString("consul.client.http_basic_auth.password"),
+			}
+		}
+		agentClient, err := api.NewClient(agentConfig)
+		if err != nil {
+			log.Printf("consul api.NewClient error: %v", err)
+			return nil, func() {}, err
+		}
+
+		interval := time.Duration(cfg.GetInt("consul.service.ttl.interval")) * time.Second
+		if interval <= 0 {
+			interval = 10 * time.Second
+		}
+		checkID := "service:" + serviceConfig.ID + ":ttl"
+		if err := agentClient.Agent().CheckRegister(&api.AgentCheckRegistration{
+			ID:        checkID,
+			Name:      "service:" + serviceConfig.ID + " ttl check",
+			ServiceID: serviceConfig.ID,
+			AgentServiceCheck: api.AgentServiceCheck{
+				TTL:                            (interval * 3).String(),
+				DeregisterCriticalServiceAfter: (time.Duration(cfg.GetInt("consul.service.ttl.deregister_after")) * time.Second).String(),
+			},
+		}); err != nil {
+			log.Printf("consul.CheckRegister error: %v", err)
+			return nil, func() {}, err
+		}
+
+		// 所有服务启动之后开始上报，关闭时先于服务停止并上报 critical，consul 不再把流量分配到当前实例
+		watchCtx, cancelWatch := context.WithCancel(context.Background())
+		lc.RegisterPhase(lifecycle.PhaseDeregister, "consul-ttl", &lifecycle.Hook{
+			OnStart: func(ctx context.Context) error {
+				go hc.Watch(watchCtx, interval, func(report *health.Report) {
+					status := api.HealthPassing
+					if !report.Healthy() {
+						status = api.HealthCritical
+					}
+					output, _ := json.Marshal(report)
+					if err := agentClient.Agent().UpdateTTL(checkID, string(output), status); err != nil {
+						log.Printf("consul.UpdateTTL error: %v", err)
+					}
+				})
+				return nil
+			},
+			OnStop: func(ctx context.Context) error {
+				cancelWatch()
+				return agentClient.Agent().UpdateTTL(checkID, "service is shutting down", api.HealthCritical)
+			},
+		}, "http", "grpc", "tcp", "mcp", "debug")
+	}
+
 	log.Printf("%s🔗 -> Initialize consul components successfully. %s\n", "\033[32m", "\033[0m")
 
+	// 关闭的 deregister 阶段先注销服务，consul 不再把流量分配到当前实例之后再停止服务
+	deregister := sync.OnceValue(func() error {
+		return client.DeregisterService(cfg.GetString("consul.service.id"))
+	})
+	lc.OnShutdown(lifecycle.PhaseDeregister, "consul", func(ctx context.Context) error {
+		return deregister()
+	})
+
 	return client, func() {
-		client.DeregisterService(cfg.GetString("consul.service.id"))
+		deregister()
 		client.Close()
 		log.Printf("%s🔗 -> Clean up consul components successfully. %s\n", "\033[32m", "\033[0m")
 	}, nil
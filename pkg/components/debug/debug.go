@@ -0,0 +1,414 @@
+// Package debug 是生成项目中 internal/taurus/debug.go 的对应实现
+package debug
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"expvar"
+	"fmt"
+	"log"
+	"net"
+	"net/http"
+	"net/http/pprof"
+	"os"
+	"path/filepath"
+	"runtime"
+	"runtime/debug"
+	runtimepprof "runtime/pprof"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Options 调试服务配置
+type Options struct {
+	Address    string            // 监听地址，默认 localhost:6060，只应在本机或内网开放
+	Username   string            // basic auth 用户名，与 Password 同时配置时启用
+	Password   string            // basic auth 密码
+	Token      string            // 访问令牌，通过 Authorization: Bearer <token> 或 ?token= 传递
+	Profiles   []string          // 允许访问的 profile，如 heap、goroutine、profile，为空时全部允许
+	Continuous ContinuousOptions // 持续采样
+}
+
+// ContinuousOptions 持续采样配置，定期把 CPU 与堆 profile 写入目录，超过保留数量的旧文件会被删除
+type ContinuousOptions struct {
+	Enable      bool   // 启动时开始采样，运行期间也可以通过 POST /debug/continuous?action=start|stop 开启或关闭
+	Dir         string // 输出目录，默认 logs/pprof
+	Interval    int    // 采样间隔(秒)，默认 300
+	CPUDuration int    // 每次 CPU 采样的时长(秒)，默认 10，不超过采样间隔
+	MaxFiles    int    // 每种 profile 保留的文件数，默认 24
+}
+
+// Server 调试服务，提供 pprof、/debug/vars 运行时快照与持续采样，实现 Lifecycle 由生命周期管理器启动与停止
+// 持续采样进行 CPU 采样期间，/debug/pprof/profile 会返回 CPU 采样已经在进行的错误
+type Server struct {
+	opts     Options
+	server   *http.Server
+	profiles map[string]bool // 允许访问的 profile，nil 表示全部允许
+	errChan  chan error
+	started  time.Time
+
+	mu     sync.Mutex
+	cancel context.CancelFunc // 持续采样正在运行时不为 nil
+	done   chan struct{}
+}
+
+// NewServer 创建调试服务，未配置的选项使用默认值
+func NewServer(opts *Options) *Server {
+	o := *opts
+	if o.Address == "" {
+		o.Address = "localhost:6060"
+	}
+	if o.Continuous.Dir == "" {
+		o.Continuous.Dir = "logs/pprof"
+	}
+	if o.Continuous.Interval <= 0 {
+		o.Continuous.Interval = 300
+	}
+	if o.Continuous.CPUDuration <= 0 {
+		o.Continuous.CPUDuration = 10
+	}
+	if o.Continuous.CPUDuration > o.Continuous.Interval {
+		o.Continuous.CPUDuration = o.Continuous.Interval
+	}
+	if o.Continuous.MaxFiles <= 0 {
+		o.Continuous.MaxFiles = 24
+	}
+
+	s := &Server{opts: o, errChan: make(chan error, 1), started: time.Now()}
+	for _, name := range o.Profiles {
+		if s.profiles == nil {
+			s.profiles = make(map[string]bool)
+		}
+		s.profiles[strings.TrimSpace(name)] = true
+	}
+	s.server = &http.Server{Addr: o.Address, Handler: s.Handler(), ReadHeaderTimeout: 10 * time.Second}
+	return s
+}
+
+// Handler 返回调试服务的路由，已经带上访问认证
+// /debug/pprof/ pprof 索引与各个 profile
+// /debug/vars 运行时快照，包含 goroutine、内存、GC、构建信息以及通过 expvar 发布的变量
+// /debug/continuous 持续采样的状态与已经写入的文件，POST ?action=start|stop 开启或关闭
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/debug/pprof/", s.pprof)
+	mux.HandleFunc("/debug/vars", s.vars)
+	mux.HandleFunc("/debug/continuous", s.continuous)
+	return s.auth(mux)
+}
+
+// Start 监听地址并在后台提供服务，监听失败作为启动错误返回，运行期间的错误发送到 Errors()
+func (s *Server) Start(ctx context.Context) error {
+	listener, err := net.Listen("tcp", s.opts.Address)
+	if err != nil {
+		return fmt.Errorf("调试服务监听失败: %v", err)
+	}
+	log.Printf("%s🔗 -> Starting debug server on %s %s\n", "\033[33m", listener.Addr(), "\033[0m")
+
+	go func() {
+		if err := s.server.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			s.errChan <- err
+		}
+	}()
+
+	if s.opts.Continuous.Enable {
+		if err := s.StartContinuous(); err != nil {
+			log.Printf("%s🔗 -> Start continuous profiling failed: %v %s\n", "\033[31m", err, "\033[0m")
+		}
+	}
+	return nil
+}
+
+// Stop 停止持续采样并关闭调试服务
+func (s *Server) Stop(ctx context.Context) error {
+	log.Printf("%s🔗 -> Shutting down debug server... %s\n", "\033[33m", "\033[0m")
+	s.StopContinuous()
+	return s.server.Shutdown(ctx)
+}
+
+// Errors 返回运行期间的错误通道，通过 LifecycleManager.Watch 转发到统一的错误通道
+func (s *Server) Errors() <-chan error {
+	return s.errChan
+}
+
+// StartContinuous 开始持续采样，已经在采样时返回错误
+func (s *Server) StartContinuous() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.cancel != nil {
+		return fmt.Errorf("持续采样已经在运行")
+	}
+	if err := os.MkdirAll(s.opts.Continuous.Dir, 0755); err != nil {
+		return fmt.Errorf("创建采样目录失败: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	s.cancel, s.done = cancel, done
+	go func() {
+		defer close(done)
+		s.runContinuous(ctx)
+	}()
+
+	log.Printf("%s🔗 -> Continuous profiling started, writing to %s every %ds %s\n", "\033[32m", s.opts.Continuous.Dir, s.opts.Continuous.Interval, "\033[0m")
+	return nil
+}
+
+// StopContinuous 停止持续采样，正在进行的 CPU 采样会提前结束并写入文件
+func (s *Server) StopContinuous() {
+	s.mu.Lock()
+	cancel, done := s.cancel, s.done
+	s.cancel, s.done = nil, nil
+	s.mu.Unlock()
+
+	if cancel == nil {
+		return
+	}
+	cancel()
+	<-done
+	log.Printf("%s🔗 -> Continuous profiling stopped. %s\n", "\033[32m", "\033[0m")
+}
+
+// ContinuousRunning 持续采样是否正在运行
+func (s *Server) ContinuousRunning() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.cancel != nil
+}
+
+func (s *Server) runContinuous(ctx context.Context) {
+	ticker := time.NewTicker(time.Duration(s.opts.Continuous.Interval) * time.Second)
+	defer ticker.Stop()
+	for {
+		s.collect(ctx)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// collect 采集一次堆与 CPU profile，文件名为 <类型>-<时间>.pprof
+func (s *Server) collect(ctx context.Context) {
+	stamp := time.Now().Format("20060102-150405")
+	err := s.writeProfile("heap", stamp, func(f *os.File) error {
+		return runtimepprof.Lookup("heap").WriteTo(f, 0)
+	})
+	if err != nil {
+		log.Printf("%s🔗 -> Write heap profile failed: %v %s\n", "\033[31m", err, "\033[0m")
+	}
+
+	err = s.writeProfile("cpu", stamp, func(f *os.File) error {
+		if err := runtimepprof.StartCPUProfile(f); err != nil {
+			return err
+		}
+		select {
+		case <-ctx.Done():
+		case <-time.After(time.Duration(s.opts.Continuous.CPUDuration) * time.Second):
+		}
+		runtimepprof.StopCPUProfile()
+		return nil
+	})
+	if err != nil {
+		log.Printf("%s🔗 -> Write cpu profile failed: %v %s\n", "\033[31m", err, "\033[0m")
+	}
+}
+
+// writeProfile 写入一个 profile 文件，失败时删除不完整的文件，成功后清理超过保留数量的旧文件
+func (s *Server) writeProfile(kind, stamp string, write func(f *os.File) error) error {
+	path := filepath.Join(s.opts.Continuous.Dir, kind+"-"+stamp+".pprof")
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	err = write(f)
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(path)
+		return err
+	}
+	return s.rotate(kind)
+}
+
+// rotate 删除超过保留数量的旧文件，文件名中的时间保证按名称排序即按时间排序
+func (s *Server) rotate(kind string) error {
+	files, err := filepath.Glob(filepath.Join(s.opts.Continuous.Dir, kind+"-*.pprof"))
+	if err != nil {
+		return err
+	}
+	sort.Strings(files)
+	for len(files) > s.opts.Continuous.MaxFiles {
+		if err := os.Remove(files[0]); err != nil {
+			return err
+		}
+		files = files[1:]
+	}
+	return nil
+}
+
+// pprof 只允许访问白名单中的 profile，索引页始终可以访问
+func (s *Server) pprof(w http.ResponseWriter, r *http.Request) {
+	name := strings.TrimPrefix(r.URL.Path, "/debug/pprof/")
+	if name == "" {
+		pprof.Index(w, r)
+		return
+	}
+	if s.profiles != nil && !s.profiles[name] {
+		http.Error(w, fmt.Sprintf("profile %s is not allowed", name), http.StatusForbidden)
+		return
+	}
+
+	switch name {
+	case "cmdline":
+		pprof.Cmdline(w, r)
+	case "profile":
+		pprof.Profile(w, r)
+	case "symbol":
+		pprof.Symbol(w, r)
+	case "trace":
+		pprof.Trace(w, r)
+	default:
+		pprof.Handler(name).ServeHTTP(w, r)
+	}
+}
+
+// vars 运行时快照
+func (s *Server) vars(w http.ResponseWriter, r *http.Request) {
+	var mem runtime.MemStats
+	runtime.ReadMemStats(&mem)
+
+	snapshot := map[string]any{
+		"time":       time.Now(),
+		"uptime":     time.Since(s.started).Round(time.Second).String(),
+		"go_version": runtime.Version(),
+		"os":         runtime.GOOS,
+		"arch":       runtime.GOARCH,
+		"num_cpu":    runtime.NumCPU(),
+		"gomaxprocs": runtime.GOMAXPROCS(0),
+		"goroutines": runtime.NumGoroutine(),
+		"cgo_calls":  runtime.NumCgoCall(),
+		"memory": map[string]any{
+			"alloc":         mem.Alloc,
+			"total_alloc":   mem.TotalAlloc,
+			"sys":           mem.Sys,
+			"heap_alloc":    mem.HeapAlloc,
+			"heap_inuse":    mem.HeapInuse,
+			"heap_idle":     mem.HeapIdle,
+			"heap_released": mem.HeapReleased,
+			"heap_objects":  mem.HeapObjects,
+			"stack_inuse":   mem.StackInuse,
+		},
+		"gc": map[string]any{
+			"num_gc":       mem.NumGC,
+			"next_gc":      mem.NextGC,
+			"pause_total":  time.Duration(mem.PauseTotalNs).String(),
+			"last_gc":      time.Unix(0, int64(mem.LastGC)),
+			"cpu_fraction": mem.GCCPUFraction,
+		},
+	}
+
+	if info, ok := debug.ReadBuildInfo(); ok {
+		build := map[string]string{"path": info.Path, "version": info.Main.Version}
+		for _, setting := range info.Settings {
+			if strings.HasPrefix(setting.Key, "vcs.") {
+				build[setting.Key] = setting.Value
+			}
+		}
+		snapshot["build"] = build
+	}
+
+	// 业务通过 expvar.Publish 发布的变量，cmdline 可能包含敏感参数，memstats 已经包含在 memory 中
+	published := make(map[string]json.RawMessage)
+	expvar.Do(func(kv expvar.KeyValue) {
+		if kv.Key == "cmdline" || kv.Key == "memstats" {
+			return
+		}
+		published[kv.Key] = json.RawMessage(kv.Value.String())
+	})
+	if len(published) > 0 {
+		snapshot["vars"] = published
+	}
+
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	json.NewEncoder(w).Encode(snapshot)
+}
+
+// continuous 持续采样的状态，POST ?action=start|stop 开启或关闭
+func (s *Server) continuous(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+	case http.MethodPost:
+		switch r.URL.Query().Get("action") {
+		case "start":
+			if err := s.StartContinuous(); err != nil {
+				http.Error(w, err.Error(), http.StatusConflict)
+				return
+			}
+		case "stop":
+			s.StopContinuous()
+		default:
+			http.Error(w, "action must be start or stop", http.StatusBadRequest)
+			return
+		}
+	default:
+		w.Header().Set("Allow", "GET, POST")
+		http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+		return
+	}
+
+	files, _ := filepath.Glob(filepath.Join(s.opts.Continuous.Dir, "*.pprof"))
+	sort.Strings(files)
+	for i, file := range files {
+		files[i] = filepath.Base(file)
+	}
+
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	json.NewEncoder(w).Encode(map[string]any{
+		"running":      s.ContinuousRunning(),
+		"dir":          s.opts.Continuous.Dir,
+		"interval":     s.opts.Continuous.Interval,
+		"cpu_duration": s.opts.Continuous.CPUDuration,
+		"files":        files,
+	})
+}
+
+// auth 配置了令牌或 basic auth 时校验请求，两者都配置时满足其一即可
+func (s *Server) auth(next http.Handler) http.Handler {
+	basic := s.opts.Username != "" && s.opts.Password != ""
+	if s.opts.Token == "" && !basic {
+		return next
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if s.opts.Token != "" {
+			token := r.URL.Query().Get("token")
+			if bearer, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer "); ok {
+				token = bearer
+			}
+			if token != "" && secureEqual(token, s.opts.Token) {
+				next.ServeHTTP(w, r)
+				return
+			}
+		}
+		if basic {
+			if username, password, ok := r.BasicAuth(); ok && secureEqual(username, s.opts.Username) && secureEqual(password, s.opts.Password) {
+				next.ServeHTTP(w, r)
+				return
+			}
+			w.Header().Set("WWW-Authenticate", `Basic realm="debug"`)
+		}
+		http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+	})
+}
+
+// secureEqual 以固定时间比较，避免通过响应时间猜测令牌
+func secureEqual(a, b string) bool {
+	return subtle.ConstantTimeCompare([]byte(a), []byte(b)) == 1
+}
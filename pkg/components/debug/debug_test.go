@@ -0,0 +1,98 @@
+package debug
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestServer(t *testing.T) {
+	s := NewServer(&Options{Token: "secret", Username: "admin", Password: "pass", Profiles: []string{"heap", "goroutine"}})
+	handler := s.Handler()
+
+	serve := func(r *http.Request) int {
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, r)
+		return rec.Code
+	}
+
+	// 令牌或 basic auth 满足其一即可
+	if code := serve(httptest.NewRequest(http.MethodGet, "/debug/pprof/heap", nil)); code != http.StatusUnauthorized {
+		t.Errorf("Expected 401 without credentials, got %d", code)
+	}
+	if code := serve(httptest.NewRequest(http.MethodGet, "/debug/pprof/heap?token=secret", nil)); code != http.StatusOK {
+		t.Errorf("Expected 200 with token, got %d", code)
+	}
+	req := httptest.NewRequest(http.MethodGet, "/debug/pprof/goroutine", nil)
+	req.SetBasicAuth("admin", "pass")
+	if code := serve(req); code != http.StatusOK {
+		t.Errorf("Expected 200 with basic auth, got %d", code)
+	}
+
+	// 不在白名单中的 profile
+	req = httptest.NewRequest(http.MethodGet, "/debug/pprof/cmdline", nil)
+	req.Header.Set("Authorization", "Bearer secret")
+	if code := serve(req); code != http.StatusForbidden {
+		t.Errorf("Expected 403 for cmdline, got %d", code)
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/debug/vars", nil)
+	req.Header.Set("Authorization", "Bearer secret")
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	var snapshot map[string]any
+	if err := json.Unmarshal(rec.Body.Bytes(), &snapshot); err != nil {
+		t.Fatalf("Invalid /debug/vars response: %v", err)
+	}
+	if _, ok := snapshot["goroutines"]; !ok {
+		t.Errorf("Expected goroutines in snapshot, got %v", snapshot)
+	}
+	if _, ok := snapshot["memory"]; !ok {
+		t.Errorf("Expected memory in snapshot, got %v", snapshot)
+	}
+}
+
+func TestContinuous(t *testing.T) {
+	dir := t.TempDir()
+	s := NewServer(&Options{Continuous: ContinuousOptions{Dir: dir, MaxFiles: 2}})
+
+	// 已经结束的 ctx 使 CPU 采样立即写入
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	s.collect(ctx)
+	for _, kind := range []string{"heap", "cpu"} {
+		files, _ := filepath.Glob(filepath.Join(dir, kind+"-*.pprof"))
+		if len(files) != 1 {
+			t.Errorf("Expected 1 %s profile, got %v", kind, files)
+		}
+	}
+
+	// 只保留最新的 MaxFiles 个文件
+	for _, name := range []string{"heap-20260101-000000.pprof", "heap-20260102-000000.pprof"} {
+		if err := os.WriteFile(filepath.Join(dir, name), nil, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := s.rotate("heap"); err != nil {
+		t.Fatal(err)
+	}
+	files, _ := filepath.Glob(filepath.Join(dir, "heap-*.pprof"))
+	if len(files) != 2 || filepath.Base(files[0]) != "heap-20260102-000000.pprof" {
+		t.Errorf("Unexpected files after rotate: %v", files)
+	}
+
+	if err := s.StartContinuous(); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.StartContinuous(); err == nil {
+		t.Error("Expected error when starting twice")
+	}
+	s.StopContinuous()
+	if s.ContinuousRunning() {
+		t.Error("Expected continuous profiling to be stopped")
+	}
+}
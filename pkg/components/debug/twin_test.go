@@ -0,0 +1,17 @@
+package debug
+
+import (
+	"testing"
+
+	"github.com/stones-hub/taurus-pro-core/pkg/components/internal/twintest"
+)
+
+func TestMatchesTemplate(t *testing.T) {
+	twintest.Compare(t, "debug.go", twintest.Template("debug.gotmpl"), map[string]string{
+		"DebugOptions":           "Options",
+		"DebugContinuousOptions": "ContinuousOptions",
+		"DebugServer":            "Server",
+		"NewDebugServer":         "NewServer",
+		"debugSecureEqual":       "secureEqual",
+	})
+}
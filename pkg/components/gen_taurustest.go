@@ -0,0 +1,132 @@
+package components
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/template"
+
+	"github.com/stones-hub/taurus-pro-core/pkg/components/types"
+)
+
+// internal/taurustest/components.go 模板
+const taurustestTemplate = `package taurustest
+
+import (
+	"fmt"
+	"testing"
+
+	"{{.Module}}/internal/taurus"
+	"github.com/stones-hub/taurus-pro-config/pkg/config"
+{{- range .Imports}}
+	{{if hasAlias .}}{{getAlias .}} "{{getPath .}}"{{else}}"{{.}}"{{end}}
+{{- end}}
+)
+
+// buildComponents 构建测试组件：核心组件使用真实实现，其余组件使用各自的测试实现，没有测试实现的组件为 nil
+// 服务组件只注册到生命周期管理器，测试中不会启动，也不会监听端口
+func buildComponents(t testing.TB, cfg *config.Config, h *Harness) (*taurus.Components, error) {
+	c := &taurus.Components{
+		Config:         cfg,
+		ConfigAccessor: taurus.ProvideConfigAccessorComponent(cfg),
+		Lifecycle:      taurus.ProvideLifecycleComponent(cfg),
+		Health:         taurus.ProvideHealthComponent(cfg),
+		Instrument:     taurus.ProvideInstrumentComponent(),
+	}
+	c.Reload = taurus.ProvideReloadComponent(c.ConfigAccessor, &taurus.ConfigOptions{
+		ConfigPath: h.opts.ConfigPath,
+		Env:        h.opts.Env,
+	})
+
+	var err error
+{{- range .Fields}}
+	if c.{{.Name}}, err = {{.ProviderName}}(t, c, h); err != nil {
+		return nil, fmt.Errorf("{{.Name}}: %v", err)
+	}
+{{- end}}
+	return c, nil
+}
+{{- range .Providers}}
+
+{{.}}
+{{- end}}
+`
+
+// GenerateComponentTest 根据选择的组件生成 internal/taurustest/components.go，
+// 提供了测试实现（Wire.TestProvider）的组件在 taurustest.New 中使用测试实现构建
+func GenerateComponentTest(components []types.Component, outputPath, module string) error {
+	data := struct {
+		Module    string
+		Imports   []string
+		Fields    []struct{ Name, ProviderName string }
+		Providers []string
+	}{Module: module}
+
+	// 模板中固定导入的包需要跳过
+	imported := map[string]bool{
+		"fmt":                       true,
+		"testing":                   true,
+		module + "/internal/taurus": true,
+		"github.com/stones-hub/taurus-pro-config/pkg/config": true,
+	}
+
+	for _, comp := range components {
+		if !comp.IsCustom {
+			continue
+		}
+		for _, wire := range comp.Wire {
+			if wire.TestProviderName == "" {
+				continue
+			}
+
+			for _, path := range wire.TestRequirePath {
+				if !imported[path] {
+					imported[path] = true
+					data.Imports = append(data.Imports, path)
+				}
+			}
+
+			data.Fields = append(data.Fields, struct{ Name, ProviderName string }{
+				Name:         wire.Name,
+				ProviderName: wire.TestProviderName,
+			})
+
+			tmpl, err := template.New("test_provider").Parse(wire.TestProvider)
+			if err != nil {
+				return fmt.Errorf("解析 TestProvider 模板失败: %v", err)
+			}
+			var provider strings.Builder
+			if err := tmpl.Execute(&provider, wire); err != nil {
+				return fmt.Errorf("执行 TestProvider 模板失败: %v", err)
+			}
+			data.Providers = append(data.Providers, provider.String())
+		}
+	}
+
+	tmpl, err := template.New("taurustest").Funcs(template.FuncMap{
+		"hasAlias": hasAlias,
+		"getAlias": getAlias,
+		"getPath":  getPath,
+	}).Parse(taurustestTemplate)
+	if err != nil {
+		return fmt.Errorf("解析模板失败: %v", err)
+	}
+
+	if err := os.MkdirAll(outputPath, 0755); err != nil {
+		return fmt.Errorf("创建 %s 失败: %v", outputPath, err)
+	}
+	f, err := os.Create(filepath.Join(outputPath, "components.go"))
+	if err != nil {
+		return fmt.Errorf("创建 components.go 失败: %v", err)
+	}
+	defer f.Close()
+
+	if err := tmpl.Execute(f, data); err != nil {
+		return fmt.Errorf("执行模板失败: %v", err)
+	}
+
+	log.Println("生成 taurustest components.go 成功")
+	return nil
+}
@@ -19,6 +19,7 @@ package taurus
 
 import (
 	"fmt"
+	"sync/atomic"
 	"github.com/google/wire"
 	"github.com/stones-hub/taurus-pro-config/pkg/config"
 {{- range .ComponentImports}}
@@ -37,7 +38,14 @@ type ConfigOptions struct {
 
 // Components 组件容器
 type Components struct {
-	Config *config.Config
+	Config     *config.Config
+	// ConfigAccessor 最近一次加载成功的配置，Config 是启动时加载的配置，重载时不会被修改
+	ConfigAccessor *ConfigAccessor
+	Lifecycle  *LifecycleManager
+	Health     *HealthRegistry
+	Instrument *Instrumentation
+	Debug      *DebugServer
+	Reload     *ConfigReloader
 {{- range .ComponentFields}}
 	{{.Name}} {{.Type}}
 {{- end}}
@@ -52,6 +60,92 @@ func ProvideConfigComponent(opts *ConfigOptions) (*config.Config, error) {
 	return configComponent, nil
 }
 
+// ConfigAccessor 可重载的配置，SIGHUP 时把配置加载到新的实例中，成功后原子替换
+// 正在使用的实例不会被修改，处理中的请求读取到的配置前后一致；重载回调与需要热更新的代码通过 Load 读取新配置
+type ConfigAccessor struct {
+	current atomic.Pointer[config.Config]
+}
+
+// Load 返回最近一次加载成功的配置
+func (a *ConfigAccessor) Load() *config.Config {
+	return a.current.Load()
+}
+
+// Store 替换当前的配置，由配置重载器在加载成功后调用
+func (a *ConfigAccessor) Store(cfg *config.Config) {
+	a.current.Store(cfg)
+}
+
+// ProvideConfigAccessorComponent 注入可重载的配置，初始为启动时加载的配置
+func ProvideConfigAccessorComponent(cfg *config.Config) *ConfigAccessor {
+	configs := &ConfigAccessor{}
+	configs.Store(cfg)
+	return configs
+}
+
+// ProvideLifecycleComponent 注入生命周期管理器，服务组件在 Provider 中注册自己的启动与停止
+// 关闭阶段的时间预算来自 shutdown 配置，排空服务的阶段未配置时使用 http.shutdown_timeout
+func ProvideLifecycleComponent(cfg *config.Config) *LifecycleManager {
+	lc := NewLifecycleManager()
+	serverTimeout := cfg.GetInt("shutdown.server")
+	if serverTimeout <= 0 {
+		serverTimeout = cfg.GetInt("http.shutdown_timeout")
+	}
+	lc.SetPhaseTimeout(LifecyclePhaseDeregister, cfg.GetInt("shutdown.deregister"))
+	lc.SetPhaseTimeout(LifecyclePhaseServer, serverTimeout)
+	lc.SetPhaseTimeout(LifecyclePhaseWorker, cfg.GetInt("shutdown.worker"))
+	lc.SetPhaseTimeout(LifecyclePhaseResource, cfg.GetInt("shutdown.resource"))
+	return lc
+}
+
+// ProvideHealthComponent 注入健康检查注册表，组件在 Provider 中注册自己的健康检查
+func ProvideHealthComponent(cfg *config.Config) *HealthRegistry {
+	return NewHealthRegistry(cfg.GetInt("health.timeout"))
+}
+
+// ProvideInstrumentComponent 注入埋点注册表，组件在 Provider 中发布可埋点的资源，metrics 等组件订阅后加上埋点
+func ProvideInstrumentComponent() *Instrumentation {
+	return NewInstrumentation()
+}
+
+// ProvideDebugComponent 注入调试服务(pprof、/debug/vars、持续采样)，由生命周期管理器启动与停止，未启用时为 nil
+func ProvideDebugComponent(cfg *config.Config, lc *LifecycleManager) *DebugServer {
+	if !cfg.GetBool("debug.enable") {
+		return nil
+	}
+
+	debugServer := NewDebugServer(&DebugOptions{
+		Address:  cfg.GetString("debug.address"),
+		Username: cfg.GetString("debug.auth.username"),
+		Password: cfg.GetString("debug.auth.password"),
+		Token:    cfg.GetString("debug.auth.token"),
+		Profiles: cfg.GetStringSlice("debug.profiles"),
+		Continuous: DebugContinuousOptions{
+			Enable:      cfg.GetBool("debug.continuous.enable"),
+			Dir:         cfg.GetString("debug.continuous.dir"),
+			Interval:    cfg.GetInt("debug.continuous.interval"),
+			CPUDuration: cfg.GetInt("debug.continuous.cpu_duration"),
+			MaxFiles:    cfg.GetInt("debug.continuous.max_files"),
+		},
+	})
+	lc.Register("debug", debugServer)
+	lc.Watch("debug", debugServer.Errors())
+	return debugServer
+}
+
+// ProvideReloadComponent 注入配置重载器，收到 SIGHUP 时重新读取配置目录与 env 文件，组件在 Provider 中注册可以热更新的配置
+func ProvideReloadComponent(configs *ConfigAccessor, opts *ConfigOptions) *ConfigReloader {
+	return NewConfigReloader(configs.Load().ToJSONString(), func() (string, error) {
+		// 加载到新的实例中，配置有误时正在使用的配置保持不变；成功后整体替换，不会修改其他 goroutine 正在读取的实例
+		next := config.New()
+		if err := next.Initialize(opts.ConfigPath, opts.Env); err != nil {
+			return "", fmt.Errorf("failed to reload config: %v", err)
+		}
+		configs.Store(next)
+		return next.ToJSONString(), nil
+	})
+}
+
 {{- range .ComponentProviders}}
 {{.Provider}}
 
@@ -62,6 +156,17 @@ func buildComponents(opts *ConfigOptions) (*Components, func(), error) {
 	wire.Build(
 		// 配置组件
 		ProvideConfigComponent,
+		ProvideConfigAccessorComponent,
+		// 生命周期管理器
+		ProvideLifecycleComponent,
+		// 健康检查
+		ProvideHealthComponent,
+		// 埋点注册表
+		ProvideInstrumentComponent,
+		// 调试服务
+		ProvideDebugComponent,
+		// 配置重载
+		ProvideReloadComponent,
 
 		// 组件提供者
 {{- range .ComponentProviders}}
@@ -112,15 +217,31 @@ func GenerateComponentWire(components []types.Component, outputPath string) erro
 		}
 	}
 
+	// 多个组件可能依赖同一个包，如 log、time、context，重复导入会导致编译失败
+	// wire.go 模板中固定导入的包同样需要跳过
+	imported := map[string]bool{
+		"fmt":                    true,
+		"sync/atomic":            true,
+		"github.com/google/wire": true,
+		"github.com/stones-hub/taurus-pro-config/pkg/config": true,
+	}
+
 	// 处理每个组件
 	for _, comp := range components {
 		if comp.IsCustom && len(comp.Wire) > 0 {
 			for _, wire := range comp.Wire {
 				// 添加导入
+				paths := make([]string, 0, len(wire.RequirePath))
+				for _, path := range wire.RequirePath {
+					if !imported[path] {
+						imported[path] = true
+						paths = append(paths, path)
+					}
+				}
 				componentData.ComponentImports = append(componentData.ComponentImports, struct {
 					Path []string
 				}{
-					Path: wire.RequirePath,
+					Path: paths,
 				})
 
 				// 添加字段
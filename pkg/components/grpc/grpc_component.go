@@ -1,20 +1,30 @@
 package grpc
 
 import (
+	"context"
 	"crypto/tls"
 	"crypto/x509"
 	"fmt"
+	"log"
 	"os"
+	"sync"
 	"time"
 
+	"google.golang.org/grpc"
+	grpchealth "google.golang.org/grpc/health"
+	"google.golang.org/grpc/health/grpc_health_v1"
 	"google.golang.org/grpc/keepalive"
+	"google.golang.org/grpc/stats"
 
 	"github.com/stones-hub/taurus-pro-config/pkg/config"
+	"github.com/stones-hub/taurus-pro-core/pkg/components/health"
+	"github.com/stones-hub/taurus-pro-core/pkg/components/instrument"
+	"github.com/stones-hub/taurus-pro-core/pkg/components/lifecycle"
 	"github.com/stones-hub/taurus-pro-core/pkg/components/types"
 	"github.com/stones-hub/taurus-pro-grpc/pkg/grpc/server"
 )
 
-func ProvideGrpcComponent(cfg *config.Config) (*server.Server, func(), error) {
+func ProvideGrpcComponent(cfg *config.Config, lc *lifecycle.Manager, hc *health.Registry, inst *instrument.Registry) (*server.Server, func(), error) {
 
 	if !cfg.GetBool("grpc.enable") {
 		return nil, func() {}, nil
@@ -62,15 +72,133 @@ func ProvideGrpcComponent(cfg *config.Config) (*server.Server, func(), error) {
 
 	}
 
-	return server.NewServer(options...)
+	// 埋点注册表中的拦截器（如 metrics）在调用时按注册顺序执行，订阅方晚于 grpc 服务初始化也能生效
+	var (
+		unaryInterceptors  []grpc.UnaryServerInterceptor
+		streamInterceptors []grpc.StreamServerInterceptor
+	)
+	inst.Subscribe(instrument.KindGrpcUnary, func(name string, resource any) {
+		if interceptor, ok := resource.(grpc.UnaryServerInterceptor); ok {
+			unaryInterceptors = append(unaryInterceptors, interceptor)
+		}
+	})
+	inst.Subscribe(instrument.KindGrpcStream, func(name string, resource any) {
+		if interceptor, ok := resource.(grpc.StreamServerInterceptor); ok {
+			streamInterceptors = append(streamInterceptors, interceptor)
+		}
+	})
+	// stats.Handler（如 otelgrpc.NewServerHandler()）由最外层的拦截器驱动，taurus-pro-grpc 的选项不支持 grpc.StatsHandler
+	var statsHandlers []stats.Handler
+	inst.Subscribe(instrument.KindGrpcStats, func(name string, resource any) {
+		if handler, ok := resource.(stats.Handler); ok {
+			statsHandlers = append(statsHandlers, handler)
+		}
+	})
+	options = append(options,
+		server.WithUnaryInterceptor(func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+			for i := len(unaryInterceptors) - 1; i >= 0; i-- {
+				interceptor, next := unaryInterceptors[i], handler
+				handler = func(ctx context.Context, req any) (any, error) {
+					return interceptor(ctx, req, info, next)
+				}
+			}
+			ctx, end := grpcStatsBegin(ctx, statsHandlers, info.FullMethod, false, false)
+			resp, err := handler(ctx, req)
+			end(err)
+			return resp, err
+		}),
+		server.WithStreamInterceptor(func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+			for i := len(streamInterceptors) - 1; i >= 0; i-- {
+				interceptor, next := streamInterceptors[i], handler
+				handler = func(srv any, ss grpc.ServerStream) error {
+					return interceptor(srv, ss, info, next)
+				}
+			}
+			ctx, end := grpcStatsBegin(ss.Context(), statsHandlers, info.FullMethod, info.IsClientStream, info.IsServerStream)
+			if ctx != ss.Context() {
+				ss = &grpcServerStream{ServerStream: ss, ctx: ctx}
+			}
+			err := handler(srv, ss)
+			end(err)
+			return err
+		}),
+	)
+
+	grpcServer, cleanup, err := server.NewServer(options...)
+	if err != nil {
+		return nil, func() {}, err
+	}
+
+	// 标准的 grpc 健康检查服务，状态来自健康检查注册表，与 http 的 /readyz 一致
+	healthServer := grpchealth.NewServer()
+	grpc_health_v1.RegisterHealthServer(grpcServer, healthServer)
+	watchCtx, cancelWatch := context.WithCancel(context.Background())
+
+	// 生命周期管理器停止 grpc 服务后，wire 的清理函数不再重复停止
+	stop := sync.OnceFunc(func() {
+		cancelWatch()
+		cleanup()
+	})
+	lc.Register("grpc", &lifecycle.Hook{
+		OnStart: func(ctx context.Context) error {
+			log.Printf("%s🔗 -> Grpc server start on %s. %s\n", "\033[32m", cfg.GetString("grpc.address"), "\033[0m")
+			lc.Go("grpc", grpcServer.Start)
+			go hc.Watch(watchCtx, time.Duration(cfg.GetInt("health.interval"))*time.Second, func(report *health.Report) {
+				status := grpc_health_v1.HealthCheckResponse_NOT_SERVING
+				if report.Healthy() {
+					status = grpc_health_v1.HealthCheckResponse_SERVING
+				}
+				healthServer.SetServingStatus("", status)
+			})
+			return nil
+		},
+		OnStop: func(ctx context.Context) error {
+			// 先将健康状态置为 NOT_SERVING，客户端不再发送新的请求
+			healthServer.Shutdown()
+			stop()
+			return nil
+		},
+	})
+
+	return grpcServer, stop, nil
+}
+
+// grpcStatsBegin 按注册顺序回调 stats.Handler 的 TagRPC 与 Begin，返回的函数在调用结束时回调 End
+func grpcStatsBegin(ctx context.Context, handlers []stats.Handler, fullMethod string, clientStream, serverStream bool) (context.Context, func(err error)) {
+	if len(handlers) == 0 {
+		return ctx, func(error) {}
+	}
+	begin := &stats.Begin{BeginTime: time.Now(), IsClientStream: clientStream, IsServerStream: serverStream}
+	contexts := make([]context.Context, len(handlers))
+	for i, handler := range handlers {
+		ctx = handler.TagRPC(ctx, &stats.RPCTagInfo{FullMethodName: fullMethod})
+		contexts[i] = ctx
+		handler.HandleRPC(ctx, begin)
+	}
+	return ctx, func(err error) {
+		end := &stats.End{BeginTime: begin.BeginTime, EndTime: time.Now(), Error: err}
+		for i := len(handlers) - 1; i >= 0; i-- {
+			handlers[i].HandleRPC(contexts[i], end)
+		}
+	}
+}
+
+// grpcServerStream 将 stats.Handler 返回的上下文传给流式方法
+type grpcServerStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *grpcServerStream) Context() context.Context {
+	return s.ctx
 }
 
 var grpcWire = &types.Wire{
-	RequirePath:  []string{"gRPCServer@github.com/stones-hub/taurus-pro-grpc/pkg/grpc/server", "time", "crypto/tls", "crypto/x509", "os", "fmt", "google.golang.org/grpc/keepalive"},
+	RequirePath:  []string{"gRPCServer@github.com/stones-hub/taurus-pro-grpc/pkg/grpc/server", "time", "crypto/tls", "crypto/x509", "os", "fmt", "log", "sync", "context", "grpchealth@google.golang.org/grpc/health", "google.golang.org/grpc", "google.golang.org/grpc/health/grpc_health_v1", "google.golang.org/grpc/keepalive", "google.golang.org/grpc/stats"},
 	Name:         "GRPC",
 	Type:         "*gRPCServer.Server",
 	ProviderName: "ProvideGrpcComponent",
-	Provider: `func {{.ProviderName}}(cfg *config.Config) ({{.Type}}, func(), error) {
+	Provider: `func {{.ProviderName}}(cfg *config.Config, lc *LifecycleManager, hc *HealthRegistry, inst *Instrumentation) ({{.Type}}, func(), error) {
 
 	if !cfg.GetBool("grpc.enable") {
 		return nil, func() {}, nil
@@ -118,7 +246,125 @@ var grpcWire = &types.Wire{
 
 	}
 
-	return gRPCServer.NewServer(options...)
+	// 埋点注册表中的拦截器（如 metrics）在调用时按注册顺序执行，订阅方晚于 grpc 服务初始化也能生效
+	var (
+		unaryInterceptors  []grpc.UnaryServerInterceptor
+		streamInterceptors []grpc.StreamServerInterceptor
+	)
+	inst.Subscribe(InstrumentKindGrpcUnary, func(name string, resource any) {
+		if interceptor, ok := resource.(grpc.UnaryServerInterceptor); ok {
+			unaryInterceptors = append(unaryInterceptors, interceptor)
+		}
+	})
+	inst.Subscribe(InstrumentKindGrpcStream, func(name string, resource any) {
+		if interceptor, ok := resource.(grpc.StreamServerInterceptor); ok {
+			streamInterceptors = append(streamInterceptors, interceptor)
+		}
+	})
+	// stats.Handler（如 otelgrpc.NewServerHandler()）由最外层的拦截器驱动，taurus-pro-grpc 的选项不支持 grpc.StatsHandler
+	var statsHandlers []stats.Handler
+	inst.Subscribe(InstrumentKindGrpcStats, func(name string, resource any) {
+		if handler, ok := resource.(stats.Handler); ok {
+			statsHandlers = append(statsHandlers, handler)
+		}
+	})
+	options = append(options,
+		gRPCServer.WithUnaryInterceptor(func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+			for i := len(unaryInterceptors) - 1; i >= 0; i-- {
+				interceptor, next := unaryInterceptors[i], handler
+				handler = func(ctx context.Context, req any) (any, error) {
+					return interceptor(ctx, req, info, next)
+				}
+			}
+			ctx, end := grpcStatsBegin(ctx, statsHandlers, info.FullMethod, false, false)
+			resp, err := handler(ctx, req)
+			end(err)
+			return resp, err
+		}),
+		gRPCServer.WithStreamInterceptor(func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+			for i := len(streamInterceptors) - 1; i >= 0; i-- {
+				interceptor, next := streamInterceptors[i], handler
+				handler = func(srv any, ss grpc.ServerStream) error {
+					return interceptor(srv, ss, info, next)
+				}
+			}
+			ctx, end := grpcStatsBegin(ss.Context(), statsHandlers, info.FullMethod, info.IsClientStream, info.IsServerStream)
+			if ctx != ss.Context() {
+				ss = &grpcServerStream{ServerStream: ss, ctx: ctx}
+			}
+			err := handler(srv, ss)
+			end(err)
+			return err
+		}),
+	)
+
+	grpcServer, cleanup, err := gRPCServer.NewServer(options...)
+	if err != nil {
+		return nil, func() {}, err
+	}
+
+	// 标准的 grpc 健康检查服务，状态来自健康检查注册表，与 http 的 /readyz 一致
+	healthServer := grpchealth.NewServer()
+	grpc_health_v1.RegisterHealthServer(grpcServer, healthServer)
+	watchCtx, cancelWatch := context.WithCancel(context.Background())
+
+	// 生命周期管理器停止 grpc 服务后，wire 的清理函数不再重复停止
+	stop := sync.OnceFunc(func() {
+		cancelWatch()
+		cleanup()
+	})
+	lc.Register("grpc", &LifecycleHook{
+		OnStart: func(ctx context.Context) error {
+			log.Printf("%s🔗 -> Grpc server start on %s. %s\n", "\033[32m", cfg.GetString("grpc.address"), "\033[0m")
+			lc.Go("grpc", grpcServer.Start)
+			go hc.Watch(watchCtx, time.Duration(cfg.GetInt("health.interval"))*time.Second, func(report *HealthReport) {
+				status := grpc_health_v1.HealthCheckResponse_NOT_SERVING
+				if report.Healthy() {
+					status = grpc_health_v1.HealthCheckResponse_SERVING
+				}
+				healthServer.SetServingStatus("", status)
+			})
+			return nil
+		},
+		OnStop: func(ctx context.Context) error {
+			// 先将健康状态置为 NOT_SERVING，客户端不再发送新的请求
+			healthServer.Shutdown()
+			stop()
+			return nil
+		},
+	})
+
+	return grpcServer, stop, nil
+}
+
+// grpcStatsBegin 按注册顺序回调 stats.Handler 的 TagRPC 与 Begin，返回的函数在调用结束时回调 End
+func grpcStatsBegin(ctx context.Context, handlers []stats.Handler, fullMethod string, clientStream, serverStream bool) (context.Context, func(err error)) {
+	if len(handlers) == 0 {
+		return ctx, func(error) {}
+	}
+	begin := &stats.Begin{BeginTime: time.Now(), IsClientStream: clientStream, IsServerStream: serverStream}
+	contexts := make([]context.Context, len(handlers))
+	for i, handler := range handlers {
+		ctx = handler.TagRPC(ctx, &stats.RPCTagInfo{FullMethodName: fullMethod})
+		contexts[i] = ctx
+		handler.HandleRPC(ctx, begin)
+	}
+	return ctx, func(err error) {
+		end := &stats.End{BeginTime: begin.BeginTime, EndTime: time.Now(), Error: err}
+		for i := len(handlers) - 1; i >= 0; i-- {
+			handlers[i].HandleRPC(contexts[i], end)
+		}
+	}
+}
+
+// grpcServerStream 将 stats.Handler 返回的上下文传给流式方法
+type grpcServerStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *grpcServerStream) Context() context.Context {
+	return s.ctx
 }`,
 }
 
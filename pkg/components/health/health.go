@@ -0,0 +1,199 @@
+// Package health 是生成项目中 internal/taurus/health.go 的对应实现，供组件的 Go 版本 Provider 使用
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// 健康检查状态
+const (
+	StatusUp   = "up"
+	StatusDown = "down"
+)
+
+// Check 健康检查函数，返回 nil 表示健康
+type Check func(ctx context.Context) error
+
+// CheckResult 单个健康检查的结果
+type CheckResult struct {
+	Status   string `json:"status"`
+	Critical bool   `json:"critical"` // 关键检查失败时服务不可用
+	Error    string `json:"error,omitempty"`
+	Duration string `json:"duration"`
+}
+
+// Report 所有健康检查的汇总报告
+type Report struct {
+	Status    string                  `json:"status"` // 所有关键检查都通过时为 up
+	Ready     bool                    `json:"ready"`  // 服务组件是否已经全部启动且未进入关闭流程
+	Checks    map[string]*CheckResult `json:"checks"`
+	Timestamp time.Time               `json:"timestamp"`
+}
+
+// Healthy 服务是否可以对外提供服务，readyz、grpc 健康服务、consul TTL 检查都以此为准
+func (r *Report) Healthy() bool {
+	return r.Ready && r.Status == StatusUp
+}
+
+// checkEntry 注册的健康检查
+type checkEntry struct {
+	name     string
+	check    Check
+	critical bool
+}
+
+// Registry 健康检查注册表
+// 组件在 Provider 中注册自己的健康检查（如数据库 ping、redis PING、milvus 连接状态），
+// http 的 /healthz、/readyz、/health，grpc 健康服务以及 consul TTL 检查共用同一份检查结果
+type Registry struct {
+	mu      sync.RWMutex
+	entries []*checkEntry
+	timeout time.Duration
+	ready   atomic.Bool
+}
+
+// NewRegistry 创建健康检查注册表，timeout 为单个检查的超时时间(秒)，默认 3 秒
+func NewRegistry(timeout int) *Registry {
+	if timeout <= 0 {
+		timeout = 3
+	}
+	return &Registry{timeout: time.Duration(timeout) * time.Second}
+}
+
+// Register 注册关键健康检查，检查失败时服务不可用
+func (h *Registry) Register(name string, check Check) {
+	h.register(name, check, true)
+}
+
+// RegisterOptional 注册非关键健康检查，检查失败只体现在报告中，不影响服务是否可用
+func (h *Registry) RegisterOptional(name string, check Check) {
+	h.register(name, check, false)
+}
+
+func (h *Registry) register(name string, check Check, critical bool) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.entries = append(h.entries, &checkEntry{name: name, check: check, critical: critical})
+}
+
+// SetReady 设置服务组件是否已经全部启动，启动完成后设置为 true，开始关闭时设置为 false
+func (h *Registry) SetReady(ready bool) {
+	h.ready.Store(ready)
+}
+
+// Check 并发执行所有健康检查并生成报告
+func (h *Registry) Check(ctx context.Context) *Report {
+	h.mu.RLock()
+	entries := append([]*checkEntry(nil), h.entries...)
+	h.mu.RUnlock()
+
+	report := &Report{
+		Status:    StatusUp,
+		Ready:     h.ready.Load(),
+		Checks:    make(map[string]*CheckResult, len(entries)),
+		Timestamp: time.Now(),
+	}
+
+	var (
+		mu sync.Mutex
+		wg sync.WaitGroup
+	)
+	for _, entry := range entries {
+		wg.Add(1)
+		go func(entry *checkEntry) {
+			defer wg.Done()
+			result := h.run(ctx, entry)
+
+			mu.Lock()
+			defer mu.Unlock()
+			report.Checks[entry.name] = result
+			if result.Status != StatusUp && entry.critical {
+				report.Status = StatusDown
+			}
+		}(entry)
+	}
+	wg.Wait()
+
+	return report
+}
+
+// run 在超时时间内执行单个健康检查
+func (h *Registry) run(ctx context.Context, entry *checkEntry) (result *CheckResult) {
+	ctx, cancel := context.WithTimeout(ctx, h.timeout)
+	defer cancel()
+
+	start := time.Now()
+	result = &CheckResult{Status: StatusUp, Critical: entry.critical}
+	defer func() {
+		if r := recover(); r != nil {
+			result.Status = StatusDown
+			result.Error = fmt.Sprintf("panic: %v", r)
+		}
+		result.Duration = time.Since(start).String()
+	}()
+
+	if err := entry.check(ctx); err != nil {
+		result.Status = StatusDown
+		result.Error = err.Error()
+	}
+	return result
+}
+
+// Watch 每隔 interval 执行一次健康检查并回调 fn，直到 ctx 结束，启动时立即执行一次
+func (h *Registry) Watch(ctx context.Context, interval time.Duration, fn func(report *Report)) {
+	if interval <= 0 {
+		interval = 10 * time.Second
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		fn(h.Check(ctx))
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// LivenessHandler /healthz 存活检查，进程能够响应请求即为存活，不检查外部依赖，避免依赖故障导致服务被反复重启
+func (h *Registry) LivenessHandler(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, map[string]string{"status": StatusUp})
+}
+
+// ReadinessHandler /readyz 就绪检查，服务组件全部启动且所有关键检查通过时返回 200，否则返回 503
+func (h *Registry) ReadinessHandler(w http.ResponseWriter, r *http.Request) {
+	report := h.Check(r.Context())
+	status := StatusUp
+	if !report.Healthy() {
+		status = StatusDown
+	}
+	writeJSON(w, statusCode(report), map[string]string{"status": status})
+}
+
+// ReportHandler /health 返回详细的健康检查报告，状态码与 /readyz 一致
+func (h *Registry) ReportHandler(w http.ResponseWriter, r *http.Request) {
+	report := h.Check(r.Context())
+	writeJSON(w, statusCode(report), report)
+}
+
+func statusCode(report *Report) int {
+	if report.Healthy() {
+		return http.StatusOK
+	}
+	return http.StatusServiceUnavailable
+}
+
+func writeJSON(w http.ResponseWriter, code int, data any) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(code)
+	json.NewEncoder(w).Encode(data)
+}
@@ -0,0 +1,49 @@
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestRegistry(t *testing.T) {
+	h := NewRegistry(1)
+	h.Register("db:default", func(ctx context.Context) error { return nil })
+	h.RegisterOptional("consul", func(ctx context.Context) error { return errors.New("connection refused") })
+
+	// 服务组件启动之前不可用
+	rec := httptest.NewRecorder()
+	h.ReadinessHandler(rec, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+	if rec.Code != http.StatusServiceUnavailable {
+		t.Fatalf("expected 503 before ready, got %d", rec.Code)
+	}
+
+	// 非关键检查失败不影响可用状态
+	h.SetReady(true)
+	rec = httptest.NewRecorder()
+	h.ReportHandler(rec, httptest.NewRequest(http.MethodGet, "/health", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body)
+	}
+	var report Report
+	if err := json.Unmarshal(rec.Body.Bytes(), &report); err != nil {
+		t.Fatal(err)
+	}
+	if report.Status != StatusUp || report.Checks["consul"].Error != "connection refused" || report.Checks["db:default"].Status != StatusUp {
+		t.Fatalf("unexpected report: %s", rec.Body)
+	}
+
+	// 关键检查失败或 panic 时不可用，存活检查不受影响
+	h.Register("redis", func(ctx context.Context) error { panic("nil client") })
+	if report := h.Check(context.Background()); report.Healthy() || report.Checks["redis"].Error != "panic: nil client" {
+		t.Fatalf("unexpected report: %+v", report.Checks["redis"])
+	}
+	rec = httptest.NewRecorder()
+	h.LivenessHandler(rec, httptest.NewRequest(http.MethodGet, "/healthz", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected liveness 200, got %d", rec.Code)
+	}
+}
@@ -0,0 +1,22 @@
+package health
+
+import (
+	"testing"
+
+	"github.com/stones-hub/taurus-pro-core/pkg/components/internal/twintest"
+)
+
+func TestMatchesTemplate(t *testing.T) {
+	twintest.Compare(t, "health.go", twintest.Template("health.gotmpl"), map[string]string{
+		"HealthCheck":       "Check",
+		"HealthCheckResult": "CheckResult",
+		"HealthReport":      "Report",
+		"healthEntry":       "checkEntry",
+		"HealthRegistry":    "Registry",
+		"NewHealthRegistry": "NewRegistry",
+		"healthStatusCode":  "statusCode",
+		"writeHealthJSON":   "writeJSON",
+		"HealthStatusUp":    "StatusUp",
+		"HealthStatusDown":  "StatusDown",
+	})
+}
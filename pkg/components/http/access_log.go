@@ -0,0 +1,181 @@
+package http
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/rand"
+	"net"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/stones-hub/taurus-pro-common/pkg/util/tnet"
+	"github.com/stones-hub/taurus-pro-config/pkg/config"
+	"github.com/stones-hub/taurus-pro-core/pkg/components/common"
+	"github.com/stones-hub/taurus-pro-http/pkg/router"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// AccessLogEntry 一条访问日志，format 为 json 时按字段名输出
+type AccessLogEntry struct {
+	Method    string  `json:"method"`
+	Path      string  `json:"path"`
+	Status    int     `json:"status"`
+	Latency   float64 `json:"latency_ms"`
+	Bytes     int64   `json:"bytes"`
+	ClientIP  string  `json:"client_ip"`
+	UserID    string  `json:"user_id,omitempty"`
+	RequestID string  `json:"request_id,omitempty"`
+	TraceID   string  `json:"trace_id,omitempty"`
+}
+
+type accessLogKey struct{}
+
+// accessLogState 放入请求上下文，内层中间件通过 SetAccessLogUser 记录用户 ID
+// 超时中间件会在另一个 goroutine 中处理请求，用户 ID 需要原子读写
+type accessLogState struct {
+	user atomic.Value
+}
+
+// SetAccessLogUser 记录请求的用户 ID，写入 access_log 中间件的访问日志，鉴权中间件（如 jwt）验证通过后调用
+// 请求没有经过 access_log 中间件时不做任何事
+func SetAccessLogUser(ctx context.Context, userID string) {
+	if state, ok := ctx.Value(accessLogKey{}).(*accessLogState); ok {
+		state.user.Store(userID)
+	}
+}
+
+// accessLogMiddleware 按 http.access_log 配置把访问日志写入 logx 中名为 logger 的日志
+// 每个请求通过 loggers 读取当前的日志管理器，SIGHUP 重载后写入新的管理器
+// 5xx 以 error、超过 slow 毫秒的慢请求以 warn 记录且总是记录，其余请求按 sample 采样后以 info 记录
+// 放在 request_id 之后、recovery 之前，日志中才有请求 ID 与 panic 时的 500
+func accessLogMiddleware(loggers *common.LoggerAccessor) MiddlewareFactory {
+	return func(cfg *config.Config) (router.MiddlewareFunc, error) {
+		if loggers == nil {
+			return nil, fmt.Errorf("access_log 需要日志组件")
+		}
+		name := cfg.GetString("http.access_log.logger")
+		if name == "" {
+			name = "default"
+		}
+		jsonFormat := cfg.GetString("http.access_log.format") != "text"
+		sample := cfg.GetFloat64("http.access_log.sample")
+		slow := time.Duration(cfg.GetInt("http.access_log.slow")) * time.Millisecond
+		skip := make(map[string]bool)
+		for _, path := range cfg.GetStringSlice("http.access_log.skip") {
+			skip[path] = true
+		}
+
+		return func(next http.Handler) http.Handler {
+			return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if skip[r.URL.Path] {
+					next.ServeHTTP(w, r)
+					return
+				}
+
+				start := time.Now()
+				state := &accessLogState{}
+				rw := &accessLogResponseWriter{ResponseWriter: w, status: http.StatusOK}
+				next.ServeHTTP(rw, r.WithContext(context.WithValue(r.Context(), accessLogKey{}, state)))
+				latency := time.Since(start)
+
+				isSlow := slow > 0 && latency >= slow
+				sampled := sample >= 1 || sample > 0 && rand.Float64() < sample
+				if rw.status < 500 && !isSlow && !sampled {
+					return
+				}
+
+				entry := AccessLogEntry{
+					Method:    r.Method,
+					Path:      r.URL.Path,
+					Status:    rw.status,
+					Latency:   float64(latency.Microseconds()) / 1000,
+					Bytes:     rw.bytes,
+					ClientIP:  tnet.GetRemoteIP(r),
+					RequestID: RequestIDFromContext(r.Context()),
+				}
+				entry.UserID, _ = state.user.Load().(string)
+				if sc := trace.SpanContextFromContext(r.Context()); sc.IsValid() {
+					entry.TraceID = sc.TraceID().String()
+				}
+
+				message := formatAccessLog(entry, jsonFormat)
+				logger := loggers.Load()
+				switch {
+				case rw.status >= 500:
+					logger.LError(name, "%s", message)
+				case isSlow:
+					logger.LWarn(name, "%s", message)
+				default:
+					logger.LInfo(name, "%s", message)
+				}
+			})
+		}, nil
+	}
+}
+
+// formatAccessLog 输出 JSON 对象或 key=value 格式的访问日志
+func formatAccessLog(entry AccessLogEntry, jsonFormat bool) string {
+	if jsonFormat {
+		b, _ := json.Marshal(entry)
+		return string(b)
+	}
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "method=%s path=%q status=%d latency_ms=%.3f bytes=%d client_ip=%s",
+		entry.Method, entry.Path, entry.Status, entry.Latency, entry.Bytes, entry.ClientIP)
+	if entry.UserID != "" {
+		fmt.Fprintf(&sb, " user_id=%q", entry.UserID)
+	}
+	if entry.RequestID != "" {
+		sb.WriteString(" request_id=" + entry.RequestID)
+	}
+	if entry.TraceID != "" {
+		sb.WriteString(" trace_id=" + entry.TraceID)
+	}
+	return sb.String()
+}
+
+// accessLogResponseWriter 记录响应状态码与字节数，保留 Flush 与 Hijack 以支持 SSE 与 websocket
+type accessLogResponseWriter struct {
+	http.ResponseWriter
+	status      int
+	bytes       int64
+	wroteHeader bool
+}
+
+func (w *accessLogResponseWriter) WriteHeader(code int) {
+	if !w.wroteHeader {
+		w.status = code
+		w.wroteHeader = true
+	}
+	w.ResponseWriter.WriteHeader(code)
+}
+
+func (w *accessLogResponseWriter) Write(b []byte) (int, error) {
+	w.wroteHeader = true
+	n, err := w.ResponseWriter.Write(b)
+	w.bytes += int64(n)
+	return n, err
+}
+
+func (w *accessLogResponseWriter) Flush() {
+	if flusher, ok := w.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+func (w *accessLogResponseWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	hijacker, ok := w.ResponseWriter.(http.Hijacker)
+	if !ok {
+		return nil, nil, fmt.Errorf("ResponseWriter 不支持 Hijack")
+	}
+	w.status = http.StatusSwitchingProtocols
+	return hijacker.Hijack()
+}
+
+func (w *accessLogResponseWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
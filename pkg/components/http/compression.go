@@ -0,0 +1,276 @@
+package http
+
+import (
+	"bufio"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/andybalholm/brotli"
+	"github.com/stones-hub/taurus-pro-config/pkg/config"
+	"github.com/stones-hub/taurus-pro-http/pkg/router"
+)
+
+// compressor gzip 与 brotli 的压缩器，放回池中前 Reset 到 io.Discard
+type compressor interface {
+	io.WriteCloser
+	Flush() error
+	Reset(w io.Writer)
+}
+
+// compressionMiddleware 按 http.compression 压缩响应，encodings 或 content_types 为空时不使用
+// 按 encodings 的顺序选择客户端 Accept-Encoding 接受的算法，只压缩 content_types 中的类型且不小于 min_size 字节的响应；
+// 已经编码的响应（Content-Encoding）、206 分段响应、Cache-Control: no-transform 的响应与 websocket 等升级的连接不压缩
+func compressionMiddleware(cfg *config.Config) (router.MiddlewareFunc, error) {
+	encodings := cfg.GetStringSlice("http.compression.encodings")
+	types := cfg.GetStringSlice("http.compression.content_types")
+	if len(encodings) == 0 || len(types) == 0 {
+		return nil, nil
+	}
+	minSize := cfg.GetInt("http.compression.min_size")
+
+	pools := make(map[string]*sync.Pool, len(encodings))
+	for _, encoding := range encodings {
+		switch encoding {
+		case "gzip":
+			pools[encoding] = &sync.Pool{New: func() any {
+				return gzip.NewWriter(io.Discard)
+			}}
+		case "br":
+			pools[encoding] = &sync.Pool{New: func() any {
+				return brotli.NewWriterLevel(io.Discard, brotli.DefaultCompression)
+			}}
+		default:
+			return nil, fmt.Errorf("http.compression.encodings 中不支持的压缩算法: %s", encoding)
+		}
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Method == http.MethodHead || r.Header.Get("Upgrade") != "" {
+				next.ServeHTTP(w, r)
+				return
+			}
+			w.Header().Add("Vary", "Accept-Encoding")
+			encoding := acceptEncoding(r.Header.Get("Accept-Encoding"), encodings)
+			if encoding == "" {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			cw := &compressResponseWriter{
+				ResponseWriter: w,
+				encoding:       encoding,
+				pool:           pools[encoding],
+				minSize:        minSize,
+				types:          types,
+				status:         http.StatusOK,
+			}
+			next.ServeHTTP(cw, r)
+			// 处理请求时 panic 不写出缓冲的响应，由外层的 recovery 返回 500
+			cw.close()
+		})
+	}, nil
+}
+
+// acceptEncoding 按 encodings 的顺序返回 Accept-Encoding 接受（q 大于 0）的第一个算法，都不接受时返回空字符串
+func acceptEncoding(header string, encodings []string) string {
+	if header == "" {
+		return ""
+	}
+	accepted := make(map[string]float64)
+	for _, part := range strings.Split(header, ",") {
+		name, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		q := 1.0
+		if value, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			if parsed, err := strconv.ParseFloat(value, 64); err == nil {
+				q = parsed
+			}
+		}
+		accepted[strings.ToLower(strings.TrimSpace(name))] = q
+	}
+	for _, encoding := range encodings {
+		q, ok := accepted[encoding]
+		if !ok {
+			q, ok = accepted["*"]
+		}
+		if ok && q > 0 {
+			return encoding
+		}
+	}
+	return ""
+}
+
+// compressibleType 响应的 Content-Type 是否在 types 中，types 中的 text/* 匹配所有 text 类型
+func compressibleType(contentType string, types []string) bool {
+	mediaType, _, _ := strings.Cut(contentType, ";")
+	mediaType = strings.ToLower(strings.TrimSpace(mediaType))
+	for _, t := range types {
+		if prefix, ok := strings.CutSuffix(t, "*"); ok {
+			if strings.HasPrefix(mediaType, prefix) {
+				return true
+			}
+		} else if mediaType == t {
+			return true
+		}
+	}
+	return false
+}
+
+// compressResponseWriter 缓冲响应的前 minSize 字节，之后根据状态码、响应头与大小决定是否压缩
+// 保留 Flush（SSE 等流式响应立即开始压缩）与 Hijack
+type compressResponseWriter struct {
+	http.ResponseWriter
+	encoding string
+	pool     *sync.Pool
+	minSize  int
+	types    []string
+
+	status      int
+	wroteHeader bool
+	started     bool       // 已写出响应头
+	compressor  compressor // 为 nil 时不压缩
+	buf         []byte
+	hijacked    bool
+}
+
+func (w *compressResponseWriter) WriteHeader(code int) {
+	// 1xx 信息响应（如 103 Early Hints）直接写出
+	if code >= 100 && code < 200 {
+		w.ResponseWriter.WriteHeader(code)
+		return
+	}
+	if w.wroteHeader {
+		return
+	}
+	w.status = code
+	w.wroteHeader = true
+	if code == http.StatusNoContent || code == http.StatusNotModified {
+		_ = w.start(false)
+	}
+}
+
+func (w *compressResponseWriter) Write(b []byte) (int, error) {
+	if !w.wroteHeader {
+		w.WriteHeader(http.StatusOK)
+	}
+	if w.started {
+		if w.compressor != nil {
+			return w.compressor.Write(b)
+		}
+		return w.ResponseWriter.Write(b)
+	}
+
+	w.buf = append(w.buf, b...)
+	var err error
+	switch contentType := w.Header().Get("Content-Type"); {
+	case contentType != "" && !compressibleType(contentType, w.types):
+		// 不压缩的类型（如下载的文件）不需要缓冲
+		err = w.start(false)
+	case len(w.buf) >= w.minSize:
+		err = w.start(w.compressible())
+	}
+	if err != nil {
+		return 0, err
+	}
+	return len(b), nil
+}
+
+// compressible 根据状态码、响应头与缓冲的数据判断是否压缩，没有 Content-Type 时按缓冲的数据检测并设置，
+// 避免标准库检测压缩后的数据
+func (w *compressResponseWriter) compressible() bool {
+	h := w.Header()
+	if w.status == http.StatusPartialContent || h.Get("Content-Encoding") != "" || h.Get("Content-Range") != "" ||
+		strings.Contains(h.Get("Cache-Control"), "no-transform") {
+		return false
+	}
+	contentType := h.Get("Content-Type")
+	if contentType == "" {
+		if len(w.buf) == 0 {
+			return false
+		}
+		contentType = http.DetectContentType(w.buf)
+		h.Set("Content-Type", contentType)
+	}
+	return compressibleType(contentType, w.types)
+}
+
+// start 写出响应头与缓冲的数据，compress 为 true 时之后的数据经过压缩器写出
+func (w *compressResponseWriter) start(compress bool) error {
+	w.started = true
+	if compress {
+		h := w.Header()
+		h.Del("Content-Length")
+		h.Set("Content-Encoding", w.encoding)
+		w.compressor = w.pool.Get().(compressor)
+		w.compressor.Reset(w.ResponseWriter)
+	}
+	w.ResponseWriter.WriteHeader(w.status)
+
+	buf := w.buf
+	w.buf = nil
+	if len(buf) == 0 {
+		return nil
+	}
+	var err error
+	if w.compressor != nil {
+		_, err = w.compressor.Write(buf)
+	} else {
+		_, err = w.ResponseWriter.Write(buf)
+	}
+	return err
+}
+
+// close 写出小于 minSize 的响应，结束压缩并把压缩器放回池中
+func (w *compressResponseWriter) close() {
+	if w.hijacked {
+		return
+	}
+	if !w.started {
+		if !w.wroteHeader && len(w.buf) == 0 {
+			return
+		}
+		_ = w.start(false)
+	}
+	if w.compressor != nil {
+		_ = w.compressor.Close()
+		w.compressor.Reset(io.Discard)
+		w.pool.Put(w.compressor)
+		w.compressor = nil
+	}
+}
+
+func (w *compressResponseWriter) Flush() {
+	if !w.started {
+		if !w.wroteHeader {
+			w.WriteHeader(http.StatusOK)
+		}
+		if err := w.start(w.compressible()); err != nil {
+			return
+		}
+	}
+	if w.compressor != nil {
+		_ = w.compressor.Flush()
+	}
+	if flusher, ok := w.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+func (w *compressResponseWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	hijacker, ok := w.ResponseWriter.(http.Hijacker)
+	if !ok {
+		return nil, nil, fmt.Errorf("ResponseWriter 不支持 Hijack")
+	}
+	w.hijacked = true
+	return hijacker.Hijack()
+}
+
+func (w *compressResponseWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
@@ -0,0 +1,256 @@
+package http
+
+import (
+	"compress/gzip"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/andybalholm/brotli"
+)
+
+const compressionTestConfig = `
+http:
+  compression:
+    encodings: [br, gzip]
+    content_types: [application/json, text/*]
+    min_size: 64
+`
+
+// newCompressionHandler 返回经过 compression 中间件的 handler
+func newCompressionHandler(t *testing.T, handler http.Handler) http.Handler {
+	t.Helper()
+	middleware, err := compressionMiddleware(newTestConfig(t, compressionTestConfig))
+	if err != nil {
+		t.Fatalf("Failed to create compression middleware: %v", err)
+	}
+	return middleware(handler)
+}
+
+// serveCompressed 以 acceptEncoding 请求 handler，返回响应
+func serveCompressed(handler http.Handler, method, acceptEncoding string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, "/users", nil)
+	if acceptEncoding != "" {
+		req.Header.Set("Accept-Encoding", acceptEncoding)
+	}
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	return rec
+}
+
+// decode 按 Content-Encoding 解压响应体
+func decode(t *testing.T, rec *httptest.ResponseRecorder) string {
+	t.Helper()
+	var reader io.Reader = rec.Body
+	switch rec.Header().Get("Content-Encoding") {
+	case "gzip":
+		gz, err := gzip.NewReader(rec.Body)
+		if err != nil {
+			t.Fatalf("Failed to read gzip body: %v", err)
+		}
+		reader = gz
+	case "br":
+		reader = brotli.NewReader(rec.Body)
+	}
+	body, err := io.ReadAll(reader)
+	if err != nil {
+		t.Fatalf("Failed to decode body: %v", err)
+	}
+	return string(body)
+}
+
+func TestAcceptEncoding(t *testing.T) {
+	encodings := []string{"br", "gzip"}
+	tests := []struct {
+		header string
+		want   string
+	}{
+		{"", ""},
+		{"gzip", "gzip"},
+		// 按配置的顺序选择，而不是 Accept-Encoding 中的顺序
+		{"gzip, deflate, br", "br"},
+		{"GZIP", "gzip"},
+		{"br;q=0, gzip;q=0.5", "gzip"},
+		{"br;q=0, gzip;q=0", ""},
+		{"*", "br"},
+		{"br;q=0, *", "gzip"},
+		{"identity", ""},
+		{"deflate", ""},
+	}
+	for _, tt := range tests {
+		if got := acceptEncoding(tt.header, encodings); got != tt.want {
+			t.Errorf("Expected %q for %q, got %q", tt.want, tt.header, got)
+		}
+	}
+}
+
+func TestCompressibleType(t *testing.T) {
+	types := []string{"application/json", "text/*"}
+	tests := []struct {
+		contentType string
+		want        bool
+	}{
+		{"application/json", true},
+		{"application/json; charset=utf-8", true},
+		{"Text/HTML; charset=utf-8", true},
+		{"text/plain", true},
+		{"application/json-seq", false},
+		{"image/png", false},
+		{"application/octet-stream", false},
+	}
+	for _, tt := range tests {
+		if got := compressibleType(tt.contentType, types); got != tt.want {
+			t.Errorf("Expected %v for %q, got %v", tt.want, tt.contentType, got)
+		}
+	}
+}
+
+func TestCompressionConfig(t *testing.T) {
+	middleware, err := compressionMiddleware(newTestConfig(t, "http: {}\n"))
+	if err != nil || middleware != nil {
+		t.Errorf("Expected no middleware without encodings, got %v, %v", middleware != nil, err)
+	}
+
+	_, err = compressionMiddleware(newTestConfig(t, `
+http:
+  compression:
+    encodings: [deflate]
+    content_types: [text/*]
+`))
+	if err == nil || !strings.Contains(err.Error(), "deflate") {
+		t.Errorf("Expected unsupported deflate error, got %v", err)
+	}
+}
+
+func TestCompression(t *testing.T) {
+	body := `{"users":[` + strings.Repeat(`{"name":"alice"},`, 20) + `{"name":"bob"}]}`
+	handler := newCompressionHandler(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Content-Length", "1")
+		// 分多次写入，超过 min_size 后开始压缩
+		_, _ = io.WriteString(w, body[:10])
+		_, _ = io.WriteString(w, body[10:])
+	}))
+
+	for _, encoding := range []string{"gzip", "br"} {
+		rec := serveCompressed(handler, http.MethodGet, encoding)
+		if got := rec.Header().Get("Content-Encoding"); got != encoding {
+			t.Errorf("Expected Content-Encoding %s, got %q", encoding, got)
+		}
+		if got := rec.Header().Get("Content-Length"); got != "" {
+			t.Errorf("Expected Content-Length removed, got %q", got)
+		}
+		if got := rec.Header().Get("Vary"); got != "Accept-Encoding" {
+			t.Errorf("Expected Vary Accept-Encoding, got %q", got)
+		}
+		if rec.Body.Len() >= len(body) {
+			t.Errorf("Expected compressed body smaller than %d, got %d", len(body), rec.Body.Len())
+		}
+		if got := decode(t, rec); got != body {
+			t.Errorf("Expected decoded body %q, got %q", body, got)
+		}
+	}
+
+	// 客户端不接受压缩
+	rec := serveCompressed(handler, http.MethodGet, "")
+	if rec.Header().Get("Content-Encoding") != "" || rec.Body.String() != body {
+		t.Errorf("Expected uncompressed body, got encoding %q", rec.Header().Get("Content-Encoding"))
+	}
+}
+
+func TestCompressionSkipped(t *testing.T) {
+	large := strings.Repeat("a", 128)
+	tests := []struct {
+		name    string
+		method  string
+		status  int
+		headers map[string]string
+		body    string
+	}{
+		// 小于 min_size 的响应
+		{"small", http.MethodGet, http.StatusOK, map[string]string{"Content-Type": "text/plain"}, "hello"},
+		{"image", http.MethodGet, http.StatusOK, map[string]string{"Content-Type": "image/png"}, large},
+		{"encoded", http.MethodGet, http.StatusOK, map[string]string{"Content-Type": "text/plain", "Content-Encoding": "gzip"}, large},
+		{"no-transform", http.MethodGet, http.StatusOK, map[string]string{"Content-Type": "text/plain", "Cache-Control": "no-transform"}, large},
+		{"partial", http.MethodGet, http.StatusPartialContent, map[string]string{"Content-Type": "text/plain"}, large},
+		{"head", http.MethodHead, http.StatusOK, map[string]string{"Content-Type": "text/plain"}, large},
+	}
+	for _, tt := range tests {
+		handler := newCompressionHandler(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			for name, value := range tt.headers {
+				w.Header().Set(name, value)
+			}
+			w.WriteHeader(tt.status)
+			_, _ = io.WriteString(w, tt.body)
+		}))
+		rec := serveCompressed(handler, tt.method, "gzip")
+		if rec.Code != tt.status {
+			t.Errorf("%s: Expected status %d, got %d", tt.name, tt.status, rec.Code)
+		}
+		if got := rec.Header().Get("Content-Encoding"); got != tt.headers["Content-Encoding"] {
+			t.Errorf("%s: Expected Content-Encoding %q, got %q", tt.name, tt.headers["Content-Encoding"], got)
+		}
+		if rec.Body.String() != tt.body {
+			t.Errorf("%s: Expected body unchanged, got %q", tt.name, rec.Body.String())
+		}
+	}
+}
+
+func TestCompressionDetectsContentType(t *testing.T) {
+	body := strings.Repeat("plain text ", 20)
+	handler := newCompressionHandler(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = io.WriteString(w, body)
+	}))
+
+	// 没有 Content-Type 时按未压缩的数据检测，而不是让标准库检测压缩后的数据
+	rec := serveCompressed(handler, http.MethodGet, "gzip")
+	if got := rec.Header().Get("Content-Type"); got != "text/plain; charset=utf-8" {
+		t.Errorf("Expected detected Content-Type, got %q", got)
+	}
+	if got := decode(t, rec); got != body {
+		t.Errorf("Expected decoded body %q, got %q", body, got)
+	}
+}
+
+func TestCompressionFlush(t *testing.T) {
+	handler := newCompressionHandler(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		_, _ = io.WriteString(w, "data: 1\n\n")
+		w.(http.Flusher).Flush()
+		_, _ = io.WriteString(w, "data: 2\n\n")
+	}))
+
+	// Flush 时即使小于 min_size 也开始压缩，已写出的数据可以解压
+	req := httptest.NewRequest(http.MethodGet, "/events", nil)
+	req.Header.Set("Accept-Encoding", "gzip")
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	if !rec.Flushed {
+		t.Errorf("Expected response flushed")
+	}
+	if got := rec.Header().Get("Content-Encoding"); got != "gzip" {
+		t.Errorf("Expected Content-Encoding gzip, got %q", got)
+	}
+	if got := decode(t, rec); got != "data: 1\n\ndata: 2\n\n" {
+		t.Errorf("Expected both events, got %q", got)
+	}
+}
+
+func TestCompressionNoContent(t *testing.T) {
+	handler := newCompressionHandler(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	rec := serveCompressed(handler, http.MethodGet, "gzip")
+	if rec.Code != http.StatusNoContent || rec.Body.Len() != 0 || rec.Header().Get("Content-Encoding") != "" {
+		t.Errorf("Expected empty 204 without encoding, got %d %q", rec.Code, rec.Header().Get("Content-Encoding"))
+	}
+
+	// 没有写入任何内容时沿用标准库的默认响应
+	handler = newCompressionHandler(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	rec = serveCompressed(handler, http.MethodGet, "gzip")
+	if rec.Code != http.StatusOK || rec.Body.Len() != 0 {
+		t.Errorf("Expected empty 200, got %d %q", rec.Code, rec.Body.String())
+	}
+}
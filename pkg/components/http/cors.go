@@ -0,0 +1,118 @@
+package http
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/stones-hub/taurus-pro-config/pkg/config"
+	"github.com/stones-hub/taurus-pro-http/pkg/router"
+)
+
+// corsMiddleware 按 http.cors 处理跨域请求，allowed_origins 为空时不使用
+// 预检请求（OPTIONS 且带 Access-Control-Request-Method）在这里返回 204，不进入路由；来源不允许时预检返回 403，
+// 普通请求照常处理但不带 CORS 响应头，由浏览器拦截响应
+func corsMiddleware(cfg *config.Config) (router.MiddlewareFunc, error) {
+	origins := cfg.GetStringSlice("http.cors.allowed_origins")
+	if len(origins) == 0 {
+		return nil, nil
+	}
+	credentials := cfg.GetBool("http.cors.allow_credentials")
+
+	var (
+		allowAll  bool
+		exact     = make(map[string]bool)
+		wildcards [][2]string // 如 https://*.example.com 的前缀与后缀
+	)
+	for _, origin := range origins {
+		origin = strings.ToLower(strings.TrimSpace(origin))
+		switch {
+		case origin == "*":
+			allowAll = true
+		case strings.Contains(origin, "*"):
+			prefix, suffix, _ := strings.Cut(origin, "*")
+			wildcards = append(wildcards, [2]string{prefix, suffix})
+		default:
+			exact[origin] = true
+		}
+	}
+	if allowAll && credentials {
+		return nil, fmt.Errorf("http.cors.allow_credentials 为 true 时 allowed_origins 不能为 *，需要列出允许的来源")
+	}
+	allowed := func(origin string) bool {
+		origin = strings.ToLower(origin)
+		if allowAll || exact[origin] {
+			return true
+		}
+		for _, w := range wildcards {
+			if len(origin) > len(w[0])+len(w[1]) && strings.HasPrefix(origin, w[0]) && strings.HasSuffix(origin, w[1]) {
+				return true
+			}
+		}
+		return false
+	}
+
+	methods := strings.Join(cfg.GetStringSlice("http.cors.allowed_methods"), ", ")
+	if methods == "" {
+		methods = "GET, POST, PUT, PATCH, DELETE, OPTIONS"
+	}
+	headers := strings.Join(cfg.GetStringSlice("http.cors.allowed_headers"), ", ")
+	exposed := strings.Join(cfg.GetStringSlice("http.cors.exposed_headers"), ", ")
+	maxAge := cfg.GetInt("http.cors.max_age")
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			origin := r.Header.Get("Origin")
+			if origin == "" {
+				next.ServeHTTP(w, r)
+				return
+			}
+			preflight := r.Method == http.MethodOptions && r.Header.Get("Access-Control-Request-Method") != ""
+
+			h := w.Header()
+			h.Add("Vary", "Origin")
+			if preflight {
+				h.Add("Vary", "Access-Control-Request-Method")
+				h.Add("Vary", "Access-Control-Request-Headers")
+			}
+			if !allowed(origin) {
+				if preflight {
+					w.WriteHeader(http.StatusForbidden)
+					return
+				}
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			if allowAll {
+				h.Set("Access-Control-Allow-Origin", "*")
+			} else {
+				h.Set("Access-Control-Allow-Origin", origin)
+			}
+			if credentials {
+				h.Set("Access-Control-Allow-Credentials", "true")
+			}
+
+			if !preflight {
+				if exposed != "" {
+					h.Set("Access-Control-Expose-Headers", exposed)
+				}
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			h.Set("Access-Control-Allow-Methods", methods)
+			// 没有配置 allowed_headers 时允许预检请求声明的所有请求头
+			if headers != "" {
+				h.Set("Access-Control-Allow-Headers", headers)
+			} else if requested := r.Header.Get("Access-Control-Request-Headers"); requested != "" {
+				h.Set("Access-Control-Allow-Headers", requested)
+			}
+			if maxAge > 0 {
+				h.Set("Access-Control-Max-Age", strconv.Itoa(maxAge))
+			}
+			w.WriteHeader(http.StatusNoContent)
+		})
+	}, nil
+}
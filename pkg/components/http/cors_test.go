@@ -0,0 +1,189 @@
+package http
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// newCorsHandler 返回经过 cors 中间件的 handler，处理函数被调用时 called 为 true
+func newCorsHandler(t *testing.T, content string, called *bool) http.Handler {
+	t.Helper()
+	middleware, err := corsMiddleware(newTestConfig(t, content))
+	if err != nil {
+		t.Fatalf("Failed to create cors middleware: %v", err)
+	}
+	if middleware == nil {
+		t.Fatalf("Expected cors middleware, got nil")
+	}
+	return middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		*called = true
+		w.WriteHeader(http.StatusOK)
+	}))
+}
+
+const corsTestConfig = `
+http:
+  cors:
+    allowed_origins: [https://app.example.com, "https://*.example.org"]
+    allowed_methods: [GET, POST]
+    exposed_headers: [X-Request-ID]
+    allow_credentials: true
+    max_age: 600
+`
+
+// preflightRequest 来源为 origin 的预检请求
+func preflightRequest(origin string) *http.Request {
+	req := httptest.NewRequest(http.MethodOptions, "/users", nil)
+	req.Header.Set("Origin", origin)
+	req.Header.Set("Access-Control-Request-Method", http.MethodPost)
+	req.Header.Set("Access-Control-Request-Headers", "Content-Type, Authorization")
+	return req
+}
+
+func TestCorsDisabled(t *testing.T) {
+	middleware, err := corsMiddleware(newTestConfig(t, "http: {}\n"))
+	if err != nil || middleware != nil {
+		t.Errorf("Expected no middleware without allowed_origins, got %v, %v", middleware != nil, err)
+	}
+}
+
+func TestCorsCredentialsWithWildcard(t *testing.T) {
+	_, err := corsMiddleware(newTestConfig(t, `
+http:
+  cors:
+    allowed_origins: ["*"]
+    allow_credentials: true
+`))
+	if err == nil || !strings.Contains(err.Error(), "allow_credentials") {
+		t.Errorf("Expected allow_credentials error, got %v", err)
+	}
+}
+
+func TestCorsPreflight(t *testing.T) {
+	var called bool
+	handler := newCorsHandler(t, corsTestConfig, &called)
+
+	// 允许的来源：预检请求直接返回 204，不进入路由
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, preflightRequest("https://app.example.com"))
+	if rec.Code != http.StatusNoContent {
+		t.Errorf("Expected status 204, got %d", rec.Code)
+	}
+	if called {
+		t.Errorf("Expected preflight not to reach handler")
+	}
+	expected := map[string]string{
+		"Access-Control-Allow-Origin":      "https://app.example.com",
+		"Access-Control-Allow-Credentials": "true",
+		"Access-Control-Allow-Methods":     "GET, POST",
+		// 没有配置 allowed_headers 时允许预检请求声明的请求头
+		"Access-Control-Allow-Headers": "Content-Type, Authorization",
+		"Access-Control-Max-Age":       "600",
+	}
+	for name, want := range expected {
+		if got := rec.Header().Get(name); got != want {
+			t.Errorf("Expected %s %q, got %q", name, want, got)
+		}
+	}
+	vary := strings.Join(rec.Header().Values("Vary"), ", ")
+	if vary != "Origin, Access-Control-Request-Method, Access-Control-Request-Headers" {
+		t.Errorf("Expected preflight Vary headers, got %q", vary)
+	}
+
+	// 不允许的来源：预检请求返回 403，不带 CORS 响应头
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, preflightRequest("https://evil.example.com"))
+	if rec.Code != http.StatusForbidden {
+		t.Errorf("Expected status 403, got %d", rec.Code)
+	}
+	if got := rec.Header().Get("Access-Control-Allow-Origin"); got != "" {
+		t.Errorf("Expected no Access-Control-Allow-Origin, got %q", got)
+	}
+}
+
+func TestCorsOrigins(t *testing.T) {
+	tests := []struct {
+		origin string
+		want   string
+	}{
+		{"https://app.example.com", "https://app.example.com"},
+		// 来源不区分大小写，响应原样返回请求的来源
+		{"HTTPS://APP.EXAMPLE.COM", "HTTPS://APP.EXAMPLE.COM"},
+		{"https://api.example.org", "https://api.example.org"},
+		{"https://a.b.example.org", "https://a.b.example.org"},
+		// 通配符至少匹配一个字符
+		{"https://.example.org", ""},
+		{"https://example.org", ""},
+		{"http://app.example.com", ""},
+		{"https://app.example.com.evil.com", ""},
+	}
+	for _, tt := range tests {
+		var called bool
+		handler := newCorsHandler(t, corsTestConfig, &called)
+		req := httptest.NewRequest(http.MethodGet, "/users", nil)
+		req.Header.Set("Origin", tt.origin)
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+
+		// 普通请求无论来源是否允许都照常处理，由浏览器按响应头拦截
+		if !called || rec.Code != http.StatusOK {
+			t.Errorf("Expected %s to reach handler, got status %d", tt.origin, rec.Code)
+		}
+		if got := rec.Header().Get("Access-Control-Allow-Origin"); got != tt.want {
+			t.Errorf("Expected Access-Control-Allow-Origin %q for %s, got %q", tt.want, tt.origin, got)
+		}
+		exposed := rec.Header().Get("Access-Control-Expose-Headers")
+		if tt.want != "" && exposed != "X-Request-ID" {
+			t.Errorf("Expected exposed headers for %s, got %q", tt.origin, exposed)
+		}
+		if got := rec.Header().Get("Vary"); got != "Origin" {
+			t.Errorf("Expected Vary Origin for %s, got %q", tt.origin, got)
+		}
+	}
+}
+
+func TestCorsWithoutOrigin(t *testing.T) {
+	var called bool
+	handler := newCorsHandler(t, corsTestConfig, &called)
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodOptions, "/users", nil))
+
+	// 没有 Origin 的请求（同源或非浏览器）不处理，OPTIONS 照常进入路由
+	if !called {
+		t.Errorf("Expected request without Origin to reach handler")
+	}
+	if len(rec.Header()) != 0 {
+		t.Errorf("Expected no headers, got %v", rec.Header())
+	}
+}
+
+func TestCorsAllowAll(t *testing.T) {
+	var called bool
+	handler := newCorsHandler(t, `
+http:
+  cors:
+    allowed_origins: ["*"]
+    allowed_headers: [Content-Type]
+`, &called)
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, preflightRequest("https://any.example.net"))
+	if got := rec.Header().Get("Access-Control-Allow-Origin"); got != "*" {
+		t.Errorf("Expected Access-Control-Allow-Origin *, got %q", got)
+	}
+	if got := rec.Header().Get("Access-Control-Allow-Credentials"); got != "" {
+		t.Errorf("Expected no Access-Control-Allow-Credentials, got %q", got)
+	}
+	// 配置了 allowed_headers 时只允许配置的请求头，没有配置 allowed_methods 时使用默认的方法
+	if got := rec.Header().Get("Access-Control-Allow-Headers"); got != "Content-Type" {
+		t.Errorf("Expected Access-Control-Allow-Headers Content-Type, got %q", got)
+	}
+	if got := rec.Header().Get("Access-Control-Allow-Methods"); got != "GET, POST, PUT, PATCH, DELETE, OPTIONS" {
+		t.Errorf("Expected default methods, got %q", got)
+	}
+	if got := rec.Header().Get("Access-Control-Max-Age"); got != "" {
+		t.Errorf("Expected no Access-Control-Max-Age, got %q", got)
+	}
+}
@@ -1,24 +1,51 @@
 package http
 
 import (
+	"context"
+	"fmt"
 	"log"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/stones-hub/taurus-pro-config/pkg/config"
+	"github.com/stones-hub/taurus-pro-core/pkg/components/common"
+	"github.com/stones-hub/taurus-pro-core/pkg/components/instrument"
+	"github.com/stones-hub/taurus-pro-core/pkg/components/lifecycle"
+	"github.com/stones-hub/taurus-pro-core/pkg/components/reload"
+	"github.com/stones-hub/taurus-pro-core/pkg/components/tlscert"
 	"github.com/stones-hub/taurus-pro-core/pkg/components/types"
 	"github.com/stones-hub/taurus-pro-http/pkg/mcp"
 	"github.com/stones-hub/taurus-pro-http/pkg/server"
 	"github.com/stones-hub/taurus-pro-http/pkg/wsocket"
 )
 
-func ProvideHttpComponent(cfg *config.Config) (*server.Server, error) {
-	httpServer := server.NewServer(
-		server.WithAddr(cfg.GetString("http.address")+":"+cfg.GetString("http.port")),
-		server.WithReadTimeout(time.Duration(cfg.GetInt("http.read_timeout"))*time.Second),
-		server.WithWriteTimeout(time.Duration(cfg.GetInt("http.write_timeout"))*time.Second),
-		server.WithIdleTimeout(time.Duration(cfg.GetInt("http.idle_timeout"))*time.Second),
-		server.WithMaxHeaderBytes(1<<20),
-	)
+// ProvideHttpMiddlewareComponent 创建 http 中间件注册表，应用在注册路由之前注册自己的中间件
+func ProvideHttpMiddlewareComponent(cfg *config.Config, loggers *common.LoggerAccessor) (*MiddlewareRegistry, error) {
+	return NewMiddlewareRegistry(cfg, loggers)
+}
+
+var httpMiddlewareWire = &types.Wire{
+	RequirePath:  []string{},
+	Name:         "HttpMiddleware",
+	Type:         "*HttpMiddlewareRegistry",
+	ProviderName: "ProvideHttpMiddlewareComponent",
+	Provider: `// {{.ProviderName}} 创建 http 中间件注册表，应用在注册路由之前注册自己的中间件
+func {{.ProviderName}}(cfg *config.Config, loggers *LoggerAccessor) ({{.Type}}, error) {
+	return NewHttpMiddlewareRegistry(cfg, loggers)
+}`,
+	TestRequirePath:  []string{},
+	TestProviderName: "ProvideTestHttpMiddlewareComponent",
+	TestProvider: `func {{.TestProviderName}}(t testing.TB, c *taurus.Components, h *Harness) (*taurus.HttpMiddlewareRegistry, error) {
+	return taurus.ProvideHttpMiddlewareComponent(c.Config, c.LoggerAccessor)
+}`,
+}
+
+func ProvideHttpComponent(cfg *config.Config, lc *lifecycle.Manager, inst *instrument.Registry, rl *reload.Reloader, mw *MiddlewareRegistry) (*Server, error) {
+	httpServer, err := httpListener(cfg, lc, inst, rl, mw, DefaultServer, nil)
+	if err != nil {
+		return nil, err
+	}
 
 	if cfg.GetBool("websocket.enable") {
 		wsocket.Initialize()
@@ -30,90 +57,427 @@ func ProvideHttpComponent(cfg *config.Config) (*server.Server, error) {
 	return httpServer, nil
 }
 
+// httpListener 创建一个 http 监听并交给生命周期管理器启动与停止
+// options 为 http.servers 中的一项，其中没有配置的键使用 http 下的同名配置，如超时与 TLS；为 nil 时即默认监听
+func httpListener(cfg *config.Config, lc *lifecycle.Manager, inst *instrument.Registry, rl *reload.Reloader, mw *MiddlewareRegistry, name string, options map[string]interface{}) (*Server, error) {
+	get := func(key string) interface{} {
+		var value interface{} = options
+		for _, part := range strings.Split(key, ".") {
+			item, _ := value.(map[string]interface{})
+			value = item[part]
+		}
+		if value == nil {
+			return cfg.Get("http." + key)
+		}
+		return value
+	}
+
+	if options != nil && options["port"] == nil {
+		return nil, fmt.Errorf("http 监听 %s 缺少 port 配置", name)
+	}
+	addr := httpString(get("address")) + ":" + httpString(get("port"))
+	readTimeout := time.Duration(httpInt(get("read_timeout"))) * time.Second
+	writeTimeout := time.Duration(httpInt(get("write_timeout"))) * time.Second
+	idleTimeout := time.Duration(httpInt(get("idle_timeout"))) * time.Second
+	// 请求行与请求头的最大字节数，在路由之前由 http 服务检查，只能按监听配置
+	maxHeaderBytes := httpInt(get("limits.header"))
+	if maxHeaderBytes <= 0 {
+		maxHeaderBytes = 1 << 20
+	}
+
+	// 启用 TLS 或 h2c 时由标准库 http.Server 监听
+	var serve *ServeOptions
+	if httpBool(get("tls.enabled")) || httpBool(get("h2c")) {
+		serve = &ServeOptions{
+			Addr:           addr,
+			ReadTimeout:    readTimeout,
+			WriteTimeout:   writeTimeout,
+			IdleTimeout:    idleTimeout,
+			MaxHeaderBytes: maxHeaderBytes,
+			H2C:            httpBool(get("h2c")),
+		}
+	}
+
+	if httpBool(get("tls.enabled")) {
+		tlsConfig, certs, err := tlscert.NewConfig(&tlscert.Options{
+			CertFile:       httpString(get("tls.cert")),
+			KeyFile:        httpString(get("tls.key")),
+			ClientCAFile:   httpString(get("tls.client_ca")),
+			MinVersion:     httpString(get("tls.min_version")),
+			ReloadInterval: time.Duration(httpInt(get("tls.reload_interval"))) * time.Second,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("初始化 http 监听 %s 的 TLS 失败: %v", name, err)
+		}
+		serve.TLSConfig = tlsConfig
+
+		// 收到 SIGHUP 时立即加载轮换后的证书，不必等待下一次检查
+		rl.RegisterEvery("http_tls:"+name, func(changed []string) error {
+			return certs.Reload()
+		})
+		log.Printf("%s🔗 -> http %s TLS enabled, min version %s. %s\n", "\033[32m", name, httpString(get("tls.min_version")), "\033[0m")
+	}
+
+	httpServer := NewServer(server.NewServer(
+		server.WithAddr(addr),
+		server.WithReadTimeout(readTimeout),
+		server.WithWriteTimeout(writeTimeout),
+		server.WithIdleTimeout(idleTimeout),
+		server.WithMaxHeaderBytes(maxHeaderBytes),
+	), inst, mw, serve)
+
+	// 默认监听的组件名称为 http，mcp 等组件依赖它
+	component := "http"
+	if name != DefaultServer {
+		component = "http:" + name
+	}
+	lc.Register(component, &lifecycle.Hook{
+		OnStart: func(ctx context.Context) error {
+			errChan := make(chan error, 1)
+			httpServer.Start(errChan)
+			lc.Watch(component, errChan)
+			return nil
+		},
+		OnStop: func(ctx context.Context) error {
+			return httpServer.Shutdown(ctx)
+		},
+	})
+
+	return httpServer, nil
+}
+
+// httpString、httpInt、httpBool 转换监听配置中的值，环境变量替换后的值可能是字符串
+func httpString(value interface{}) string {
+	if value == nil {
+		return ""
+	}
+	return fmt.Sprint(value)
+}
+
+func httpInt(value interface{}) int {
+	switch v := value.(type) {
+	case int:
+		return v
+	case int64:
+		return int(v)
+	case float64:
+		return int(v)
+	case string:
+		n, _ := strconv.Atoi(v)
+		return n
+	}
+	return 0
+}
+
+func httpBool(value interface{}) bool {
+	switch v := value.(type) {
+	case bool:
+		return v
+	case string:
+		b, _ := strconv.ParseBool(v)
+		return b
+	}
+	return false
+}
+
+// ProvideHttpServersComponent 按 http.servers 创建默认监听之外的监听，每个监听有独立的地址、超时、TLS 与中间件
+// 如把管理后台与 /metrics 放在只对内网开放的端口上
+func ProvideHttpServersComponent(cfg *config.Config, lc *lifecycle.Manager, inst *instrument.Registry, rl *reload.Reloader, mw *MiddlewareRegistry, httpServer *Server) (Servers, error) {
+	servers := Servers{DefaultServer: httpServer}
+
+	list, _ := cfg.Get("http.servers").([]interface{})
+	for _, raw := range list {
+		options, _ := raw.(map[string]interface{})
+		name := httpString(options["name"])
+		if name == "" {
+			return nil, fmt.Errorf("http.servers 中的监听缺少 name 配置")
+		}
+		if _, ok := servers[name]; ok {
+			return nil, fmt.Errorf("http 监听名称重复: %s", name)
+		}
+
+		srv, err := httpListener(cfg, lc, inst, rl, mw, name, options)
+		if err != nil {
+			return nil, err
+		}
+		// 监听级中间件，位于全局中间件之内，如只在管理端口上鉴权
+		if middleware := mw.Listener(middlewareNames(options["middleware"])); middleware != nil {
+			srv.Use(middleware)
+		}
+		servers[name] = srv
+		log.Printf("%s🔗 -> Http server %s initialized successfully. %s\n", "\033[32m", name, "\033[0m")
+	}
+
+	return servers, nil
+}
+
 var httpWire = &types.Wire{
-	RequirePath:  []string{"github.com/stones-hub/taurus-pro-http/pkg/server", "log", "time", "github.com/stones-hub/taurus-pro-http/pkg/wsocket"},
+	RequirePath:  []string{"github.com/stones-hub/taurus-pro-http/pkg/server", "context", "fmt", "log", "strconv", "strings", "time", "github.com/stones-hub/taurus-pro-http/pkg/wsocket"},
 	Name:         "Http",
-	Type:         "*server.Server",
+	Type:         "*HttpServer",
 	ProviderName: "ProvideHttpComponent",
-	Provider: `func {{.ProviderName}}(cfg *config.Config) ({{.Type}}, error) {
-httpServer := server.NewServer(
-server.WithAddr(cfg.GetString("http.address")+":"+cfg.GetString("http.port")),
-server.WithReadTimeout(time.Duration(cfg.GetInt("http.read_timeout"))*time.Second),
-server.WithWriteTimeout(time.Duration(cfg.GetInt("http.write_timeout"))*time.Second),
-server.WithIdleTimeout(time.Duration(cfg.GetInt("http.idle_timeout"))*time.Second),
-server.WithMaxHeaderBytes(1<<20),
-)
+	Provider: `func {{.ProviderName}}(cfg *config.Config, lc *LifecycleManager, inst *Instrumentation, rl *ConfigReloader, mw *HttpMiddlewareRegistry) ({{.Type}}, error) {
+	httpServer, err := httpListener(cfg, lc, inst, rl, mw, DefaultHttpServer, nil)
+	if err != nil {
+		return nil, err
+	}
 
-if cfg.GetBool("websocket.enable") {
+	if cfg.GetBool("websocket.enable") {
 		wsocket.Initialize()
 		log.Printf("%s🔗 -> http-websocket initialized successfully. %s\n", "\033[32m", "\033[0m")
 	}
 
-log.Printf("%s🔗 -> Http all initialized successfully. %s\n", "\033[32m", "\033[0m")
+	log.Printf("%s🔗 -> Http all initialized successfully. %s\n", "\033[32m", "\033[0m")
+
+	return httpServer, nil
+}
+
+// httpListener 创建一个 http 监听并交给生命周期管理器启动与停止
+// options 为 http.servers 中的一项，其中没有配置的键使用 http 下的同名配置，如超时与 TLS；为 nil 时即默认监听
+func httpListener(cfg *config.Config, lc *LifecycleManager, inst *Instrumentation, rl *ConfigReloader, mw *HttpMiddlewareRegistry, name string, options map[string]interface{}) (*HttpServer, error) {
+	get := func(key string) interface{} {
+		var value interface{} = options
+		for _, part := range strings.Split(key, ".") {
+			item, _ := value.(map[string]interface{})
+			value = item[part]
+		}
+		if value == nil {
+			return cfg.Get("http." + key)
+		}
+		return value
+	}
+
+	if options != nil && options["port"] == nil {
+		return nil, fmt.Errorf("http 监听 %s 缺少 port 配置", name)
+	}
+	addr := httpString(get("address")) + ":" + httpString(get("port"))
+	readTimeout := time.Duration(httpInt(get("read_timeout"))) * time.Second
+	writeTimeout := time.Duration(httpInt(get("write_timeout"))) * time.Second
+	idleTimeout := time.Duration(httpInt(get("idle_timeout"))) * time.Second
+	// 请求行与请求头的最大字节数，在路由之前由 http 服务检查，只能按监听配置
+	maxHeaderBytes := httpInt(get("limits.header"))
+	if maxHeaderBytes <= 0 {
+		maxHeaderBytes = 1 << 20
+	}
+
+	// 启用 TLS 或 h2c 时由标准库 http.Server 监听
+	var serve *HttpServeOptions
+	if httpBool(get("tls.enabled")) || httpBool(get("h2c")) {
+		serve = &HttpServeOptions{
+			Addr:           addr,
+			ReadTimeout:    readTimeout,
+			WriteTimeout:   writeTimeout,
+			IdleTimeout:    idleTimeout,
+			MaxHeaderBytes: maxHeaderBytes,
+			H2C:            httpBool(get("h2c")),
+		}
+	}
+
+	if httpBool(get("tls.enabled")) {
+		tlsConfig, certs, err := NewTLSConfig(&TLSOptions{
+			CertFile:       httpString(get("tls.cert")),
+			KeyFile:        httpString(get("tls.key")),
+			ClientCAFile:   httpString(get("tls.client_ca")),
+			MinVersion:     httpString(get("tls.min_version")),
+			ReloadInterval: time.Duration(httpInt(get("tls.reload_interval"))) * time.Second,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("初始化 http 监听 %s 的 TLS 失败: %v", name, err)
+		}
+		serve.TLSConfig = tlsConfig
+
+		// 收到 SIGHUP 时立即加载轮换后的证书，不必等待下一次检查
+		rl.RegisterEvery("http_tls:"+name, func(changed []string) error {
+			return certs.Reload()
+		})
+		log.Printf("%s🔗 -> http %s TLS enabled, min version %s. %s\n", "\033[32m", name, httpString(get("tls.min_version")), "\033[0m")
+	}
+
+	httpServer := NewHttpServer(server.NewServer(
+		server.WithAddr(addr),
+		server.WithReadTimeout(readTimeout),
+		server.WithWriteTimeout(writeTimeout),
+		server.WithIdleTimeout(idleTimeout),
+		server.WithMaxHeaderBytes(maxHeaderBytes),
+	), inst, mw, serve)
 
-return httpServer, nil
+	// 默认监听的组件名称为 http，mcp 等组件依赖它
+	component := "http"
+	if name != DefaultHttpServer {
+		component = "http:" + name
+	}
+	lc.Register(component, &LifecycleHook{
+		OnStart: func(ctx context.Context) error {
+			errChan := make(chan error, 1)
+			httpServer.Start(errChan)
+			lc.Watch(component, errChan)
+			return nil
+		},
+		OnStop: func(ctx context.Context) error {
+			return httpServer.Shutdown(ctx)
+		},
+	})
+
+	return httpServer, nil
+}
+
+// httpString、httpInt、httpBool 转换监听配置中的值，环境变量替换后的值可能是字符串
+func httpString(value interface{}) string {
+	if value == nil {
+		return ""
+	}
+	return fmt.Sprint(value)
+}
+
+func httpInt(value interface{}) int {
+	switch v := value.(type) {
+	case int:
+		return v
+	case int64:
+		return int(v)
+	case float64:
+		return int(v)
+	case string:
+		n, _ := strconv.Atoi(v)
+		return n
+	}
+	return 0
+}
+
+func httpBool(value interface{}) bool {
+	switch v := value.(type) {
+	case bool:
+		return v
+	case string:
+		b, _ := strconv.ParseBool(v)
+		return b
+	}
+	return false
+}`,
+	TestRequirePath:  []string{},
+	TestProviderName: "ProvideTestHttpComponent",
+	TestProvider: `// ProvideTestHttpComponent 创建 http 服务但不监听端口，注册的路由通过 Handler 挂载到 httptest.Server
+func {{.TestProviderName}}(t testing.TB, c *taurus.Components, h *Harness) (*taurus.HttpServer, error) {
+	return taurus.ProvideHttpComponent(c.Config, c.Lifecycle, c.Instrument, c.Reload, c.HttpMiddleware)
 }`,
 }
 
-func ProvideMcpComponent(cfg *config.Config, httpServer *server.Server) (*mcp.MCPServer, error) {
+var httpServersWire = &types.Wire{
+	RequirePath:  []string{"fmt", "log"},
+	Name:         "HttpServers",
+	Type:         "HttpServers",
+	ProviderName: "ProvideHttpServersComponent",
+	Provider: `// {{.ProviderName}} 按 http.servers 创建默认监听之外的监听，每个监听有独立的地址、超时、TLS 与中间件
+// 如把管理后台与 /metrics 放在只对内网开放的端口上
+func {{.ProviderName}}(cfg *config.Config, lc *LifecycleManager, inst *Instrumentation, rl *ConfigReloader, mw *HttpMiddlewareRegistry, httpServer *HttpServer) ({{.Type}}, error) {
+	servers := HttpServers{DefaultHttpServer: httpServer}
+
+	list, _ := cfg.Get("http.servers").([]interface{})
+	for _, raw := range list {
+		options, _ := raw.(map[string]interface{})
+		name := httpString(options["name"])
+		if name == "" {
+			return nil, fmt.Errorf("http.servers 中的监听缺少 name 配置")
+		}
+		if _, ok := servers[name]; ok {
+			return nil, fmt.Errorf("http 监听名称重复: %s", name)
+		}
+
+		srv, err := httpListener(cfg, lc, inst, rl, mw, name, options)
+		if err != nil {
+			return nil, err
+		}
+		// 监听级中间件，位于全局中间件之内，如只在管理端口上鉴权
+		if middleware := mw.Listener(httpMiddlewareNames(options["middleware"])); middleware != nil {
+			srv.Use(middleware)
+		}
+		servers[name] = srv
+		log.Printf("%s🔗 -> Http server %s initialized successfully. %s\n", "\033[32m", name, "\033[0m")
+	}
+
+	return servers, nil
+}`,
+	TestRequirePath:  []string{},
+	TestProviderName: "ProvideTestHttpServersComponent",
+	TestProvider: `// ProvideTestHttpServersComponent 测试中只有默认监听，注册到其他监听的路由与其他路由一起挂载到 httptest.Server
+func {{.TestProviderName}}(t testing.TB, c *taurus.Components, h *Harness) (taurus.HttpServers, error) {
+	return taurus.HttpServers{taurus.DefaultHttpServer: c.Http}, nil
+}`,
+}
+
+func ProvideMcpComponent(cfg *config.Config, httpServer *Server, lc *lifecycle.Manager) (*mcp.MCPServer, error) {
 
 	// 如果是stdio模式的mcp，不要在http-server中启用, 因为我没构建的就是一个http服务器集群
 	if !cfg.GetBool("mcp.enable") || mcp.Transport(cfg.GetString("mcp.transport")) == mcp.TransportStdio {
 		return nil, nil
 	}
 
+	// mcp 挂载在 server.Server 内部，由标准库 http.Server 监听时无法提供 mcp 的路由，不启动以免 mcp 的客户端请求静默失败
+	if httpServer.serve != nil {
+		return nil, fmt.Errorf("启用 http.tls 或 http.h2c 时无法提供 http 模式的 mcp，请关闭 mcp.enable 或使用 stdio 模式")
+	}
+
 	mcpServer, cleanup, err := mcp.New(
 		mcp.WithName("taurus"),
 		mcp.WithVersion("v0.0.1"),
 		mcp.WithTransport(mcp.Transport(cfg.GetString("mcp.transport"))),
 		mcp.WithMode(mcp.Mode(cfg.GetString("mcp.mode"))),
-		mcp.WithHttpServer(httpServer),
+		mcp.WithHttpServer(httpServer.Server),
 	)
 
 	if err != nil {
 		return nil, err
 	}
 
-	if httpServer != nil {
-		httpServer.RegisterOnShutdown(func() {
+	// mcp 挂载在 http 服务上，依赖 http 使其先于 http 停止，与原来在 http 关闭时清理的顺序一致
+	lc.Register("mcp", &lifecycle.Hook{
+		OnStop: func(ctx context.Context) error {
 			log.Printf("%s🔗 -> Http-MCP starting shutdown. %s\n", "\033[32m", "\033[0m")
 			cleanup()
-		})
-	}
+			return nil
+		},
+	}, "http")
 
 	return mcpServer, nil
 }
 
 var mcpWire = &types.Wire{
-	RequirePath:  []string{"github.com/stones-hub/taurus-pro-http/pkg/mcp", "log"},
+	RequirePath:  []string{"github.com/stones-hub/taurus-pro-http/pkg/mcp", "fmt", "log"},
 	Name:         "McpServer",
 	Type:         "*mcp.MCPServer",
 	ProviderName: "ProvideMcpComponent",
-	Provider: `func {{.ProviderName}}(cfg *config.Config, httpServer *server.Server) ({{.Type}}, error) {
+	Provider: `func {{.ProviderName}}(cfg *config.Config, httpServer *HttpServer, lc *LifecycleManager) ({{.Type}}, error) {
 
 	// 如果是stdio模式的mcp，不要在http-server中启用, 因为我没构建的就是一个http服务器集群
 	if !cfg.GetBool("mcp.enable") || mcp.Transport(cfg.GetString("mcp.transport")) == mcp.TransportStdio {
 		return nil, nil
 	}
 
+	// mcp 挂载在 server.Server 内部，由标准库 http.Server 监听时无法提供 mcp 的路由，不启动以免 mcp 的客户端请求静默失败
+	if httpServer.serve != nil {
+		return nil, fmt.Errorf("启用 http.tls 或 http.h2c 时无法提供 http 模式的 mcp，请关闭 mcp.enable 或使用 stdio 模式")
+	}
+
 	mcpServer, cleanup, err := mcp.New(
 		mcp.WithName("taurus"),
 		mcp.WithVersion("v0.0.1"),
 		mcp.WithTransport(mcp.Transport(cfg.GetString("mcp.transport"))),
 		mcp.WithMode(mcp.Mode(cfg.GetString("mcp.mode"))),
-		mcp.WithHttpServer(httpServer),
+		mcp.WithHttpServer(httpServer.Server),
 	)
 
 	if err != nil {
 		return nil, err
 	}
 
-	if httpServer != nil {
-		httpServer.RegisterOnShutdown(func() {
+	// mcp 挂载在 http 服务上，依赖 http 使其先于 http 停止，与原来在 http 关闭时清理的顺序一致
+	lc.Register("mcp", &LifecycleHook{
+		OnStop: func(ctx context.Context) error {
 			log.Printf("%s🔗 -> Http-MCP starting shutdown. %s\n", "\033[32m", "\033[0m")
 			cleanup()
-		})
-	}
+			return nil
+		},
+	}, "http")
 
 	return mcpServer, nil
 }`,
@@ -126,6 +490,6 @@ var HttpComponent = types.Component{
 	Description:  "Http,WebSocket服务器组件",
 	IsCustom:     true,
 	Required:     true,
-	Dependencies: []string{"config"},
-	Wire:         []*types.Wire{httpWire, mcpWire},
+	Dependencies: []string{"config", "common"},
+	Wire:         []*types.Wire{httpMiddlewareWire, httpWire, httpServersWire, mcpWire},
 }
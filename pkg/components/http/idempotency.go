@@ -0,0 +1,363 @@
+package http
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/stones-hub/taurus-pro-config/pkg/config"
+	"github.com/stones-hub/taurus-pro-http/pkg/router"
+)
+
+// IdempotentReplayedHeader 重放保存的响应时带上的响应头
+const IdempotentReplayedHeader = "Idempotent-Replayed"
+
+// IdempotencyRecord 幂等键保存的记录，Token 标识占用幂等键的请求，Done 为 false 时请求仍在处理
+type IdempotencyRecord struct {
+	Token       string      `json:"token"`
+	Fingerprint string      `json:"fingerprint"` // 请求方法、URI 与请求体的摘要
+	Done        bool        `json:"done"`
+	Status      int         `json:"status,omitempty"`
+	Header      http.Header `json:"header,omitempty"` // 处理函数写入的响应头
+	Body        []byte      `json:"body,omitempty"`
+}
+
+// IdempotencyStore 保存幂等键的记录，内置内存实现，pkg/middleware 在有 redis 组件时注册基于 redis 的实现
+type IdempotencyStore interface {
+	// Acquire 幂等键不存在时保存 record（处理中）并返回 nil，已存在时返回保存的记录
+	Acquire(ctx context.Context, key string, record *IdempotencyRecord, ttl time.Duration) (*IdempotencyRecord, error)
+	// Complete 保存处理完成的记录，幂等键已经被其它请求占用（Token 不同）时不保存
+	Complete(ctx context.Context, key string, record *IdempotencyRecord, ttl time.Duration) error
+	// Release 删除 token 占用的幂等键，处理失败后客户端可以使用同一个幂等键重试
+	Release(ctx context.Context, key, token string) error
+}
+
+// IdempotencyOptions idempotency 中间件的存储与幂等键的作用域
+type IdempotencyOptions struct {
+	Store IdempotencyStore // 为 nil 时保存在内存中，只在当前进程内有效
+	// Scope 返回请求的作用域（如用户 ID），不同作用域中相同的幂等键互不影响；
+	// 返回空字符串时请求不做幂等处理，如匿名请求，避免不同的调用方共享幂等键；为 nil 时按 Authorization 请求头
+	Scope func(r *http.Request) string
+}
+
+// idempotencyMiddleware 内置的 idempotency 中间件，记录保存在内存中
+func idempotencyMiddleware(cfg *config.Config) (router.MiddlewareFunc, error) {
+	return IdempotencyMiddleware(cfg, nil)
+}
+
+// IdempotencyMiddleware 按 http.idempotency 配置处理带幂等键（默认 Idempotency-Key 请求头）的 POST、PUT、PATCH、DELETE 请求
+// 第一个请求占用幂等键并保存响应，之后相同的请求直接返回保存的响应（带 Idempotent-Replayed: true）而不再执行；
+// 第一个请求处理中时返回 409，幂等键用于不同的请求（方法、URI 或请求体不同）时返回 422
+// 5xx 与 401、403、408、409、429 响应不保存，客户端可以使用同一个幂等键重试；没有幂等键的请求不处理
+// 没有作用域（匿名）的请求与 http.idempotency.skip 前缀下的路径（如登录）不处理，设置 Cookie 或 Cache-Control: no-store 的响应不保存，以免重放令牌等凭证
+// 需要读取请求体计算摘要，放在 body_limit 之后；放在 compression 之后，保存的是没有压缩的响应
+func IdempotencyMiddleware(cfg *config.Config, options *IdempotencyOptions) (router.MiddlewareFunc, error) {
+	if options == nil {
+		options = &IdempotencyOptions{}
+	}
+	store, scope := options.Store, options.Scope
+	if store == nil {
+		store = NewMemoryIdempotencyStore()
+	}
+	if scope == nil {
+		scope = func(r *http.Request) string {
+			return r.Header.Get("Authorization")
+		}
+	}
+
+	header := cfg.GetString("http.idempotency.header")
+	if header == "" {
+		header = "Idempotency-Key"
+	}
+	methods := make(map[string]bool)
+	for _, method := range cfg.GetStringSlice("http.idempotency.methods") {
+		methods[strings.ToUpper(method)] = true
+	}
+	if len(methods) == 0 {
+		methods = map[string]bool{http.MethodPost: true, http.MethodPut: true, http.MethodPatch: true, http.MethodDelete: true}
+	}
+	ttl := time.Duration(cfg.GetInt("http.idempotency.ttl")) * time.Second
+	if ttl <= 0 {
+		ttl = 24 * time.Hour
+	}
+	lockTTL := time.Duration(cfg.GetInt("http.idempotency.lock_ttl")) * time.Second
+	if lockTTL <= 0 {
+		lockTTL = 2 * time.Minute
+	}
+	maxBody := cfg.GetInt("http.idempotency.max_body")
+	if maxBody <= 0 {
+		maxBody = 1 << 20
+	}
+	skip := cfg.GetStringSlice("http.idempotency.skip")
+	skipped := func(path string) bool {
+		for _, prefix := range skip {
+			if strings.HasPrefix(path, prefix) {
+				return true
+			}
+		}
+		return false
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			key := r.Header.Get(header)
+			if key == "" || !methods[r.Method] || skipped(r.URL.Path) {
+				next.ServeHTTP(w, r)
+				return
+			}
+			scopeKey := scope(r)
+			if scopeKey == "" {
+				next.ServeHTTP(w, r)
+				return
+			}
+			if len(key) > 255 {
+				http.Error(w, fmt.Sprintf("%s 不能超过 255 个字符", header), http.StatusBadRequest)
+				return
+			}
+
+			body, err := io.ReadAll(r.Body)
+			if err != nil {
+				var maxBytesErr *http.MaxBytesError
+				if errors.As(err, &maxBytesErr) {
+					http.Error(w, http.StatusText(http.StatusRequestEntityTooLarge), http.StatusRequestEntityTooLarge)
+					return
+				}
+				http.Error(w, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
+				return
+			}
+			r.Body = io.NopCloser(bytes.NewReader(body))
+
+			ctx := context.WithoutCancel(r.Context())
+			storeKey := idempotencyDigest([]byte(scopeKey + "\n" + key))
+			record := &IdempotencyRecord{
+				Token:       newRequestID(),
+				Fingerprint: idempotencyDigest([]byte(r.Method+" "+r.URL.RequestURI()+"\n"), body),
+			}
+			existing, err := store.Acquire(ctx, storeKey, record, lockTTL)
+			if err != nil {
+				// 存储不可用时不阻断请求
+				log.Printf("%s🔗 -> 幂等键存储不可用，请求不做幂等处理: %v %s\n", "\033[33m", err, "\033[0m")
+				next.ServeHTTP(w, r)
+				return
+			}
+			if existing != nil {
+				switch {
+				case existing.Fingerprint != record.Fingerprint:
+					http.Error(w, fmt.Sprintf("%s 已经用于其它请求", header), http.StatusUnprocessableEntity)
+				case !existing.Done:
+					w.Header().Set("Retry-After", "1")
+					http.Error(w, "相同幂等键的请求正在处理", http.StatusConflict)
+				default:
+					for k, v := range existing.Header {
+						w.Header()[k] = slices.Clone(v)
+					}
+					w.Header().Set(IdempotentReplayedHeader, "true")
+					w.Header().Set("Content-Length", strconv.Itoa(len(existing.Body)))
+					w.WriteHeader(existing.Status)
+					_, _ = w.Write(existing.Body)
+				}
+				return
+			}
+
+			// 没有保存响应（包括处理时 panic）时释放幂等键
+			completed := false
+			defer func() {
+				if !completed {
+					if err := store.Release(ctx, storeKey, record.Token); err != nil {
+						log.Printf("%s🔗 -> 释放幂等键失败: %v %s\n", "\033[33m", err, "\033[0m")
+					}
+				}
+			}()
+
+			rw := &idempotencyResponseWriter{ResponseWriter: w, before: w.Header().Clone(), status: http.StatusOK, maxBody: maxBody}
+			next.ServeHTTP(rw, r)
+			if !rw.wroteHeader && !rw.hijacked {
+				// 处理函数没有写出响应时与 net/http 一样返回 200
+				rw.WriteHeader(http.StatusOK)
+			}
+			if !rw.storable() {
+				return
+			}
+
+			record.Done = true
+			record.Status = rw.status
+			record.Header = rw.header
+			record.Body = rw.body.Bytes()
+			if err := store.Complete(ctx, storeKey, record, ttl); err != nil {
+				log.Printf("%s🔗 -> 保存幂等键的响应失败: %v %s\n", "\033[33m", err, "\033[0m")
+				return
+			}
+			completed = true
+		})
+	}, nil
+}
+
+// idempotencyDigest 返回 SHA-256 摘要的十六进制文本
+func idempotencyDigest(parts ...[]byte) string {
+	h := sha256.New()
+	for _, part := range parts {
+		h.Write(part)
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// idempotencyResponseWriter 把响应写给客户端，同时记录处理函数写入的响应头与不超过 maxBody 字节的响应体
+type idempotencyResponseWriter struct {
+	http.ResponseWriter
+	before      http.Header // 外层中间件写入的响应头（如 X-Request-ID），不保存
+	header      http.Header
+	status      int
+	body        bytes.Buffer
+	maxBody     int
+	overflow    bool // 响应体超过 maxBody，不保存
+	setCookie   bool // 处理函数设置了 Cookie（如登录的会话），不保存
+	hijacked    bool
+	wroteHeader bool
+}
+
+func (w *idempotencyResponseWriter) WriteHeader(code int) {
+	if !w.wroteHeader {
+		w.status = code
+		w.wroteHeader = true
+		w.header = make(http.Header)
+		for k, v := range w.ResponseWriter.Header() {
+			if k == "Content-Length" || slices.Equal(w.before[k], v) {
+				continue
+			}
+			if k == "Set-Cookie" {
+				w.setCookie = true
+				continue
+			}
+			w.header[k] = slices.Clone(v)
+		}
+	}
+	w.ResponseWriter.WriteHeader(code)
+}
+
+func (w *idempotencyResponseWriter) Write(b []byte) (int, error) {
+	if !w.wroteHeader {
+		w.WriteHeader(http.StatusOK)
+	}
+	if !w.overflow {
+		if w.body.Len()+len(b) > w.maxBody {
+			w.overflow = true
+			w.body.Reset()
+		} else {
+			w.body.Write(b)
+		}
+	}
+	return w.ResponseWriter.Write(b)
+}
+
+// storable 响应是否保存：5xx 以及鉴权失败、超时、冲突与限流的响应不保存，客户端可以重试；
+// 设置 Cookie 或 Cache-Control: no-store 的响应（如返回令牌的登录）不保存，重放会把凭证交给使用同一个幂等键的请求
+func (w *idempotencyResponseWriter) storable() bool {
+	if w.overflow || w.hijacked || w.setCookie || w.status >= 500 {
+		return false
+	}
+	if strings.Contains(strings.ToLower(w.header.Get("Cache-Control")), "no-store") {
+		return false
+	}
+	switch w.status {
+	case http.StatusUnauthorized, http.StatusForbidden, http.StatusRequestTimeout, http.StatusConflict, http.StatusTooManyRequests:
+		return false
+	}
+	return true
+}
+
+func (w *idempotencyResponseWriter) Flush() {
+	if !w.wroteHeader {
+		w.WriteHeader(http.StatusOK)
+	}
+	if flusher, ok := w.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+func (w *idempotencyResponseWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	hijacker, ok := w.ResponseWriter.(http.Hijacker)
+	if !ok {
+		return nil, nil, fmt.Errorf("ResponseWriter 不支持 Hijack")
+	}
+	w.hijacked = true
+	return hijacker.Hijack()
+}
+
+func (w *idempotencyResponseWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+// MemoryIdempotencyStore 保存在进程内存中的幂等键记录，多个副本之间不共享
+type MemoryIdempotencyStore struct {
+	mu        sync.Mutex
+	records   map[string]memoryIdempotencyEntry
+	lastSweep time.Time
+}
+
+type memoryIdempotencyEntry struct {
+	record  *IdempotencyRecord
+	expires time.Time
+}
+
+// NewMemoryIdempotencyStore 创建内存中的幂等键存储，过期的记录在之后的 Acquire 中清理
+func NewMemoryIdempotencyStore() *MemoryIdempotencyStore {
+	return &MemoryIdempotencyStore{records: make(map[string]memoryIdempotencyEntry), lastSweep: time.Now()}
+}
+
+func (s *MemoryIdempotencyStore) Acquire(ctx context.Context, key string, record *IdempotencyRecord, ttl time.Duration) (*IdempotencyRecord, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	if now.Sub(s.lastSweep) >= time.Minute {
+		for k, entry := range s.records {
+			if now.After(entry.expires) {
+				delete(s.records, k)
+			}
+		}
+		s.lastSweep = now
+	}
+	if entry, ok := s.records[key]; ok && now.Before(entry.expires) {
+		existing := *entry.record
+		return &existing, nil
+	}
+	// 保存副本，调用方之后修改 record 不影响已保存的记录
+	stored := *record
+	s.records[key] = memoryIdempotencyEntry{record: &stored, expires: now.Add(ttl)}
+	return nil, nil
+}
+
+func (s *MemoryIdempotencyStore) Complete(ctx context.Context, key string, record *IdempotencyRecord, ttl time.Duration) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if entry, ok := s.records[key]; ok && entry.record.Token != record.Token && time.Now().Before(entry.expires) {
+		return nil
+	}
+	stored := *record
+	s.records[key] = memoryIdempotencyEntry{record: &stored, expires: time.Now().Add(ttl)}
+	return nil
+}
+
+func (s *MemoryIdempotencyStore) Release(ctx context.Context, key, token string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if entry, ok := s.records[key]; ok && entry.record.Token == token {
+		delete(s.records, key)
+	}
+	return nil
+}
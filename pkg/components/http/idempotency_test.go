@@ -0,0 +1,276 @@
+package http
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stones-hub/taurus-pro-config/pkg/config"
+)
+
+// newTestConfig 把 content 写入临时目录中的 http.yaml 并加载
+func newTestConfig(t *testing.T, content string) *config.Config {
+	t.Helper()
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "http.yaml"), []byte(content), 0o644); err != nil {
+		t.Fatalf("Failed to write config: %v", err)
+	}
+	env := filepath.Join(t.TempDir(), ".env")
+	if err := os.WriteFile(env, nil, 0o644); err != nil {
+		t.Fatalf("Failed to write env: %v", err)
+	}
+	cfg := config.New()
+	if err := cfg.Initialize(dir, env); err != nil {
+		t.Fatalf("Failed to load config: %v", err)
+	}
+	return cfg
+}
+
+// newIdempotencyHandler 返回经过 idempotency 中间件的 handler
+func newIdempotencyHandler(t *testing.T, handler http.Handler) http.Handler {
+	t.Helper()
+	cfg := newTestConfig(t, `
+http:
+  idempotency:
+    ttl: 60
+    skip: [/auth/]
+`)
+	middleware, err := IdempotencyMiddleware(cfg, nil)
+	if err != nil {
+		t.Fatalf("Failed to create idempotency middleware: %v", err)
+	}
+	return middleware(handler)
+}
+
+// idempotencyRequest 带 Authorization 与幂等键的 POST 请求
+func idempotencyRequest(path, key, body string) *http.Request {
+	req := httptest.NewRequest(http.MethodPost, path, strings.NewReader(body))
+	req.Header.Set("Authorization", "Bearer user-1")
+	req.Header.Set("Idempotency-Key", key)
+	return req
+}
+
+func TestIdempotencyConcurrentRequests(t *testing.T) {
+	var calls atomic.Int32
+	entered, release := make(chan struct{}), make(chan struct{})
+	handler := newIdempotencyHandler(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) == 1 {
+			close(entered)
+			<-release
+		}
+		w.WriteHeader(http.StatusCreated)
+		_, _ = w.Write([]byte("created"))
+	}))
+
+	first := httptest.NewRecorder()
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		handler.ServeHTTP(first, idempotencyRequest("/users", "key-1", `{"name":"a"}`))
+	}()
+	<-entered
+
+	// 第一个请求处理中时，相同幂等键的请求返回 409，不执行处理函数
+	var wg sync.WaitGroup
+	codes := make([]int, 8)
+	retryAfter := make([]string, 8)
+	for i := range codes {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, idempotencyRequest("/users", "key-1", `{"name":"a"}`))
+			codes[i], retryAfter[i] = rec.Code, rec.Header().Get("Retry-After")
+		}(i)
+	}
+	wg.Wait()
+	for i, code := range codes {
+		if code != http.StatusConflict || retryAfter[i] == "" {
+			t.Errorf("Expected 409 with Retry-After while in flight, got %d %q", code, retryAfter[i])
+		}
+	}
+
+	close(release)
+	<-done
+	if first.Code != http.StatusCreated {
+		t.Fatalf("Expected 201 for the first request, got %d", first.Code)
+	}
+
+	// 完成之后重放保存的响应
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, idempotencyRequest("/users", "key-1", `{"name":"a"}`))
+	if rec.Code != http.StatusCreated || rec.Body.String() != "created" || rec.Header().Get(IdempotentReplayedHeader) != "true" {
+		t.Errorf("Expected replayed 201, got %d %q %q", rec.Code, rec.Body.String(), rec.Header().Get(IdempotentReplayedHeader))
+	}
+	if n := calls.Load(); n != 1 {
+		t.Errorf("Expected handler to run once, got %d", n)
+	}
+}
+
+func TestIdempotencyPayloadMismatch(t *testing.T) {
+	var calls atomic.Int32
+	handler := newIdempotencyHandler(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.WriteHeader(http.StatusCreated)
+	}))
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, idempotencyRequest("/users", "key-1", `{"name":"a"}`))
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("Expected 201, got %d", rec.Code)
+	}
+
+	// 请求体、URI 不同时返回 422
+	for _, req := range []*http.Request{
+		idempotencyRequest("/users", "key-1", `{"name":"b"}`),
+		idempotencyRequest("/roles", "key-1", `{"name":"a"}`),
+	} {
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		if rec.Code != http.StatusUnprocessableEntity {
+			t.Errorf("Expected 422 for %s, got %d", req.URL.Path, rec.Code)
+		}
+	}
+
+	// 不同调用方的相同幂等键互不影响
+	req := idempotencyRequest("/users", "key-1", `{"name":"b"}`)
+	req.Header.Set("Authorization", "Bearer user-2")
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	if rec.Code != http.StatusCreated || rec.Header().Get(IdempotentReplayedHeader) != "" {
+		t.Errorf("Expected 201 for another scope, got %d", rec.Code)
+	}
+	if n := calls.Load(); n != 2 {
+		t.Errorf("Expected handler to run twice, got %d", n)
+	}
+}
+
+func TestIdempotencySkipped(t *testing.T) {
+	var calls atomic.Int32
+	handler := newIdempotencyHandler(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+	}))
+
+	anonymous := func() *http.Request {
+		req := idempotencyRequest("/users", "key-1", "")
+		req.Header.Del("Authorization")
+		return req
+	}
+	tests := []struct {
+		name string
+		req  func() *http.Request
+	}{
+		{"anonymous", anonymous},
+		{"skip prefix", func() *http.Request { return idempotencyRequest("/auth/login", "key-1", "") }},
+		{"GET", func() *http.Request {
+			req := idempotencyRequest("/users", "key-1", "")
+			req.Method = http.MethodGet
+			return req
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			before := calls.Load()
+			for i := 0; i < 2; i++ {
+				rec := httptest.NewRecorder()
+				handler.ServeHTTP(rec, tt.req())
+				if rec.Header().Get(IdempotentReplayedHeader) != "" {
+					t.Errorf("Expected no replay")
+				}
+			}
+			if n := calls.Load() - before; n != 2 {
+				t.Errorf("Expected handler to run twice, got %d", n)
+			}
+		})
+	}
+}
+
+func TestIdempotencyStorable(t *testing.T) {
+	tests := []struct {
+		status int
+		header http.Header
+		want   bool
+	}{
+		{http.StatusOK, nil, true},
+		{http.StatusCreated, nil, true},
+		{http.StatusBadRequest, nil, true},
+		{http.StatusNotFound, nil, true},
+		{http.StatusUnprocessableEntity, nil, true},
+		{http.StatusUnauthorized, nil, false},
+		{http.StatusForbidden, nil, false},
+		{http.StatusRequestTimeout, nil, false},
+		{http.StatusConflict, nil, false},
+		{http.StatusTooManyRequests, nil, false},
+		{http.StatusInternalServerError, nil, false},
+		{http.StatusServiceUnavailable, nil, false},
+		{http.StatusOK, http.Header{"Cache-Control": {"no-store"}}, false},
+		{http.StatusOK, http.Header{"Set-Cookie": {"session=1"}}, false},
+	}
+	for _, tt := range tests {
+		var calls atomic.Int32
+		handler := newIdempotencyHandler(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			calls.Add(1)
+			for k, v := range tt.header {
+				w.Header()[k] = v
+			}
+			w.WriteHeader(tt.status)
+		}))
+		for i := 0; i < 2; i++ {
+			handler.ServeHTTP(httptest.NewRecorder(), idempotencyRequest("/users", "key-1", ""))
+		}
+
+		// 保存的响应重放，处理函数只执行一次；不保存时释放幂等键，重试时再次执行
+		stored := calls.Load() == 1
+		if stored != tt.want {
+			t.Errorf("Expected storable=%v for %d %v, got %v", tt.want, tt.status, tt.header, stored)
+		}
+	}
+}
+
+func TestMemoryIdempotencyStore(t *testing.T) {
+	ctx := context.Background()
+	store := NewMemoryIdempotencyStore()
+
+	if existing, err := store.Acquire(ctx, "key", &IdempotencyRecord{Token: "a"}, 20*time.Millisecond); err != nil || existing != nil {
+		t.Fatalf("Expected to acquire a new key, got %v %v", existing, err)
+	}
+	existing, _ := store.Acquire(ctx, "key", &IdempotencyRecord{Token: "b"}, 20*time.Millisecond)
+	if existing == nil || existing.Token != "a" {
+		t.Fatalf("Expected the record of token a, got %v", existing)
+	}
+
+	// 其它请求不能保存或释放占用中的幂等键
+	_ = store.Complete(ctx, "key", &IdempotencyRecord{Token: "b", Done: true}, time.Minute)
+	_ = store.Release(ctx, "key", "b")
+	if existing, _ := store.Acquire(ctx, "key", &IdempotencyRecord{Token: "c"}, time.Minute); existing == nil || existing.Token != "a" || existing.Done {
+		t.Fatalf("Expected the key to stay with token a, got %v", existing)
+	}
+
+	// 过期之后可以重新占用
+	time.Sleep(30 * time.Millisecond)
+	if existing, _ := store.Acquire(ctx, "key", &IdempotencyRecord{Token: "d"}, time.Minute); existing != nil {
+		t.Fatalf("Expected expired key to be acquired again, got %v", existing)
+	}
+
+	// 保存的完成记录在 ttl 内有效
+	_ = store.Complete(ctx, "key", &IdempotencyRecord{Token: "d", Done: true, Status: http.StatusCreated}, 20*time.Millisecond)
+	if existing, _ := store.Acquire(ctx, "key", &IdempotencyRecord{Token: "e"}, time.Minute); existing == nil || !existing.Done || existing.Status != http.StatusCreated {
+		t.Fatalf("Expected completed record, got %v", existing)
+	}
+	time.Sleep(30 * time.Millisecond)
+	if existing, _ := store.Acquire(ctx, "key", &IdempotencyRecord{Token: "f"}, time.Minute); existing != nil {
+		t.Fatalf("Expected completed record to expire, got %v", existing)
+	}
+
+	_ = store.Release(ctx, "key", "f")
+	if existing, _ := store.Acquire(ctx, "key", &IdempotencyRecord{Token: "g"}, time.Minute); existing != nil {
+		t.Fatalf("Expected released key to be acquired again, got %v", existing)
+	}
+}
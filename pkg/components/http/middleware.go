@@ -0,0 +1,388 @@
+package http
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"log"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/stones-hub/taurus-pro-config/pkg/config"
+	"github.com/stones-hub/taurus-pro-core/pkg/components/common"
+	"github.com/stones-hub/taurus-pro-http/pkg/middleware"
+	"github.com/stones-hub/taurus-pro-http/pkg/router"
+)
+
+// MiddlewareFactory 按配置创建中间件，返回 nil 时不使用该中间件（如超时时间配置为 0）
+type MiddlewareFactory func(cfg *config.Config) (router.MiddlewareFunc, error)
+
+// middlewareOverride 按路径前缀覆盖全局中间件
+type middlewareOverride struct {
+	prefix string
+	chain  []string        // 不为 nil 时替换全局中间件
+	skip   map[string]bool // 跳过的中间件
+}
+
+// MiddlewareRegistry 是生成项目中 internal/taurus/middleware.go 的 HttpMiddlewareRegistry 的对应实现
+// 按名称注册的 http 中间件，http.middleware.chain 按顺序声明作用于所有路由的全局中间件，
+// http.middleware.overrides 按路径前缀替换或跳过其中的中间件
+// 内置 recovery、request_id、access_log、cors、compression、body_limit、idempotency、timeout，应用注册自己的中间件（如 pkg/middleware 中的 rate_limit）后同样可以在配置中引用
+// 每个中间件只创建一次，所有路由共用同一个实例（如限流器的令牌桶）
+type MiddlewareRegistry struct {
+	cfg       *config.Config
+	chain     []string
+	overrides []middlewareOverride
+
+	mu        sync.Mutex
+	listeners []string // http.servers 中监听的 middleware 与路由通过 Named 引用的中间件，由 Validate 检查
+	factories map[string]MiddlewareFactory
+	built     map[string]router.MiddlewareFunc
+}
+
+// NewMiddlewareRegistry 创建注册了内置中间件的注册表，读取 http.middleware 中的全局中间件与按路径前缀的覆盖
+// access_log 写入 loggers 中 http.access_log.logger 指定的日志，每个请求读取当前的日志管理器，重载后写入新的管理器
+func NewMiddlewareRegistry(cfg *config.Config, loggers *common.LoggerAccessor) (*MiddlewareRegistry, error) {
+	r := &MiddlewareRegistry{
+		cfg:       cfg,
+		chain:     cfg.GetStringSlice("http.middleware.chain"),
+		factories: make(map[string]MiddlewareFactory),
+		built:     make(map[string]router.MiddlewareFunc),
+	}
+	r.Register("recovery", recoveryMiddleware)
+	r.Register("request_id", requestIDMiddleware)
+	r.Register("body_limit", bodyLimitMiddleware)
+	r.Register("timeout", timeoutMiddleware)
+	r.Register("access_log", accessLogMiddleware(loggers))
+	r.Register("cors", corsMiddleware)
+	r.Register("compression", compressionMiddleware)
+	r.Register("idempotency", idempotencyMiddleware)
+
+	list, _ := cfg.Get("http.middleware.overrides").([]interface{})
+	for _, raw := range list {
+		item, _ := raw.(map[string]interface{})
+		prefix, _ := item["prefix"].(string)
+		if prefix == "" {
+			return nil, fmt.Errorf("http.middleware.overrides 中的覆盖缺少 prefix 配置")
+		}
+		override := middlewareOverride{prefix: prefix, skip: make(map[string]bool)}
+		if chain, ok := item["chain"]; ok {
+			override.chain = append([]string{}, middlewareNames(chain)...)
+		}
+		for _, name := range middlewareNames(item["skip"]) {
+			override.skip[name] = true
+		}
+		r.overrides = append(r.overrides, override)
+	}
+	// 最长的前缀优先匹配
+	sort.SliceStable(r.overrides, func(i, j int) bool {
+		return len(r.overrides[i].prefix) > len(r.overrides[j].prefix)
+	})
+	return r, nil
+}
+
+// Register 注册中间件，替换同名的中间件（包括内置中间件），需要在 http 服务启动之前调用
+func (r *MiddlewareRegistry) Register(name string, factory MiddlewareFactory) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.factories[name] = factory
+	delete(r.built, name)
+}
+
+// For 返回路径为 path 的路由使用的全局中间件，路径没有使用任何全局中间件时返回 nil
+// 中间件在第一次请求时才创建并组合，路由可以在应用注册中间件之前注册（如 metrics 的 /metrics）；
+// 名称没有注册或创建失败时请求返回 500，而不是跳过中间件（如鉴权），http 服务启动前由 Validate 检查
+func (r *MiddlewareRegistry) For(path string) router.MiddlewareFunc {
+	if r == nil {
+		return nil
+	}
+	names := r.names(path)
+	if len(names) == 0 {
+		return nil
+	}
+	return r.lazy(names)
+}
+
+// Listener 返回 http.servers 中监听的 middleware 配置按顺序组合的中间件，没有配置时返回 nil
+// 与 For 相同在第一次请求时才创建，应用在监听创建之后注册的中间件（如 pkg/middleware 中的 auth）同样可以引用
+func (r *MiddlewareRegistry) Listener(names []string) router.MiddlewareFunc {
+	return r.Named(names...)
+}
+
+// Named 返回按名称引用注册的中间件并按顺序组合的中间件，用于单个路由的 Middleware，没有名称时返回 nil
+// 引用的是注册表中唯一的实例，多个路由引用 rate_limit 时共用同一个限流器与同一个配置重载
+func (r *MiddlewareRegistry) Named(names ...string) router.MiddlewareFunc {
+	if r == nil || len(names) == 0 {
+		return nil
+	}
+	r.mu.Lock()
+	r.listeners = append(r.listeners, names...)
+	r.mu.Unlock()
+	return r.lazy(names)
+}
+
+// lazy 返回在第一次请求时按 names 创建并组合的中间件，创建失败时请求返回 500
+func (r *MiddlewareRegistry) lazy(names []string) router.MiddlewareFunc {
+	return func(next http.Handler) http.Handler {
+		var (
+			once    sync.Once
+			handler http.Handler
+		)
+		return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			once.Do(func() {
+				chain, err := r.resolve(names)
+				if err != nil {
+					log.Printf("%s🔗 -> %v %s\n", "\033[31m", err, "\033[0m")
+					handler = http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+						http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+					})
+					return
+				}
+				handler = next
+				for i := len(chain) - 1; i >= 0; i-- {
+					handler = chain[i](handler)
+				}
+			})
+			handler.ServeHTTP(w, req)
+		})
+	}
+}
+
+// Validate 创建全局中间件、overrides 与监听的 middleware 中引用的所有中间件，返回第一个错误，http 服务启动时调用
+func (r *MiddlewareRegistry) Validate() error {
+	if r == nil {
+		return nil
+	}
+	names := append([]string{}, r.chain...)
+	for _, override := range r.overrides {
+		names = append(names, override.chain...)
+	}
+	r.mu.Lock()
+	names = append(names, r.listeners...)
+	r.mu.Unlock()
+	_, err := r.resolve(names)
+	return err
+}
+
+// names 返回路径使用的全局中间件名称，最长的匹配前缀优先
+func (r *MiddlewareRegistry) names(path string) []string {
+	names, skip := r.chain, map[string]bool{}
+	for _, override := range r.overrides {
+		if strings.HasPrefix(path, override.prefix) {
+			if override.chain != nil {
+				names = override.chain
+			}
+			skip = override.skip
+			break
+		}
+	}
+
+	result := make([]string, 0, len(names))
+	for _, name := range names {
+		if !skip[name] {
+			result = append(result, name)
+		}
+	}
+	return result
+}
+
+// resolve 按名称创建中间件，排在前面的在外层，工厂返回 nil 的中间件不使用
+func (r *MiddlewareRegistry) resolve(names []string) ([]router.MiddlewareFunc, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	chain := make([]router.MiddlewareFunc, 0, len(names))
+	for _, name := range names {
+		middleware, err := r.build(name)
+		if err != nil {
+			return nil, err
+		}
+		if middleware != nil {
+			chain = append(chain, middleware)
+		}
+	}
+	return chain, nil
+}
+
+// build 创建中间件，已经创建的直接返回，调用方需要持有锁
+func (r *MiddlewareRegistry) build(name string) (router.MiddlewareFunc, error) {
+	if middleware, ok := r.built[name]; ok {
+		return middleware, nil
+	}
+	factory, ok := r.factories[name]
+	if !ok {
+		return nil, fmt.Errorf("http 中间件没有注册: %s", name)
+	}
+	middleware, err := factory(r.cfg)
+	if err != nil {
+		return nil, fmt.Errorf("创建 http 中间件 %s 失败: %v", name, err)
+	}
+	r.built[name] = middleware
+	return middleware, nil
+}
+
+// middlewareNames 转换配置中的中间件名称列表
+func middlewareNames(raw interface{}) []string {
+	list, _ := raw.([]interface{})
+	names := make([]string, 0, len(list))
+	for _, item := range list {
+		if name, ok := item.(string); ok && name != "" {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// recoveryMiddleware 捕获处理请求时的 panic，返回 500 并输出堆栈
+func recoveryMiddleware(cfg *config.Config) (router.MiddlewareFunc, error) {
+	return middleware.RecoveryMiddleware(func(err any, stack string) {
+		log.Printf("%s🔗 -> Http panic: %v\n%s%s\n", "\033[31m", err, stack, "\033[0m")
+	}), nil
+}
+
+// RequestIDHeader 请求 ID 的请求头与响应头
+const RequestIDHeader = "X-Request-ID"
+
+type requestIDKey struct{}
+
+// RequestIDFromContext 返回 request_id 中间件放入请求上下文的请求 ID，没有时返回空字符串
+func RequestIDFromContext(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDKey{}).(string)
+	return id
+}
+
+// requestIDMiddleware 沿用请求头中的 X-Request-ID（如网关生成的），没有或无效时生成新的 ID，写入响应头与请求上下文
+func requestIDMiddleware(cfg *config.Config) (router.MiddlewareFunc, error) {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			id := r.Header.Get(RequestIDHeader)
+			if !validRequestID(id) {
+				id = newRequestID()
+			}
+			w.Header().Set(RequestIDHeader, id)
+			next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), requestIDKey{}, id)))
+		})
+	}, nil
+}
+
+// validRequestID 客户端传入的请求 ID 最长 128 个字符，只能包含字母、数字与 - _ . :
+func validRequestID(id string) bool {
+	if id == "" || len(id) > 128 {
+		return false
+	}
+	for _, c := range id {
+		if !(c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || strings.ContainsRune("-_.:", c)) {
+			return false
+		}
+	}
+	return true
+}
+
+// newRequestID 生成 32 位十六进制的请求 ID
+func newRequestID() string {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// bodyLimitMiddleware 限制请求体为 http.limits.body 字节，超过时返回 413，不大于 0 时不限制
+// http.limits.overrides 按路径前缀（匹配最长的前缀）使用不同的限制，如上传接口放宽、回调接口收紧
+func bodyLimitMiddleware(cfg *config.Config) (router.MiddlewareFunc, error) {
+	type bodyLimit struct {
+		prefix string
+		limit  int64
+	}
+	var overrides []bodyLimit
+	list, _ := cfg.Get("http.limits.overrides").([]interface{})
+	for _, raw := range list {
+		item, _ := raw.(map[string]interface{})
+		prefix, _ := item["prefix"].(string)
+		if prefix == "" {
+			return nil, fmt.Errorf("http.limits.overrides 中的覆盖缺少 prefix 配置")
+		}
+		body, err := bodyLimitBytes(item["body"])
+		if err != nil {
+			return nil, fmt.Errorf("http.limits.overrides 中 %s 的 body 配置无效: %v", prefix, err)
+		}
+		overrides = append(overrides, bodyLimit{prefix: prefix, limit: body})
+	}
+	sort.SliceStable(overrides, func(i, j int) bool {
+		return len(overrides[i].prefix) > len(overrides[j].prefix)
+	})
+
+	defaultLimit := int64(cfg.GetInt("http.limits.body"))
+	if defaultLimit <= 0 && len(overrides) == 0 {
+		return nil, nil
+	}
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			limit := defaultLimit
+			for _, override := range overrides {
+				if strings.HasPrefix(r.URL.Path, override.prefix) {
+					limit = override.limit
+					break
+				}
+			}
+			if limit <= 0 {
+				next.ServeHTTP(w, r)
+				return
+			}
+			if r.ContentLength > limit {
+				http.Error(w, http.StatusText(http.StatusRequestEntityTooLarge), http.StatusRequestEntityTooLarge)
+				return
+			}
+			r.Body = http.MaxBytesReader(w, r.Body, limit)
+			next.ServeHTTP(w, r)
+		})
+	}, nil
+}
+
+// bodyLimitBytes 转换 http.limits.overrides 中的 body 配置，YAML 解析为 int，JSON 与 TOML 为 float64 或 int64，
+// 环境变量替换后为字符串；缺少或不是非负整数时返回错误，而不是按 0（不限制）处理
+func bodyLimitBytes(value interface{}) (int64, error) {
+	switch v := value.(type) {
+	case nil:
+		return 0, fmt.Errorf("缺少 body 配置")
+	case int:
+		if v >= 0 {
+			return int64(v), nil
+		}
+	case int64:
+		if v >= 0 {
+			return v, nil
+		}
+	case float64:
+		if v >= 0 && v < 1<<63 && v == float64(int64(v)) {
+			return int64(v), nil
+		}
+	case string:
+		if n, err := strconv.ParseInt(strings.TrimSpace(v), 10, 64); err == nil && n >= 0 {
+			return n, nil
+		}
+	}
+	return 0, fmt.Errorf("需要非负整数，实际为 %v", value)
+}
+
+// timeoutMiddleware 请求处理超过 http.middleware.timeout 秒时返回 503 并取消请求上下文，不大于 0 时不限制
+// websocket 等升级的连接不受限制，SSE 等流式响应需要在 overrides 中跳过
+func timeoutMiddleware(cfg *config.Config) (router.MiddlewareFunc, error) {
+	timeout := time.Duration(cfg.GetInt("http.middleware.timeout")) * time.Second
+	if timeout <= 0 {
+		return nil, nil
+	}
+	return func(next http.Handler) http.Handler {
+		timed := http.TimeoutHandler(next, timeout, http.StatusText(http.StatusServiceUnavailable))
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Header.Get("Upgrade") != "" {
+				next.ServeHTTP(w, r)
+				return
+			}
+			timed.ServeHTTP(w, r)
+		})
+	}, nil
+}
@@ -0,0 +1,348 @@
+package http
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stones-hub/taurus-pro-config/pkg/config"
+	"github.com/stones-hub/taurus-pro-http/pkg/router"
+)
+
+// middlewareTestConfig 全局中间件为 a、b、c，/api 跳过 b，/api/admin 替换为 c、a，/public 不使用全局中间件
+const middlewareTestConfig = `
+http:
+  middleware:
+    chain: [a, b, c]
+    overrides:
+      - prefix: /api
+        skip: [b]
+      - prefix: /api/admin
+        chain: [c, a]
+      - prefix: /public
+        chain: []
+`
+
+// newTestRegistry 创建注册了 a、b、c 的注册表，中间件在 calls 中记录进入与退出的顺序
+func newTestRegistry(t *testing.T, content string, calls *[]string) *MiddlewareRegistry {
+	t.Helper()
+	r, err := NewMiddlewareRegistry(newTestConfig(t, content), nil)
+	if err != nil {
+		t.Fatalf("Failed to create middleware registry: %v", err)
+	}
+	for _, name := range []string{"a", "b", "c"} {
+		r.Register(name, recordingMiddleware(name, calls))
+	}
+	return r
+}
+
+// recordingMiddleware 返回在 calls 中记录 name> 与 <name 的中间件
+func recordingMiddleware(name string, calls *[]string) MiddlewareFactory {
+	return func(cfg *config.Config) (router.MiddlewareFunc, error) {
+		return func(next http.Handler) http.Handler {
+			return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				*calls = append(*calls, name+">")
+				next.ServeHTTP(w, r)
+				*calls = append(*calls, "<"+name)
+			})
+		}, nil
+	}
+}
+
+// serveWith 使用 middleware 处理 path 的请求，返回响应
+func serveWith(middleware router.MiddlewareFunc, path string, calls *[]string) *httptest.ResponseRecorder {
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		*calls = append(*calls, "handler")
+	}))
+	if middleware != nil {
+		handler = middleware(handler)
+	}
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+	return rec
+}
+
+func TestMiddlewareChainOrder(t *testing.T) {
+	var calls []string
+	r := newTestRegistry(t, middlewareTestConfig, &calls)
+
+	// 排在前面的中间件在外层
+	serveWith(r.For("/users"), "/users", &calls)
+	want := []string{"a>", "b>", "c>", "handler", "<c", "<b", "<a"}
+	if !reflect.DeepEqual(calls, want) {
+		t.Errorf("Expected %v, got %v", want, calls)
+	}
+}
+
+func TestMiddlewareOverrides(t *testing.T) {
+	tests := []struct {
+		path string
+		want []string
+	}{
+		{"/users", []string{"a>", "b>", "c>", "handler", "<c", "<b", "<a"}},
+		// /api 跳过 b
+		{"/api/users", []string{"a>", "c>", "handler", "<c", "<a"}},
+		// /api/admin 比 /api 长，按其 chain 替换全局中间件
+		{"/api/admin/users", []string{"c>", "a>", "handler", "<a", "<c"}},
+	}
+	for _, tt := range tests {
+		var calls []string
+		r := newTestRegistry(t, middlewareTestConfig, &calls)
+		serveWith(r.For(tt.path), tt.path, &calls)
+		if !reflect.DeepEqual(calls, tt.want) {
+			t.Errorf("Expected %v for %s, got %v", tt.want, tt.path, calls)
+		}
+	}
+
+	// chain 为空时路由不使用全局中间件
+	var calls []string
+	r := newTestRegistry(t, middlewareTestConfig, &calls)
+	if middleware := r.For("/public/index.html"); middleware != nil {
+		t.Errorf("Expected no middleware for /public, got one")
+	}
+}
+
+func TestMiddlewareOverrideWithoutPrefix(t *testing.T) {
+	cfg := newTestConfig(t, `
+http:
+  middleware:
+    overrides:
+      - skip: [a]
+`)
+	if _, err := NewMiddlewareRegistry(cfg, nil); err == nil || !strings.Contains(err.Error(), "prefix") {
+		t.Errorf("Expected missing prefix error, got %v", err)
+	}
+}
+
+func TestMiddlewareListener(t *testing.T) {
+	var calls []string
+	r := newTestRegistry(t, middlewareTestConfig, &calls)
+
+	if middleware := r.Listener(nil); middleware != nil {
+		t.Errorf("Expected no middleware without names, got one")
+	}
+
+	// 监听的中间件按配置的顺序组合，引用的中间件可以在 Listener 之后注册
+	listener := r.Listener([]string{"auth", "b"})
+	if err := r.Validate(); err == nil || !strings.Contains(err.Error(), "auth") {
+		t.Errorf("Expected unregistered auth error, got %v", err)
+	}
+	r.Register("auth", recordingMiddleware("auth", &calls))
+	if err := r.Validate(); err != nil {
+		t.Fatalf("Expected no error after registering auth, got %v", err)
+	}
+
+	serveWith(listener, "/users", &calls)
+	want := []string{"auth>", "b>", "handler", "<b", "<auth"}
+	if !reflect.DeepEqual(calls, want) {
+		t.Errorf("Expected %v, got %v", want, calls)
+	}
+}
+
+func TestMiddlewareNamed(t *testing.T) {
+	var calls []string
+	r := newTestRegistry(t, middlewareTestConfig, &calls)
+
+	if middleware := r.Named(); middleware != nil {
+		t.Errorf("Expected no middleware without names, got one")
+	}
+
+	// 多个路由与全局中间件引用同一个名称时只创建一个实例
+	created := 0
+	r.Register("limit", func(cfg *config.Config) (router.MiddlewareFunc, error) {
+		created++
+		return recordingMiddleware("limit", &calls)(cfg)
+	})
+	first, second := r.Named("limit", "b"), r.Named("limit")
+	if err := r.Validate(); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	serveWith(first, "/login", &calls)
+	serveWith(second, "/register", &calls)
+	want := []string{"limit>", "b>", "handler", "<b", "<limit", "limit>", "handler", "<limit"}
+	if !reflect.DeepEqual(calls, want) {
+		t.Errorf("Expected %v, got %v", want, calls)
+	}
+	if created != 1 {
+		t.Errorf("Expected limit created once, got %d", created)
+	}
+
+	// Validate 检查路由引用的名称
+	r.Named("unknown")
+	if err := r.Validate(); err == nil || !strings.Contains(err.Error(), "unknown") {
+		t.Errorf("Expected unregistered unknown error, got %v", err)
+	}
+}
+
+func TestMiddlewareUnregistered(t *testing.T) {
+	var calls []string
+	r := newTestRegistry(t, `
+http:
+  middleware:
+    chain: [a, missing]
+`, &calls)
+
+	if err := r.Validate(); err == nil || !strings.Contains(err.Error(), "missing") {
+		t.Errorf("Expected unregistered missing error, got %v", err)
+	}
+
+	// 没有注册的中间件在请求时返回 500，不跳过中间件执行处理函数
+	rec := serveWith(r.For("/users"), "/users", &calls)
+	if rec.Code != http.StatusInternalServerError {
+		t.Errorf("Expected status 500, got %d", rec.Code)
+	}
+	if len(calls) != 0 {
+		t.Errorf("Expected no calls, got %v", calls)
+	}
+}
+
+func TestMiddlewareRegister(t *testing.T) {
+	var calls []string
+	r := newTestRegistry(t, `
+http:
+  middleware:
+    chain: [request_id, timeout, a]
+    timeout: 0
+`, &calls)
+
+	// 替换内置中间件，工厂只调用一次
+	var built int
+	r.Register("request_id", func(cfg *config.Config) (router.MiddlewareFunc, error) {
+		built++
+		return recordingMiddleware("request_id", &calls)(cfg)
+	})
+
+	// timeout 为 0 时工厂返回 nil，不使用该中间件
+	serveWith(r.For("/users"), "/users", &calls)
+	serveWith(r.For("/orders"), "/orders", &calls)
+	want := []string{
+		"request_id>", "a>", "handler", "<a", "<request_id",
+		"request_id>", "a>", "handler", "<a", "<request_id",
+	}
+	if !reflect.DeepEqual(calls, want) {
+		t.Errorf("Expected %v, got %v", want, calls)
+	}
+	if built != 1 {
+		t.Errorf("Expected factory called once, got %d", built)
+	}
+}
+
+// newBodyLimitHandler 返回经过 body_limit 中间件的 handler，响应为读取到的字节数或读取失败时的 413
+func newBodyLimitHandler(t *testing.T, content string) http.Handler {
+	t.Helper()
+	middleware, err := bodyLimitMiddleware(newTestConfig(t, content))
+	if err != nil {
+		t.Fatalf("Failed to create body limit middleware: %v", err)
+	}
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			w.WriteHeader(http.StatusRequestEntityTooLarge)
+			return
+		}
+		_, _ = io.WriteString(w, strconv.Itoa(len(body)))
+	}))
+	if middleware != nil {
+		handler = middleware(handler)
+	}
+	return handler
+}
+
+func TestBodyLimit(t *testing.T) {
+	handler := newBodyLimitHandler(t, `
+http:
+  limits:
+    body: 16
+    overrides:
+      - prefix: /upload
+        body: 64
+      - prefix: /upload/raw
+        body: 0
+`)
+	tests := []struct {
+		path    string
+		size    int
+		chunked bool
+		want    int
+	}{
+		{"/users", 16, false, http.StatusOK},
+		{"/users", 17, false, http.StatusRequestEntityTooLarge},
+		// 没有 Content-Length 的请求体在读取超过限制时失败
+		{"/users", 17, true, http.StatusRequestEntityTooLarge},
+		{"/upload/avatar", 64, false, http.StatusOK},
+		{"/upload/avatar", 65, true, http.StatusRequestEntityTooLarge},
+		// 最长的前缀优先，0 表示不限制
+		{"/upload/raw", 1024, true, http.StatusOK},
+	}
+	for _, tt := range tests {
+		var body io.Reader = strings.NewReader(strings.Repeat("a", tt.size))
+		if tt.chunked {
+			body = io.MultiReader(body)
+		}
+		req := httptest.NewRequest(http.MethodPost, tt.path, body)
+		if tt.chunked {
+			req.ContentLength = -1
+		}
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		if rec.Code != tt.want {
+			t.Errorf("Expected status %d for %d bytes to %s, got %d", tt.want, tt.size, tt.path, rec.Code)
+		}
+		if tt.want == http.StatusOK && rec.Body.String() != strconv.Itoa(tt.size) {
+			t.Errorf("Expected %d bytes read from %s, got %s", tt.size, tt.path, rec.Body.String())
+		}
+	}
+}
+
+func TestBodyLimitDisabled(t *testing.T) {
+	middleware, err := bodyLimitMiddleware(newTestConfig(t, "http: {}\n"))
+	if err != nil || middleware != nil {
+		t.Errorf("Expected no middleware without limits, got %v, %v", middleware != nil, err)
+	}
+
+	_, err = bodyLimitMiddleware(newTestConfig(t, `
+http:
+  limits:
+    overrides:
+      - body: 10
+`))
+	if err == nil || !strings.Contains(err.Error(), "prefix") {
+		t.Errorf("Expected missing prefix error, got %v", err)
+	}
+}
+
+func TestBodyLimitOverrideValues(t *testing.T) {
+	// 浮点数（如 JSON 配置中的数字）与字符串（如环境变量替换后的值）与整数相同按字节数限制
+	for _, body := range []string{"8", "8.0", `"8"`} {
+		middleware, err := bodyLimitMiddleware(newTestConfig(t, bodyLimitOverride(body)))
+		if err != nil || middleware == nil {
+			t.Fatalf("Expected middleware for body %s, got %v", body, err)
+		}
+		rec := httptest.NewRecorder()
+		middleware(http.NotFoundHandler()).ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/upload", strings.NewReader("123456789")))
+		if rec.Code != http.StatusRequestEntityTooLarge {
+			t.Errorf("Expected status 413 for body %s, got %d", body, rec.Code)
+		}
+	}
+
+	// 缺少或无效的 body 返回错误，而不是不限制
+	for _, body := range []string{"", "-1", "1.5", "10MB", "true"} {
+		if _, err := bodyLimitMiddleware(newTestConfig(t, bodyLimitOverride(body))); err == nil || !strings.Contains(err.Error(), "/upload") {
+			t.Errorf("Expected invalid body error for %q, got %v", body, err)
+		}
+	}
+}
+
+// bodyLimitOverride 返回 /upload 的 body 为 body 的配置，body 为空时没有 body 配置
+func bodyLimitOverride(body string) string {
+	content := "http:\n  limits:\n    overrides:\n      - prefix: /upload\n"
+	if body != "" {
+		content += "        body: " + body + "\n"
+	}
+	return content
+}
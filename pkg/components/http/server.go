@@ -0,0 +1,235 @@
+package http
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/stones-hub/taurus-pro-core/pkg/components/instrument"
+	"github.com/stones-hub/taurus-pro-http/pkg/router"
+	"github.com/stones-hub/taurus-pro-http/pkg/server"
+)
+
+// ServeOptions 由标准库 http.Server 监听时的选项，启用 http.tls 或 http.h2c 时使用
+type ServeOptions struct {
+	Addr           string
+	ReadTimeout    time.Duration
+	WriteTimeout   time.Duration
+	IdleTimeout    time.Duration
+	MaxHeaderBytes int
+	TLSConfig      *tls.Config // 不为 nil 时监听 HTTPS，通过 ALPN 同时支持 HTTP/2 与 HTTP/1.1
+	H2C            bool        // 明文端口同时支持 HTTP/2(h2c)，用于 TLS 在网关或服务网格终止的场景
+}
+
+// Server 是生成项目中 internal/taurus/http.go 的 HttpServer 的对应实现
+// 在 server.Server 的基础上，注册路由时自动加上埋点注册表中的 http 埋点中间件
+// 配置了 ServeOptions 时由标准库 http.Server 监听，只提供通过 Server 注册的路由
+type Server struct {
+	*server.Server
+	instrument  *instrument.Registry
+	middlewares *MiddlewareRegistry
+	serve       *ServeOptions
+	std         *http.Server
+
+	mux *http.ServeMux // 已注册的路由，注册时即加上中间件，启动之后注册的路由同样生效
+
+	mu         sync.Mutex
+	err        error                   // 第一个注册失败的路由的错误，Start 时返回
+	middleware []router.MiddlewareFunc // 监听级中间件，由 Use 添加
+}
+
+// NewServer 创建带埋点的 http 服务，middlewares 提供 http.middleware 中配置的全局中间件，serve 为 nil 时由 server.Server 监听
+func NewServer(srv *server.Server, instrument *instrument.Registry, middlewares *MiddlewareRegistry, serve *ServeOptions) *Server {
+	return &Server{Server: srv, instrument: instrument, middlewares: middlewares, serve: serve, mux: http.NewServeMux()}
+}
+
+// DefaultServer 默认监听的名称，即 http 配置中 address、port 对应的监听
+const DefaultServer = "default"
+
+// Servers 按名称索引的 http 监听，默认监听之外的监听由 http.servers 配置
+type Servers map[string]*Server
+
+// Get 返回名称对应的监听，没有配置该名称时返回默认监听
+// 路由可以固定注册到 admin、internal 等监听，没有单独配置端口的环境中与其他路由共用默认端口
+func (s Servers) Get(name string) *Server {
+	if srv, ok := s[name]; ok {
+		return srv
+	}
+	return s[DefaultServer]
+}
+
+// Start 开始监听，运行期间的错误发送到 errChan
+// 由标准库 http.Server 监听时启动之后注册的路由同样生效，端口被占用等错误同样发送到 errChan
+func (s *Server) Start(errChan chan error) {
+	// 全局中间件没有注册或创建失败时不启动，避免在缺少中间件（如鉴权、限流）的情况下提供服务
+	if err := s.middlewares.Validate(); err != nil {
+		errChan <- err
+		return
+	}
+	// 路由重复或冲突时同样不启动，调用方可能没有检查 AddRouter 返回的错误
+	s.mu.Lock()
+	err := s.err
+	s.mu.Unlock()
+	if err != nil {
+		errChan <- err
+		return
+	}
+	if s.serve == nil {
+		s.Server.Start(errChan)
+		return
+	}
+
+	protocols := new(http.Protocols)
+	protocols.SetHTTP1(true)
+	protocols.SetHTTP2(s.serve.TLSConfig != nil)
+	protocols.SetUnencryptedHTTP2(s.serve.H2C)
+
+	srv := &http.Server{
+		Addr:           s.serve.Addr,
+		Handler:        s.mux,
+		ReadTimeout:    s.serve.ReadTimeout,
+		WriteTimeout:   s.serve.WriteTimeout,
+		IdleTimeout:    s.serve.IdleTimeout,
+		MaxHeaderBytes: s.serve.MaxHeaderBytes,
+		TLSConfig:      s.serve.TLSConfig,
+		Protocols:      protocols,
+	}
+	s.mu.Lock()
+	s.std = srv
+	s.mu.Unlock()
+
+	ln, err := net.Listen("tcp", srv.Addr)
+	if err != nil {
+		errChan <- err
+		return
+	}
+	go func() {
+		if srv.TLSConfig != nil {
+			err = srv.ServeTLS(ln, "", "")
+		} else {
+			err = srv.Serve(ln)
+		}
+		if !errors.Is(err, http.ErrServerClosed) {
+			errChan <- err
+		}
+	}()
+}
+
+// Shutdown 停止监听并等待处理中的请求完成
+func (s *Server) Shutdown(ctx context.Context) error {
+	if s.serve == nil {
+		return s.Server.Shutdown(ctx)
+	}
+
+	s.mu.Lock()
+	srv := s.std
+	s.mu.Unlock()
+	if srv == nil {
+		return nil
+	}
+	return srv.Shutdown(ctx)
+}
+
+// Use 添加监听级中间件，作用于之后注册到该监听的所有路由，位于全局中间件之内、路由与路由组中间件之外
+// 如只在管理端口上校验来源 IP，不影响其他监听上的路由
+func (s *Server) Use(middleware ...router.MiddlewareFunc) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.middleware = append(s.middleware, middleware...)
+}
+
+// AddRouter 注册路由，中间件由外到内依次为：埋点、http.middleware 中的全局中间件、Use 添加的监听级中间件、路由的中间件
+// 埋点位于最外层，可以统计到中间件拦截的请求（如 401、429）
+// 路径与已注册的路由重复或冲突时返回错误且不注册，Start 时同样返回该错误
+func (s *Server) AddRouter(r router.Router) error {
+	r.Middleware = s.chain(r.Path, r.Middleware)
+	r = s.instrumented(r.Path, r)
+	if err := s.record(r); err != nil {
+		return err
+	}
+	s.Server.AddRouter(r)
+	return nil
+}
+
+// AddRouterWithoutInstrument 注册不加埋点的路由，如 /metrics 自身的请求不计入 http 指标
+func (s *Server) AddRouterWithoutInstrument(r router.Router) error {
+	r.Middleware = s.chain(r.Path, r.Middleware)
+	if err := s.record(r); err != nil {
+		return err
+	}
+	s.Server.AddRouter(r)
+	return nil
+}
+
+// AddRouterGroup 注册路由组，每个路由以 前缀+路径 注册，路由组的中间件位于路由的中间件之外，
+// 全局中间件按完整路径匹配 http.middleware.overrides，与单独注册的路由相同
+// 注册失败的路由不影响路由组中的其他路由，返回所有失败的错误
+func (s *Server) AddRouterGroup(group router.RouteGroup) error {
+	var errs []error
+	for _, r := range group.Routes {
+		r.Path = group.Prefix + r.Path
+		r.Middleware = append(append([]router.MiddlewareFunc{}, group.Middleware...), r.Middleware...)
+		if err := s.AddRouter(r); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Handler 返回由已注册的路由构建的 http.Handler，中间件的顺序与 server.Server 一致（排在前面的在外层），
+// 不需要监听端口即可通过 httptest 测试真实的路由
+func (s *Server) Handler() http.Handler {
+	return s.mux
+}
+
+// record 加上中间件后将路由注册到 s.mux，路径重复、冲突或格式错误时返回错误，第一个错误记录到 s.err
+func (s *Server) record(r router.Router) (err error) {
+	var handler http.Handler = r.Handler
+	for i := len(r.Middleware) - 1; i >= 0; i-- {
+		handler = r.Middleware[i](handler)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	// http.ServeMux 遇到这些路径时 panic，在注册时转换为错误，而不是在请求中 panic
+	defer func() {
+		if p := recover(); p != nil {
+			err = fmt.Errorf("注册路由 %s 失败: %v", r.Path, p)
+			if s.err == nil {
+				s.err = err
+			}
+		}
+	}()
+	s.mux.Handle(r.Path, handler)
+	return nil
+}
+
+// chain 在路由的中间件前加上全局中间件与监听级中间件
+func (s *Server) chain(path string, middleware []router.MiddlewareFunc) []router.MiddlewareFunc {
+	var chain []router.MiddlewareFunc
+	if global := s.middlewares.For(path); global != nil {
+		chain = append(chain, global)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if len(chain) == 0 && len(s.middleware) == 0 {
+		return middleware
+	}
+	return append(append(chain, s.middleware...), middleware...)
+}
+
+// instrumented 将埋点中间件加到路由中间件的最前面
+func (s *Server) instrumented(route string, r router.Router) router.Router {
+	middleware := s.instrument.HttpMiddleware(route)
+	if middleware == nil {
+		return r
+	}
+	r.Middleware = append([]router.MiddlewareFunc{middleware}, r.Middleware...)
+	return r
+}
@@ -0,0 +1,95 @@
+package http
+
+import (
+	"crypto/tls"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stones-hub/taurus-pro-core/pkg/components/instrument"
+	"github.com/stones-hub/taurus-pro-http/pkg/router"
+	"github.com/stones-hub/taurus-pro-http/pkg/server"
+)
+
+// newTestServer 创建由标准库 http.Server 监听 TLS 的服务
+func newTestServer(t *testing.T) *Server {
+	t.Helper()
+	middlewares, err := NewMiddlewareRegistry(newTestConfig(t, "http: {}\n"), nil)
+	if err != nil {
+		t.Fatalf("Failed to create middleware registry: %v", err)
+	}
+	serve := &ServeOptions{Addr: "127.0.0.1:0", TLSConfig: &tls.Config{}}
+	return NewServer(server.NewServer(), instrument.NewRegistry(), middlewares, serve)
+}
+
+// textRoute 返回响应为 body 的路由
+func textRoute(path, body string) router.Router {
+	return router.Router{
+		Path: path,
+		Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			_, _ = io.WriteString(w, body)
+		}),
+	}
+}
+
+func TestServerDuplicateRoute(t *testing.T) {
+	s := newTestServer(t)
+	if err := s.AddRouter(textRoute("/users/list", "first")); err != nil {
+		t.Fatalf("Failed to add router: %v", err)
+	}
+
+	// 路由组的 前缀+路径 与已注册的路由重复，注册时返回错误，路由组中的其他路由照常注册
+	err := s.AddRouterGroup(router.RouteGroup{
+		Prefix: "/users",
+		Routes: []router.Router{textRoute("/list", "second"), textRoute("/detail", "detail")},
+	})
+	if err == nil || !strings.Contains(err.Error(), "/users/list") {
+		t.Errorf("Expected duplicate /users/list error, got %v", err)
+	}
+
+	// 请求不会 panic，之后的请求照常处理
+	for i := 0; i < 2; i++ {
+		for path, want := range map[string]string{"/users/list": "first", "/users/detail": "detail"} {
+			rec := httptest.NewRecorder()
+			s.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+			if rec.Code != http.StatusOK || rec.Body.String() != want {
+				t.Errorf("Expected %s from %s, got %d %q", want, path, rec.Code, rec.Body.String())
+			}
+		}
+	}
+
+	// 调用方没有检查错误时，启动失败
+	errChan := make(chan error, 1)
+	s.Start(errChan)
+	select {
+	case err := <-errChan:
+		if !strings.Contains(err.Error(), "/users/list") {
+			t.Errorf("Expected duplicate /users/list error, got %v", err)
+		}
+	default:
+		t.Errorf("Expected start to fail")
+		_ = s.Shutdown(t.Context())
+	}
+}
+
+func TestServerInvalidRoute(t *testing.T) {
+	s := newTestServer(t)
+	if err := s.AddRouter(textRoute("/users/{id}", "user")); err != nil {
+		t.Fatalf("Failed to add router: %v", err)
+	}
+
+	// 与已注册的路由冲突或格式错误的路径
+	for _, path := range []string{"/users/{name}", "/users/{id"} {
+		if err := s.AddRouterWithoutInstrument(textRoute(path, "")); err == nil {
+			t.Errorf("Expected error for %s", path)
+		}
+	}
+
+	rec := httptest.NewRecorder()
+	s.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/users/1", nil))
+	if rec.Body.String() != "user" {
+		t.Errorf("Expected user, got %q", rec.Body.String())
+	}
+}
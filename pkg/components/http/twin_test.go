@@ -0,0 +1,53 @@
+package http
+
+import (
+	"testing"
+
+	"github.com/stones-hub/taurus-pro-core/pkg/components/internal/twintest"
+)
+
+// templateRenames 模板（生成项目的 taurus 包）与本包标识符的对应关系
+var templateRenames = map[string]string{
+	"HttpServeOptions":          "ServeOptions",
+	"HttpServer":                "Server",
+	"NewHttpServer":             "NewServer",
+	"DefaultHttpServer":         "DefaultServer",
+	"HttpServers":               "Servers",
+	"HttpMiddlewareFactory":     "MiddlewareFactory",
+	"httpMiddlewareOverride":    "middlewareOverride",
+	"HttpMiddlewareRegistry":    "MiddlewareRegistry",
+	"NewHttpMiddlewareRegistry": "NewMiddlewareRegistry",
+	"httpMiddlewareNames":       "middlewareNames",
+	"httpRecoveryMiddleware":    "recoveryMiddleware",
+	"httpRequestIDMiddleware":   "requestIDMiddleware",
+	"httpBodyLimitMiddleware":   "bodyLimitMiddleware",
+	"httpBodyLimitBytes":        "bodyLimitBytes",
+	"httpTimeoutMiddleware":     "timeoutMiddleware",
+	"httpAccessLogMiddleware":   "accessLogMiddleware",
+	"httpCorsMiddleware":        "corsMiddleware",
+	"httpCompressor":            "compressor",
+	"httpCompressionMiddleware": "compressionMiddleware",
+	"httpAcceptEncoding":        "acceptEncoding",
+	"httpCompressible":          "compressibleType",
+	"httpIdempotencyMiddleware": "idempotencyMiddleware",
+	"httpIdempotencyDigest":     "idempotencyDigest",
+	"Instrumentation":           "instrument.Registry",
+	"LoggerAccessor":            "common.LoggerAccessor",
+	"ConfigAccessor":            "coreconfig.Accessor",
+}
+
+func TestMatchesTemplate(t *testing.T) {
+	files := map[string]string{
+		"server.go":      "http.gotmpl",
+		"middleware.go":  "middleware.gotmpl",
+		"access_log.go":  "access_log.gotmpl",
+		"cors.go":        "cors.gotmpl",
+		"compression.go": "compression.gotmpl",
+		"idempotency.go": "idempotency.gotmpl",
+	}
+	for twin, template := range files {
+		t.Run(twin, func(t *testing.T) {
+			twintest.Compare(t, twin, twintest.Template(template), templateRenames)
+		})
+	}
+}
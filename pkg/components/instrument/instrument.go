@@ -0,0 +1,123 @@
+// Package instrument 是生成项目中 internal/taurus/instrument.go 的对应实现，供组件的 Go 版本 Provider 使用
+package instrument
+
+import (
+	"context"
+	"net/http"
+	"slices"
+	"sync"
+)
+
+// 可埋点资源的类型，组件通过 Publish 发布，metrics、otel 等组件通过 Subscribe 订阅
+const (
+	KindGorm            = "gorm"              // *gorm.DB，名称为数据库名
+	KindRedis           = "redis"             // redis 客户端，支持 AddHook(redis.Hook)
+	KindGrpcUnary       = "grpc.unary"        // grpc.UnaryServerInterceptor
+	KindGrpcStream      = "grpc.stream"       // grpc.StreamServerInterceptor
+	KindGrpcStats       = "grpc.stats"        // stats.Handler，如 otelgrpc.NewServerHandler()
+	KindGrpcClientStats = "grpc.client.stats" // 客户端的 stats.Handler，创建连接时通过 grpc.WithStatsHandler 使用
+)
+
+// HttpInstrument http 埋点中间件，route 为注册时的路由路径，如 /admin/user/login
+type HttpInstrument func(route string, next http.Handler) http.Handler
+
+// TaskFunc 定时任务的执行函数
+type TaskFunc func(ctx context.Context) error
+
+// TaskInstrument 定时任务埋点中间件，name 为任务名称
+type TaskInstrument func(name string, next TaskFunc) TaskFunc
+
+// resourceEntry 发布的可埋点资源
+type resourceEntry struct {
+	kind     string
+	name     string
+	resource any
+}
+
+// Registry 可观测性埋点注册表
+// 数据库、redis、grpc 等组件在 Provider 中发布自己的资源，metrics 等可选组件订阅后为其加上埋点，
+// 组件之间不需要直接依赖，未选择 metrics 组件时不会产生任何额外开销
+type Registry struct {
+	mu          sync.RWMutex
+	resources   []*resourceEntry
+	subscribers map[string][]func(name string, resource any)
+	https       []HttpInstrument
+	tasks       []TaskInstrument
+}
+
+// NewRegistry 创建埋点注册表
+func NewRegistry() *Registry {
+	return &Registry{subscribers: make(map[string][]func(name string, resource any))}
+}
+
+// Publish 发布可埋点的资源，已经订阅该类型的回调会立即执行
+func (i *Registry) Publish(kind, name string, resource any) {
+	i.mu.Lock()
+	i.resources = append(i.resources, &resourceEntry{kind: kind, name: name, resource: resource})
+	subscribers := slices.Clone(i.subscribers[kind])
+	i.mu.Unlock()
+
+	for _, fn := range subscribers {
+		fn(name, resource)
+	}
+}
+
+// Subscribe 订阅指定类型的资源，之前已经发布的资源会立即回调，之后发布的资源在发布时回调
+// wire 构建组件的顺序不确定，订阅方与发布方谁先初始化都不影响结果
+func (i *Registry) Subscribe(kind string, fn func(name string, resource any)) {
+	i.mu.Lock()
+	i.subscribers[kind] = append(i.subscribers[kind], fn)
+	resources := make([]*resourceEntry, 0)
+	for _, r := range i.resources {
+		if r.kind == kind {
+			resources = append(resources, r)
+		}
+	}
+	i.mu.Unlock()
+
+	for _, r := range resources {
+		fn(r.name, r.resource)
+	}
+}
+
+// UseHttp 注册 http 埋点中间件，http.Server 注册路由时自动加到路由中间件的最外层
+func (i *Registry) UseHttp(instrument HttpInstrument) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	i.https = append(i.https, instrument)
+}
+
+// HttpMiddleware 返回指定路由的 http 埋点中间件，没有注册任何埋点时返回 nil
+func (i *Registry) HttpMiddleware(route string) func(http.Handler) http.Handler {
+	i.mu.RLock()
+	instruments := append([]HttpInstrument(nil), i.https...)
+	i.mu.RUnlock()
+
+	if len(instruments) == 0 {
+		return nil
+	}
+	return func(next http.Handler) http.Handler {
+		// 先注册的埋点在最外层
+		for j := len(instruments) - 1; j >= 0; j-- {
+			next = instruments[j](route, next)
+		}
+		return next
+	}
+}
+
+// UseTask 注册定时任务埋点中间件
+func (i *Registry) UseTask(instrument TaskInstrument) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	i.tasks = append(i.tasks, instrument)
+}
+
+// WrapTask 为定时任务的执行函数加上已注册的埋点
+func (i *Registry) WrapTask(name string, fn TaskFunc) TaskFunc {
+	i.mu.RLock()
+	defer i.mu.RUnlock()
+	for j := len(i.tasks) - 1; j >= 0; j-- {
+		fn = i.tasks[j](name, fn)
+	}
+	return fn
+}
@@ -0,0 +1,60 @@
+package instrument
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestRegistry(t *testing.T) {
+	r := NewRegistry()
+
+	// 订阅前后发布的资源都会回调
+	var got []string
+	r.Publish(KindGorm, "default", "db1")
+	r.Subscribe(KindGorm, func(name string, resource any) { got = append(got, name+"="+resource.(string)) })
+	r.Publish(KindGorm, "report", "db2")
+	r.Publish(KindRedis, "default", "redis")
+	if strings.Join(got, ",") != "default=db1,report=db2" {
+		t.Fatalf("unexpected resources: %v", got)
+	}
+
+	if r.HttpMiddleware("/users") != nil {
+		t.Fatal("expected nil middleware without instruments")
+	}
+
+	var events []string
+	tag := func(tag string) HttpInstrument {
+		return func(route string, next http.Handler) http.Handler {
+			return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+				events = append(events, tag+" "+route)
+				next.ServeHTTP(w, req)
+			})
+		}
+	}
+	r.UseHttp(tag("metrics"))
+	r.UseHttp(tag("trace"))
+	handler := r.HttpMiddleware("/users")(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {}))
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/users", nil))
+	if strings.Join(events, ",") != "metrics /users,trace /users" {
+		t.Fatalf("unexpected order: %v", events)
+	}
+
+	failures := 0
+	r.UseTask(func(name string, next TaskFunc) TaskFunc {
+		return func(ctx context.Context) error {
+			err := next(ctx)
+			if err != nil {
+				failures++
+			}
+			return err
+		}
+	})
+	task := r.WrapTask("sync", func(ctx context.Context) error { return errors.New("timeout") })
+	if err := task(context.Background()); err == nil || failures != 1 {
+		t.Fatalf("unexpected task result: err=%v failures=%d", err, failures)
+	}
+}
@@ -0,0 +1,21 @@
+package instrument
+
+import (
+	"testing"
+
+	"github.com/stones-hub/taurus-pro-core/pkg/components/internal/twintest"
+)
+
+func TestMatchesTemplate(t *testing.T) {
+	twintest.Compare(t, "instrument.go", twintest.Template("instrument.gotmpl"), map[string]string{
+		"instrumentResource":            "resourceEntry",
+		"Instrumentation":               "Registry",
+		"NewInstrumentation":            "NewRegistry",
+		"InstrumentKindGorm":            "KindGorm",
+		"InstrumentKindGrpcClientStats": "KindGrpcClientStats",
+		"InstrumentKindGrpcStats":       "KindGrpcStats",
+		"InstrumentKindGrpcStream":      "KindGrpcStream",
+		"InstrumentKindGrpcUnary":       "KindGrpcUnary",
+		"InstrumentKindRedis":           "KindRedis",
+	})
+}
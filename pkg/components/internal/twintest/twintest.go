@@ -0,0 +1,137 @@
+// Package twintest 检查 pkg/components 下的 Go 实现与 templates/internal/taurus 中对应模板是否一致
+// 两边的包名、导入与注释可以不同，但每个顶层声明在按 renames 改名后必须逐字一致
+package twintest
+
+import (
+	"bytes"
+	"go/ast"
+	"go/parser"
+	"go/printer"
+	"go/token"
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+)
+
+// Compare 比较 Go 实现 twin 与模板 template 的顶层声明
+// renames 把模板中的标识符改为 Go 实现中的写法，如 HttpServer -> Server、ConfigAccessor -> coreconfig.Accessor
+func Compare(t *testing.T, twin, template string, renames map[string]string) {
+	t.Helper()
+
+	twinDecls, err := decls(twin, nil)
+	if err != nil {
+		t.Fatalf("Failed to parse %s: %v", twin, err)
+	}
+	templateDecls, err := decls(template, renames)
+	if err != nil {
+		t.Fatalf("Failed to parse %s: %v", template, err)
+	}
+
+	for _, name := range sortedKeys(twinDecls) {
+		want, ok := templateDecls[name]
+		if !ok {
+			t.Errorf("%s: %s is not in %s", twin, name, template)
+			continue
+		}
+		if got := twinDecls[name]; got != want {
+			t.Errorf("%s: %s differs from %s\n--- twin\n%s\n--- template\n%s", twin, name, template, got, want)
+		}
+	}
+	for _, name := range sortedKeys(templateDecls) {
+		if _, ok := twinDecls[name]; !ok {
+			t.Errorf("%s: %s is not in %s", template, name, twin)
+		}
+	}
+}
+
+// decls 解析文件并按名称返回去掉注释后的顶层声明源码，方法的名称为 接收者.方法名
+func decls(path string, renames map[string]string) (map[string]string, error) {
+	src, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	src = bytes.ReplaceAll(src, []byte("{{.ProjectName}}"), []byte("project"))
+
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, path, src, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	// 先改名再打印，字段对齐按改名后的标识符计算
+	ast.Inspect(file, func(n ast.Node) bool {
+		if ident, ok := n.(*ast.Ident); ok {
+			if to, ok := renames[ident.Name]; ok {
+				ident.Name = to
+			}
+		}
+		return true
+	})
+
+	result := make(map[string]string)
+	add := func(name string, node any) error {
+		var buf bytes.Buffer
+		if err := printer.Fprint(&buf, fset, node); err != nil {
+			return err
+		}
+		result[name] = buf.String()
+		return nil
+	}
+	for _, decl := range file.Decls {
+		switch d := decl.(type) {
+		case *ast.FuncDecl:
+			name := d.Name.Name
+			if d.Recv != nil && len(d.Recv.List) > 0 {
+				name = receiverName(d.Recv.List[0].Type) + "." + name
+			}
+			if err := add(name, d); err != nil {
+				return nil, err
+			}
+		case *ast.GenDecl:
+			if d.Tok == token.IMPORT {
+				continue
+			}
+			for _, spec := range d.Specs {
+				var name string
+				switch s := spec.(type) {
+				case *ast.TypeSpec:
+					name = s.Name.Name
+				case *ast.ValueSpec:
+					name = s.Names[0].Name
+				}
+				if err := add(name, spec); err != nil {
+					return nil, err
+				}
+			}
+		}
+	}
+	return result, nil
+}
+
+// receiverName 返回方法接收者的类型名
+func receiverName(expr ast.Expr) string {
+	switch e := expr.(type) {
+	case *ast.StarExpr:
+		return receiverName(e.X)
+	case *ast.IndexExpr:
+		return receiverName(e.X)
+	case *ast.Ident:
+		return e.Name
+	}
+	return ""
+}
+
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// Template 返回相对于 pkg/components/<包> 目录的模板路径
+func Template(name string) string {
+	return filepath.Join("..", "..", "..", "templates", "internal", "taurus", name)
+}
@@ -0,0 +1,375 @@
+// Package lifecycle 是生成项目中 internal/taurus/lifecycle.go 的对应实现，供组件的 Go 版本 Provider 使用
+package lifecycle
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Phase 关闭阶段，Shutdown 按以下顺序依次执行，每个阶段有独立的时间预算
+type Phase int
+
+const (
+	PhaseDeregister Phase = iota // 从注册中心注销、上报不可用，不再接收新的流量
+	PhaseServer                  // 排空 http、grpc、tcp 等服务中正在处理的请求
+	PhaseWorker                  // 停止定时任务、队列与 hook
+	PhaseResource                // 关闭数据库、redis 等资源，导出剩余的 otel 数据
+)
+
+// phases 关闭阶段的执行顺序
+var phases = []Phase{PhaseDeregister, PhaseServer, PhaseWorker, PhaseResource}
+
+func (p Phase) String() string {
+	switch p {
+	case PhaseDeregister:
+		return "deregister"
+	case PhaseServer:
+		return "server"
+	case PhaseWorker:
+		return "worker"
+	case PhaseResource:
+		return "resource"
+	}
+	return fmt.Sprintf("phase(%d)", int(p))
+}
+
+// Lifecycle 服务组件的生命周期契约
+// Start 启动组件，阻塞运行的服务（如 ListenAndServe）需要通过 Manager.Go 在后台运行
+// Stop 停止组件，需要在 ctx 结束前返回
+type Lifecycle interface {
+	Start(ctx context.Context) error
+	Stop(ctx context.Context) error
+}
+
+// Hook 以函数的方式实现 Lifecycle，未设置的函数视为空操作
+type Hook struct {
+	OnStart func(ctx context.Context) error
+	OnStop  func(ctx context.Context) error
+}
+
+func (h *Hook) Start(ctx context.Context) error {
+	if h.OnStart == nil {
+		return nil
+	}
+	return h.OnStart(ctx)
+}
+
+func (h *Hook) Stop(ctx context.Context) error {
+	if h.OnStop == nil {
+		return nil
+	}
+	return h.OnStop(ctx)
+}
+
+// lifecycleEntry 注册到管理器的组件
+type lifecycleEntry struct {
+	name      string
+	component Lifecycle
+	dependsOn []string
+	phase     Phase
+}
+
+// Manager 统一管理服务组件（http、grpc、tcp、mcp、debug 等）的启动与停止
+// 组件按依赖顺序启动，关闭时按阶段停止，运行期间的错误统一发送到 Errors() 返回的通道
+type Manager struct {
+	mu       sync.Mutex
+	entries  []*lifecycleEntry
+	started  []*lifecycleEntry
+	cleanups []*lifecycleEntry
+	timeouts map[Phase]time.Duration
+	errChan  chan error
+	done     chan struct{}
+	stopOnce sync.Once
+}
+
+// NewManager 创建生命周期管理器
+func NewManager() *Manager {
+	return &Manager{
+		timeouts: make(map[Phase]time.Duration),
+		errChan:  make(chan error, 1),
+		done:     make(chan struct{}),
+	}
+}
+
+// SetPhaseTimeout 设置关闭阶段的时间预算(秒)，未设置或不大于 0 时为 10 秒
+func (m *Manager) SetPhaseTimeout(phase Phase, seconds int) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.timeouts[phase] = time.Duration(seconds) * time.Second
+}
+
+// Register 注册服务组件，在 PhaseServer 阶段停止
+// dependsOn 中的组件会先于当前组件启动、晚于当前组件停止，组件都是可选的，dependsOn 中未注册的组件会被忽略
+func (m *Manager) Register(name string, component Lifecycle, dependsOn ...string) {
+	m.RegisterPhase(PhaseServer, name, component, dependsOn...)
+}
+
+// RegisterPhase 注册组件并指定关闭阶段，如 consul TTL 上报在 PhaseDeregister 阶段停止
+// 不同阶段的组件按阶段顺序停止，dependsOn 只影响启动顺序与同一阶段内的停止顺序
+func (m *Manager) RegisterPhase(phase Phase, name string, component Lifecycle, dependsOn ...string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.entries = append(m.entries, &lifecycleEntry{name: name, component: component, dependsOn: dependsOn, phase: phase})
+}
+
+// OnShutdown 注册只在关闭时执行的清理函数，如关闭数据库连接、停止定时任务
+// 与 Register 不同，清理函数不需要启动，脚本模式下 Shutdown 同样会执行
+func (m *Manager) OnShutdown(phase Phase, name string, fn func(ctx context.Context) error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.cleanups = append(m.cleanups, &lifecycleEntry{name: name, component: &Hook{OnStop: fn}, phase: phase})
+}
+
+// Errors 返回统一的错误通道，任意组件在运行期间出错都会发送到该通道
+func (m *Manager) Errors() <-chan error {
+	return m.errChan
+}
+
+// Go 在后台运行阻塞的服务函数，如 server.ListenAndServe，返回的错误会发送到统一的错误通道
+// 管理器停止之后返回的错误会被忽略
+func (m *Manager) Go(name string, fn func() error) {
+	go func() {
+		if err := fn(); err != nil {
+			m.report(name, err)
+		}
+	}()
+}
+
+// Watch 将组件自身的错误通道转发到统一的错误通道，如 http.Server.Start(errChan)
+func (m *Manager) Watch(name string, errChan <-chan error) {
+	go func() {
+		select {
+		case err := <-errChan:
+			if err != nil {
+				m.report(name, err)
+			}
+		case <-m.done:
+		}
+	}()
+}
+
+// report 发送组件错误，只保留第一个错误，后续错误只记录日志
+func (m *Manager) report(name string, err error) {
+	select {
+	case <-m.done:
+		return
+	default:
+	}
+
+	err = fmt.Errorf("%s: %v", name, err)
+	select {
+	case m.errChan <- err:
+	default:
+		log.Printf("%s🔗 -> Lifecycle component error: %v %s\n", "\033[31m", err, "\033[0m")
+	}
+}
+
+// Start 按依赖顺序启动所有组件，任意组件启动失败时按相反顺序停止已经启动的组件
+func (m *Manager) Start(ctx context.Context) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	entries, err := m.sorted()
+	if err != nil {
+		return err
+	}
+
+	for _, entry := range entries {
+		if err := entry.component.Start(ctx); err != nil {
+			m.stopStarted(ctx)
+			return fmt.Errorf("启动 %s 失败: %v", entry.name, err)
+		}
+		m.started = append(m.started, entry)
+		log.Printf("%s🔗 -> Lifecycle component %s started. %s\n", "\033[32m", entry.name, "\033[0m")
+	}
+	return nil
+}
+
+// Stop 按启动的相反顺序依次停止所有已启动的组件，不区分阶段，返回所有停止失败的错误
+// 服务关闭时使用 Shutdown
+func (m *Manager) Stop(ctx context.Context) error {
+	m.stopOnce.Do(func() { close(m.done) })
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.stopStarted(ctx)
+}
+
+// stopStarted 按相反顺序停止已启动的组件，调用方需要持有锁
+func (m *Manager) stopStarted(ctx context.Context) error {
+	var errs []error
+	for i := len(m.started) - 1; i >= 0; i-- {
+		entry := m.started[i]
+		if err := entry.component.Stop(ctx); err != nil {
+			errs = append(errs, fmt.Errorf("停止 %s 失败: %v", entry.name, err))
+			log.Printf("%s🔗 -> Lifecycle component %s stop failed: %v %s\n", "\033[31m", entry.name, err, "\033[0m")
+			continue
+		}
+		log.Printf("%s🔗 -> Lifecycle component %s stopped. %s\n", "\033[32m", entry.name, "\033[0m")
+	}
+	m.started = nil
+	return errors.Join(errs...)
+}
+
+// Shutdown 按阶段依次停止已启动的组件并执行 OnShutdown 注册的清理函数，返回所有失败与超时的错误
+// 同一阶段内的组件并发停止（依赖它的组件先停止），阶段超时后记录未完成的组件并进入下一阶段，
+// 一个慢组件不会占用其他阶段的时间
+func (m *Manager) Shutdown() error {
+	m.stopOnce.Do(func() { close(m.done) })
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	stopping := append(slices.Clone(m.started), m.cleanups...)
+	m.started, m.cleanups = nil, nil
+
+	var errs []error
+	for _, phase := range phases {
+		entries := make([]*lifecycleEntry, 0)
+		for _, entry := range stopping {
+			if entry.phase == phase {
+				entries = append(entries, entry)
+			}
+		}
+		if len(entries) == 0 {
+			continue
+		}
+		if err := m.stopPhase(phase, entries); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// stopPhase 在阶段的时间预算内并发停止组件，超时时返回仍未完成的组件，调用方需要持有锁
+func (m *Manager) stopPhase(phase Phase, entries []*lifecycleEntry) error {
+	timeout := m.timeouts[phase]
+	if timeout <= 0 {
+		timeout = 10 * time.Second
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	log.Printf("%s🔗 -> Shutdown phase %s started, timeout %s %s\n", "\033[33m", phase, timeout, "\033[0m")
+
+	var (
+		mu      sync.Mutex
+		errs    []error
+		wg      sync.WaitGroup
+		pending = make(map[int]bool, len(entries)) // 按下标记录，同名的组件（如 OnShutdown 与 Register 使用相同的名称）不会合并
+		done    = make([]chan struct{}, len(entries))
+	)
+	for i := range entries {
+		pending[i] = true
+		done[i] = make(chan struct{})
+	}
+
+	for i, entry := range entries {
+		wg.Add(1)
+		go func(i int, entry *lifecycleEntry) {
+			defer wg.Done()
+			defer close(done[i])
+
+			// 同一阶段内依赖当前组件的组件先停止，如 mcp 先于 http
+			for j, other := range entries {
+				if slices.Contains(other.dependsOn, entry.name) {
+					select {
+					case <-done[j]:
+					case <-ctx.Done():
+					}
+				}
+			}
+
+			err := entry.component.Stop(ctx)
+			mu.Lock()
+			defer mu.Unlock()
+			// 超过时间预算才返回（如因 ctx 取消而中断）的组件同样视为超时
+			if ctx.Err() != nil {
+				return
+			}
+			delete(pending, i)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("停止 %s 失败: %v", entry.name, err))
+				log.Printf("%s🔗 -> Lifecycle component %s stop failed: %v %s\n", "\033[31m", entry.name, err, "\033[0m")
+				return
+			}
+			log.Printf("%s🔗 -> Lifecycle component %s stopped. %s\n", "\033[32m", entry.name, "\033[0m")
+		}(i, entry)
+	}
+
+	finished := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(finished)
+	}()
+
+	select {
+	case <-finished:
+	case <-ctx.Done():
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(pending) > 0 {
+		names := make([]string, 0, len(pending))
+		for i := range pending {
+			names = append(names, entries[i].name)
+		}
+		sort.Strings(names)
+		log.Printf("%s🔗 -> Shutdown phase %s timed out after %s, still running: %s %s\n", "\033[31m", phase, timeout, strings.Join(names, ", "), "\033[0m")
+		errs = append(errs, fmt.Errorf("关闭阶段 %s 超时，未完成: %s", phase, strings.Join(names, ", ")))
+	}
+	return errors.Join(errs...)
+}
+
+// sorted 按依赖关系对组件进行拓扑排序，没有依赖关系的组件保持注册顺序
+func (m *Manager) sorted() ([]*lifecycleEntry, error) {
+	index := make(map[string]*lifecycleEntry, len(m.entries))
+	for _, entry := range m.entries {
+		if _, ok := index[entry.name]; ok {
+			return nil, fmt.Errorf("组件 %s 重复注册", entry.name)
+		}
+		index[entry.name] = entry
+	}
+
+	const (
+		visiting = 1
+		visited  = 2
+	)
+	state := make(map[string]int, len(m.entries))
+	result := make([]*lifecycleEntry, 0, len(m.entries))
+
+	var visit func(entry *lifecycleEntry, path []string) error
+	visit = func(entry *lifecycleEntry, path []string) error {
+		switch state[entry.name] {
+		case visited:
+			return nil
+		case visiting:
+			return fmt.Errorf("组件存在循环依赖: %s", strings.Join(append(path, entry.name), " -> "))
+		}
+		state[entry.name] = visiting
+		for _, dep := range entry.dependsOn {
+			if next, ok := index[dep]; ok {
+				if err := visit(next, append(path, entry.name)); err != nil {
+					return err
+				}
+			}
+		}
+		state[entry.name] = visited
+		result = append(result, entry)
+		return nil
+	}
+
+	for _, entry := range m.entries {
+		if err := visit(entry, nil); err != nil {
+			return nil, err
+		}
+	}
+	return result, nil
+}
@@ -0,0 +1,116 @@
+package lifecycle
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestManager(t *testing.T) {
+	var events []string
+	hook := func(name string) *Hook {
+		return &Hook{
+			OnStart: func(ctx context.Context) error { events = append(events, "start "+name); return nil },
+			OnStop:  func(ctx context.Context) error { events = append(events, "stop "+name); return nil },
+		}
+	}
+
+	m := NewManager()
+	m.Register("mcp", hook("mcp"), "http")
+	m.Register("http", hook("http"), "tracer")
+	m.Register("tcp", hook("tcp"))
+
+	ctx := context.Background()
+	if err := m.Start(ctx); err != nil {
+		t.Fatal(err)
+	}
+	m.Go("tcp", func() error { return errors.New("listen failed") })
+	select {
+	case err := <-m.Errors():
+		if err.Error() != "tcp: listen failed" {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("error not reported")
+	}
+	if err := m.Stop(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	want := "start http,start mcp,start tcp,stop tcp,stop mcp,stop http"
+	if got := strings.Join(events, ","); got != want {
+		t.Fatalf("unexpected order:\n got: %s\nwant: %s", got, want)
+	}
+}
+
+func TestManagerStartFailure(t *testing.T) {
+	stopped := false
+	m := NewManager()
+	m.Register("http", &Hook{OnStop: func(ctx context.Context) error { stopped = true; return nil }})
+	m.Register("grpc", &Hook{OnStart: func(ctx context.Context) error { return errors.New("boom") }}, "http")
+
+	if err := m.Start(context.Background()); err == nil || !stopped {
+		t.Fatalf("expected started components to be stopped, err=%v stopped=%v", err, stopped)
+	}
+
+	m = NewManager()
+	m.Register("a", &Hook{}, "b")
+	m.Register("b", &Hook{}, "a")
+	if err := m.Start(context.Background()); err == nil || !strings.Contains(err.Error(), "a -> b -> a") {
+		t.Fatalf("expected cycle error, got %v", err)
+	}
+}
+
+func TestManagerShutdown(t *testing.T) {
+	var (
+		mu     sync.Mutex
+		events []string
+	)
+	record := func(event string) {
+		mu.Lock()
+		defer mu.Unlock()
+		events = append(events, event)
+	}
+	hook := func(name string) *Hook {
+		return &Hook{OnStop: func(ctx context.Context) error { record("stop " + name); return nil }}
+	}
+
+	m := NewManager()
+	m.SetPhaseTimeout(PhaseWorker, 1)
+	m.Register("http", hook("http"))
+	m.Register("mcp", hook("mcp"), "http")
+	m.RegisterPhase(PhaseDeregister, "consul-ttl", hook("consul-ttl"), "http")
+	m.OnShutdown(PhaseResource, "db", func(ctx context.Context) error { record("close db"); return nil })
+	// 超时的组件不影响同一阶段的其他组件与后续阶段
+	m.OnShutdown(PhaseWorker, "queue", func(ctx context.Context) error { <-ctx.Done(); return nil })
+	m.OnShutdown(PhaseWorker, "cron", func(ctx context.Context) error { record("stop cron"); return nil })
+
+	if err := m.Start(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	err := m.Shutdown()
+	if err == nil || !strings.Contains(err.Error(), "关闭阶段 worker 超时，未完成: queue") {
+		t.Fatalf("expected worker timeout error, got %v", err)
+	}
+
+	want := "stop consul-ttl,stop mcp,stop http,stop cron,close db"
+	if got := strings.Join(events, ","); got != want {
+		t.Fatalf("unexpected order:\n got: %s\nwant: %s", got, want)
+	}
+}
+
+func TestManagerShutdownDuplicateNames(t *testing.T) {
+	m := NewManager()
+	m.SetPhaseTimeout(PhaseWorker, 1)
+	// 同名的清理函数分别记录，先完成的不会把超时的一个标记为完成
+	m.OnShutdown(PhaseWorker, "queue", func(ctx context.Context) error { return nil })
+	m.OnShutdown(PhaseWorker, "queue", func(ctx context.Context) error { <-ctx.Done(); return nil })
+
+	err := m.Shutdown()
+	if err == nil || !strings.Contains(err.Error(), "关闭阶段 worker 超时，未完成: queue") {
+		t.Fatalf("expected worker timeout error, got %v", err)
+	}
+}
@@ -0,0 +1,21 @@
+package lifecycle
+
+import (
+	"testing"
+
+	"github.com/stones-hub/taurus-pro-core/pkg/components/internal/twintest"
+)
+
+func TestMatchesTemplate(t *testing.T) {
+	twintest.Compare(t, "lifecycle.go", twintest.Template("lifecycle.gotmpl"), map[string]string{
+		"LifecyclePhase":           "Phase",
+		"lifecyclePhases":          "phases",
+		"LifecycleHook":            "Hook",
+		"LifecycleManager":         "Manager",
+		"NewLifecycleManager":      "NewManager",
+		"LifecyclePhaseDeregister": "PhaseDeregister",
+		"LifecyclePhaseServer":     "PhaseServer",
+		"LifecyclePhaseWorker":     "PhaseWorker",
+		"LifecyclePhaseResource":   "PhaseResource",
+	})
+}
@@ -0,0 +1,647 @@
+package metrics
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"github.com/redis/go-redis/v9"
+	"github.com/stones-hub/taurus-pro-config/pkg/config"
+	thttp "github.com/stones-hub/taurus-pro-core/pkg/components/http"
+	"github.com/stones-hub/taurus-pro-core/pkg/components/instrument"
+	"github.com/stones-hub/taurus-pro-core/pkg/components/types"
+	"github.com/stones-hub/taurus-pro-http/pkg/router"
+	"google.golang.org/grpc"
+	grpcstatus "google.golang.org/grpc/status"
+	"gorm.io/gorm"
+)
+
+var MetricsComponent = types.Component{
+	Name:         "metrics",
+	Package:      "github.com/prometheus/client_golang",
+	Version:      "v1.23.2",
+	Description:  "prometheus指标组件，暴露/metrics并自动统计http、grpc、数据库、redis、定时任务与go运行时指标",
+	IsCustom:     true,
+	Required:     false,
+	Dependencies: []string{"config"},
+	Wire:         []*types.Wire{metricsWire},
+}
+
+var metricsWire = &types.Wire{
+	RequirePath: []string{
+		"bufio", "context", "errors", "fmt", "log", "net", "net/http", "strconv", "time",
+		"github.com/prometheus/client_golang/prometheus",
+		"github.com/prometheus/client_golang/prometheus/collectors",
+		"github.com/prometheus/client_golang/prometheus/promhttp",
+		"github.com/redis/go-redis/v9",
+		"github.com/stones-hub/taurus-pro-http/pkg/router",
+		"google.golang.org/grpc",
+		"grpcstatus@google.golang.org/grpc/status",
+		"gorm.io/gorm",
+	},
+	Name:         "Metrics",
+	Type:         "*prometheus.Registry",
+	ProviderName: "ProvideMetricsComponent",
+	Provider: `func {{.ProviderName}}(cfg *config.Config, httpServers HttpServers, inst *Instrumentation) ({{.Type}}, error) {
+	if !cfg.GetBool("metrics.enable") {
+		return nil, nil
+	}
+
+	registry := prometheus.NewRegistry()
+	labels := prometheus.Labels{}
+	if raw, ok := cfg.Get("metrics.const_labels").(map[string]interface{}); ok {
+		for name, value := range raw {
+			labels[name] = fmt.Sprint(value)
+		}
+	}
+	registerer := prometheus.WrapRegistererWith(labels, registry)
+	namespace := cfg.GetString("metrics.namespace")
+	buckets := metricsBuckets(cfg.Get("metrics.buckets"))
+
+	// go 运行时与进程指标
+	if cfg.GetBool("metrics.runtime.enable") {
+		registerer.MustRegister(
+			collectors.NewGoCollector(),
+			collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		)
+	}
+
+	// http 路由的请求数、状态码与耗时，route 为注册时的路由路径，避免路径参数导致标签过多
+	if cfg.GetBool("metrics.http.enable") {
+		requests := prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace, Subsystem: "http", Name: "requests_total", Help: "http 请求数",
+		}, []string{"method", "route", "status"})
+		duration := prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace, Subsystem: "http", Name: "request_duration_seconds", Help: "http 请求耗时", Buckets: buckets,
+		}, []string{"method", "route"})
+		registerer.MustRegister(requests, duration)
+
+		inst.UseHttp(func(route string, next http.Handler) http.Handler {
+			return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				start := time.Now()
+				rw := &metricsResponseWriter{ResponseWriter: w, status: http.StatusOK}
+				defer func() {
+					code := rw.status
+					err := recover()
+					if err != nil {
+						code = http.StatusInternalServerError
+					}
+					requests.WithLabelValues(r.Method, route, strconv.Itoa(code)).Inc()
+					duration.WithLabelValues(r.Method, route).Observe(time.Since(start).Seconds())
+					if err != nil {
+						panic(err)
+					}
+				}()
+				next.ServeHTTP(rw, r)
+			})
+		})
+	}
+
+	// grpc 方法的调用数、状态码与耗时
+	if cfg.GetBool("metrics.grpc.enable") {
+		handled := prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace, Subsystem: "grpc", Name: "server_handled_total", Help: "grpc 方法调用数",
+		}, []string{"method", "code"})
+		duration := prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace, Subsystem: "grpc", Name: "server_handling_seconds", Help: "grpc 方法耗时", Buckets: buckets,
+		}, []string{"method"})
+		registerer.MustRegister(handled, duration)
+
+		observe := func(method string, start time.Time, err error) {
+			handled.WithLabelValues(method, grpcstatus.Code(err).String()).Inc()
+			duration.WithLabelValues(method).Observe(time.Since(start).Seconds())
+		}
+		inst.Publish(InstrumentKindGrpcUnary, "metrics", grpc.UnaryServerInterceptor(func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+			start := time.Now()
+			resp, err := handler(ctx, req)
+			observe(info.FullMethod, start, err)
+			return resp, err
+		}))
+		inst.Publish(InstrumentKindGrpcStream, "metrics", grpc.StreamServerInterceptor(func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+			start := time.Now()
+			err := handler(srv, ss)
+			observe(info.FullMethod, start, err)
+			return err
+		}))
+	}
+
+	// gorm 按数据库名与操作类型统计的查询数、失败数与耗时
+	if cfg.GetBool("metrics.db.enable") {
+		queries := prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace, Subsystem: "db", Name: "queries_total", Help: "数据库操作数",
+		}, []string{"db", "operation", "result"})
+		duration := prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace, Subsystem: "db", Name: "query_duration_seconds", Help: "数据库操作耗时", Buckets: buckets,
+		}, []string{"db", "operation"})
+		registerer.MustRegister(queries, duration)
+
+		inst.Subscribe(InstrumentKindGorm, func(name string, resource any) {
+			gormDB, ok := resource.(*gorm.DB)
+			if !ok {
+				return
+			}
+			if err := metricsGormCallbacks(gormDB, name, queries, duration); err != nil {
+				log.Printf("%s🔗 -> Metrics instrument db %s failed: %v %s\n", "\033[31m", name, err, "\033[0m")
+			}
+		})
+	}
+
+	// redis 命令的调用数、失败数与耗时
+	if cfg.GetBool("metrics.redis.enable") {
+		hook := &metricsRedisHook{
+			commands: prometheus.NewCounterVec(prometheus.CounterOpts{
+				Namespace: namespace, Subsystem: "redis", Name: "commands_total", Help: "redis 命令调用数",
+			}, []string{"command", "result"}),
+			duration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+				Namespace: namespace, Subsystem: "redis", Name: "command_duration_seconds", Help: "redis 命令耗时", Buckets: buckets,
+			}, []string{"command"}),
+		}
+		registerer.MustRegister(hook.commands, hook.duration)
+
+		inst.Subscribe(InstrumentKindRedis, func(name string, resource any) {
+			if client, ok := resource.(interface{ AddHook(redis.Hook) }); ok {
+				client.AddHook(hook)
+			}
+		})
+	}
+
+	// 定时任务的执行次数、失败次数与耗时
+	if cfg.GetBool("metrics.cron.enable") {
+		runs := prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace, Subsystem: "cron", Name: "task_runs_total", Help: "定时任务执行次数",
+		}, []string{"task"})
+		failures := prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace, Subsystem: "cron", Name: "task_failures_total", Help: "定时任务失败次数",
+		}, []string{"task"})
+		duration := prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace, Subsystem: "cron", Name: "task_duration_seconds", Help: "定时任务耗时", Buckets: buckets,
+		}, []string{"task"})
+		registerer.MustRegister(runs, failures, duration)
+
+		inst.UseTask(func(name string, next TaskFunc) TaskFunc {
+			return func(ctx context.Context) error {
+				start := time.Now()
+				err := next(ctx)
+				runs.WithLabelValues(name).Inc()
+				if err != nil {
+					failures.WithLabelValues(name).Inc()
+				}
+				duration.WithLabelValues(name).Observe(time.Since(start).Seconds())
+				return err
+			}
+		})
+	}
+
+	path := cfg.GetString("metrics.path")
+	if path == "" {
+		path = "/metrics"
+	}
+	// 注册到 metrics.server 指定的监听，如只对内网开放的端口；不加埋点，/metrics 自身的请求不计入 http 指标
+	httpServers.Get(cfg.GetString("metrics.server")).AddRouterWithoutInstrument(router.Router{
+		Path:    path,
+		Handler: promhttp.HandlerFor(registry, promhttp.HandlerOpts{Registry: registry}),
+	})
+
+	log.Printf("%s🔗 -> Metrics all initialized successfully, exposed on %s. %s\n", "\033[32m", path, "\033[0m")
+
+	return registry, nil
+}
+
+// metricsBuckets 解析配置中的耗时直方图分桶，未配置时使用 prometheus 的默认分桶
+func metricsBuckets(raw interface{}) []float64 {
+	list, ok := raw.([]interface{})
+	if !ok || len(list) == 0 {
+		return prometheus.DefBuckets
+	}
+	buckets := make([]float64, 0, len(list))
+	for _, item := range list {
+		switch v := item.(type) {
+		case int:
+			buckets = append(buckets, float64(v))
+		case float64:
+			buckets = append(buckets, v)
+		}
+	}
+	return buckets
+}
+
+// metricsResponseWriter 记录响应状态码，保留 Flush 与 Hijack 以支持 SSE 与 websocket
+type metricsResponseWriter struct {
+	http.ResponseWriter
+	status      int
+	wroteHeader bool
+}
+
+func (w *metricsResponseWriter) WriteHeader(code int) {
+	if !w.wroteHeader {
+		w.status = code
+		w.wroteHeader = true
+	}
+	w.ResponseWriter.WriteHeader(code)
+}
+
+func (w *metricsResponseWriter) Write(b []byte) (int, error) {
+	w.wroteHeader = true
+	return w.ResponseWriter.Write(b)
+}
+
+func (w *metricsResponseWriter) Flush() {
+	if flusher, ok := w.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+func (w *metricsResponseWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	hijacker, ok := w.ResponseWriter.(http.Hijacker)
+	if !ok {
+		return nil, nil, fmt.Errorf("ResponseWriter 不支持 Hijack")
+	}
+	w.status = http.StatusSwitchingProtocols
+	return hijacker.Hijack()
+}
+
+func (w *metricsResponseWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+// metricsGormCallbacks 在 gorm 每类操作的前后注册回调，统计查询数与耗时，记录不存在不计为失败
+func metricsGormCallbacks(gormDB *gorm.DB, name string, queries *prometheus.CounterVec, duration *prometheus.HistogramVec) error {
+	const startKey = "metrics:start"
+	before := func(tx *gorm.DB) {
+		tx.InstanceSet(startKey, time.Now())
+	}
+	after := func(operation string) func(tx *gorm.DB) {
+		return func(tx *gorm.DB) {
+			start, ok := tx.InstanceGet(startKey)
+			if !ok {
+				return
+			}
+			result := "ok"
+			if tx.Error != nil && !errors.Is(tx.Error, gorm.ErrRecordNotFound) {
+				result = "error"
+			}
+			queries.WithLabelValues(name, operation, result).Inc()
+			duration.WithLabelValues(name, operation).Observe(time.Since(start.(time.Time)).Seconds())
+		}
+	}
+
+	callback := gormDB.Callback()
+	return errors.Join(
+		callback.Create().Before("gorm:create").Register("metrics:before_create", before),
+		callback.Create().After("gorm:create").Register("metrics:after_create", after("create")),
+		callback.Query().Before("gorm:query").Register("metrics:before_query", before),
+		callback.Query().After("gorm:query").Register("metrics:after_query", after("query")),
+		callback.Update().Before("gorm:update").Register("metrics:before_update", before),
+		callback.Update().After("gorm:update").Register("metrics:after_update", after("update")),
+		callback.Delete().Before("gorm:delete").Register("metrics:before_delete", before),
+		callback.Delete().After("gorm:delete").Register("metrics:after_delete", after("delete")),
+		callback.Row().Before("gorm:row").Register("metrics:before_row", before),
+		callback.Row().After("gorm:row").Register("metrics:after_row", after("row")),
+		callback.Raw().Before("gorm:raw").Register("metrics:before_raw", before),
+		callback.Raw().After("gorm:raw").Register("metrics:after_raw", after("raw")),
+	)
+}
+
+// metricsRedisHook 按命令统计 redis 调用数与耗时，redis.Nil 不计为失败
+type metricsRedisHook struct {
+	commands *prometheus.CounterVec
+	duration *prometheus.HistogramVec
+}
+
+func (h *metricsRedisHook) DialHook(next redis.DialHook) redis.DialHook {
+	return next
+}
+
+func (h *metricsRedisHook) ProcessHook(next redis.ProcessHook) redis.ProcessHook {
+	return func(ctx context.Context, cmd redis.Cmder) error {
+		start := time.Now()
+		err := next(ctx, cmd)
+		h.observe(cmd.Name(), start, err)
+		return err
+	}
+}
+
+func (h *metricsRedisHook) ProcessPipelineHook(next redis.ProcessPipelineHook) redis.ProcessPipelineHook {
+	return func(ctx context.Context, cmds []redis.Cmder) error {
+		start := time.Now()
+		err := next(ctx, cmds)
+		h.observe("pipeline", start, err)
+		return err
+	}
+}
+
+func (h *metricsRedisHook) observe(command string, start time.Time, err error) {
+	result := "ok"
+	if err != nil && !errors.Is(err, redis.Nil) {
+		result = "error"
+	}
+	h.commands.WithLabelValues(command, result).Inc()
+	h.duration.WithLabelValues(command).Observe(time.Since(start).Seconds())
+}`,
+}
+
+func ProvideMetricsComponent(cfg *config.Config, httpServers thttp.Servers, inst *instrument.Registry) (*prometheus.Registry, error) {
+	if !cfg.GetBool("metrics.enable") {
+		return nil, nil
+	}
+
+	registry := prometheus.NewRegistry()
+	labels := prometheus.Labels{}
+	if raw, ok := cfg.Get("metrics.const_labels").(map[string]interface{}); ok {
+		for name, value := range raw {
+			labels[name] = fmt.Sprint(value)
+		}
+	}
+	registerer := prometheus.WrapRegistererWith(labels, registry)
+	namespace := cfg.GetString("metrics.namespace")
+	buckets := metricsBuckets(cfg.Get("metrics.buckets"))
+
+	// go 运行时与进程指标
+	if cfg.GetBool("metrics.runtime.enable") {
+		registerer.MustRegister(
+			collectors.NewGoCollector(),
+			collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		)
+	}
+
+	// http 路由的请求数、状态码与耗时，route 为注册时的路由路径，避免路径参数导致标签过多
+	if cfg.GetBool("metrics.http.enable") {
+		requests := prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace, Subsystem: "http", Name: "requests_total", Help: "http 请求数",
+		}, []string{"method", "route", "status"})
+		duration := prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace, Subsystem: "http", Name: "request_duration_seconds", Help: "http 请求耗时", Buckets: buckets,
+		}, []string{"method", "route"})
+		registerer.MustRegister(requests, duration)
+
+		inst.UseHttp(func(route string, next http.Handler) http.Handler {
+			return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				start := time.Now()
+				rw := &metricsResponseWriter{ResponseWriter: w, status: http.StatusOK}
+				defer func() {
+					code := rw.status
+					err := recover()
+					if err != nil {
+						code = http.StatusInternalServerError
+					}
+					requests.WithLabelValues(r.Method, route, strconv.Itoa(code)).Inc()
+					duration.WithLabelValues(r.Method, route).Observe(time.Since(start).Seconds())
+					if err != nil {
+						panic(err)
+					}
+				}()
+				next.ServeHTTP(rw, r)
+			})
+		})
+	}
+
+	// grpc 方法的调用数、状态码与耗时
+	if cfg.GetBool("metrics.grpc.enable") {
+		handled := prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace, Subsystem: "grpc", Name: "server_handled_total", Help: "grpc 方法调用数",
+		}, []string{"method", "code"})
+		duration := prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace, Subsystem: "grpc", Name: "server_handling_seconds", Help: "grpc 方法耗时", Buckets: buckets,
+		}, []string{"method"})
+		registerer.MustRegister(handled, duration)
+
+		observe := func(method string, start time.Time, err error) {
+			handled.WithLabelValues(method, grpcstatus.Code(err).String()).Inc()
+			duration.WithLabelValues(method).Observe(time.Since(start).Seconds())
+		}
+		inst.Publish(instrument.KindGrpcUnary, "metrics", grpc.UnaryServerInterceptor(func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+			start := time.Now()
+			resp, err := handler(ctx, req)
+			observe(info.FullMethod, start, err)
+			return resp, err
+		}))
+		inst.Publish(instrument.KindGrpcStream, "metrics", grpc.StreamServerInterceptor(func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+			start := time.Now()
+			err := handler(srv, ss)
+			observe(info.FullMethod, start, err)
+			return err
+		}))
+	}
+
+	// gorm 按数据库名与操作类型统计的查询数、失败数与耗时
+	if cfg.GetBool("metrics.db.enable") {
+		queries := prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace, Subsystem: "db", Name: "queries_total", Help: "数据库操作数",
+		}, []string{"db", "operation", "result"})
+		duration := prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace, Subsystem: "db", Name: "query_duration_seconds", Help: "数据库操作耗时", Buckets: buckets,
+		}, []string{"db", "operation"})
+		registerer.MustRegister(queries, duration)
+
+		inst.Subscribe(instrument.KindGorm, func(name string, resource any) {
+			gormDB, ok := resource.(*gorm.DB)
+			if !ok {
+				return
+			}
+			if err := metricsGormCallbacks(gormDB, name, queries, duration); err != nil {
+				log.Printf("%s🔗 -> Metrics instrument db %s failed: %v %s\n", "\033[31m", name, err, "\033[0m")
+			}
+		})
+	}
+
+	// redis 命令的调用数、失败数与耗时
+	if cfg.GetBool("metrics.redis.enable") {
+		hook := &metricsRedisHook{
+			commands: prometheus.NewCounterVec(prometheus.CounterOpts{
+				Namespace: namespace, Subsystem: "redis", Name: "commands_total", Help: "redis 命令调用数",
+			}, []string{"command", "result"}),
+			duration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+				Namespace: namespace, Subsystem: "redis", Name: "command_duration_seconds", Help: "redis 命令耗时", Buckets: buckets,
+			}, []string{"command"}),
+		}
+		registerer.MustRegister(hook.commands, hook.duration)
+
+		inst.Subscribe(instrument.KindRedis, func(name string, resource any) {
+			if client, ok := resource.(interface{ AddHook(redis.Hook) }); ok {
+				client.AddHook(hook)
+			}
+		})
+	}
+
+	// 定时任务的执行次数、失败次数与耗时
+	if cfg.GetBool("metrics.cron.enable") {
+		runs := prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace, Subsystem: "cron", Name: "task_runs_total", Help: "定时任务执行次数",
+		}, []string{"task"})
+		failures := prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace, Subsystem: "cron", Name: "task_failures_total", Help: "定时任务失败次数",
+		}, []string{"task"})
+		duration := prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace, Subsystem: "cron", Name: "task_duration_seconds", Help: "定时任务耗时", Buckets: buckets,
+		}, []string{"task"})
+		registerer.MustRegister(runs, failures, duration)
+
+		inst.UseTask(func(name string, next instrument.TaskFunc) instrument.TaskFunc {
+			return func(ctx context.Context) error {
+				start := time.Now()
+				err := next(ctx)
+				runs.WithLabelValues(name).Inc()
+				if err != nil {
+					failures.WithLabelValues(name).Inc()
+				}
+				duration.WithLabelValues(name).Observe(time.Since(start).Seconds())
+				return err
+			}
+		})
+	}
+
+	path := cfg.GetString("metrics.path")
+	if path == "" {
+		path = "/metrics"
+	}
+	// 注册到 metrics.server 指定的监听，如只对内网开放的端口；不加埋点，/metrics 自身的请求不计入 http 指标
+	httpServers.Get(cfg.GetString("metrics.server")).AddRouterWithoutInstrument(router.Router{
+		Path:    path,
+		Handler: promhttp.HandlerFor(registry, promhttp.HandlerOpts{Registry: registry}),
+	})
+
+	log.Printf("%s🔗 -> Metrics all initialized successfully, exposed on %s. %s\n", "\033[32m", path, "\033[0m")
+
+	return registry, nil
+}
+
+// metricsBuckets 解析配置中的耗时直方图分桶，未配置时使用 prometheus 的默认分桶
+func metricsBuckets(raw interface{}) []float64 {
+	list, ok := raw.([]interface{})
+	if !ok || len(list) == 0 {
+		return prometheus.DefBuckets
+	}
+	buckets := make([]float64, 0, len(list))
+	for _, item := range list {
+		switch v := item.(type) {
+		case int:
+			buckets = append(buckets, float64(v))
+		case float64:
+			buckets = append(buckets, v)
+		}
+	}
+	return buckets
+}
+
+// metricsResponseWriter 记录响应状态码，保留 Flush 与 Hijack 以支持 SSE 与 websocket
+type metricsResponseWriter struct {
+	http.ResponseWriter
+	status      int
+	wroteHeader bool
+}
+
+func (w *metricsResponseWriter) WriteHeader(code int) {
+	if !w.wroteHeader {
+		w.status = code
+		w.wroteHeader = true
+	}
+	w.ResponseWriter.WriteHeader(code)
+}
+
+func (w *metricsResponseWriter) Write(b []byte) (int, error) {
+	w.wroteHeader = true
+	return w.ResponseWriter.Write(b)
+}
+
+func (w *metricsResponseWriter) Flush() {
+	if flusher, ok := w.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+func (w *metricsResponseWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	hijacker, ok := w.ResponseWriter.(http.Hijacker)
+	if !ok {
+		return nil, nil, fmt.Errorf("ResponseWriter 不支持 Hijack")
+	}
+	w.status = http.StatusSwitchingProtocols
+	return hijacker.Hijack()
+}
+
+func (w *metricsResponseWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+// metricsGormCallbacks 在 gorm 每类操作的前后注册回调，统计查询数与耗时，记录不存在不计为失败
+func metricsGormCallbacks(gormDB *gorm.DB, name string, queries *prometheus.CounterVec, duration *prometheus.HistogramVec) error {
+	const startKey = "metrics:start"
+	before := func(tx *gorm.DB) {
+		tx.InstanceSet(startKey, time.Now())
+	}
+	after := func(operation string) func(tx *gorm.DB) {
+		return func(tx *gorm.DB) {
+			start, ok := tx.InstanceGet(startKey)
+			if !ok {
+				return
+			}
+			result := "ok"
+			if tx.Error != nil && !errors.Is(tx.Error, gorm.ErrRecordNotFound) {
+				result = "error"
+			}
+			queries.WithLabelValues(name, operation, result).Inc()
+			duration.WithLabelValues(name, operation).Observe(time.Since(start.(time.Time)).Seconds())
+		}
+	}
+
+	callback := gormDB.Callback()
+	return errors.Join(
+		callback.Create().Before("gorm:create").Register("metrics:before_create", before),
+		callback.Create().After("gorm:create").Register("metrics:after_create", after("create")),
+		callback.Query().Before("gorm:query").Register("metrics:before_query", before),
+		callback.Query().After("gorm:query").Register("metrics:after_query", after("query")),
+		callback.Update().Before("gorm:update").Register("metrics:before_update", before),
+		callback.Update().After("gorm:update").Register("metrics:after_update", after("update")),
+		callback.Delete().Before("gorm:delete").Register("metrics:before_delete", before),
+		callback.Delete().After("gorm:delete").Register("metrics:after_delete", after("delete")),
+		callback.Row().Before("gorm:row").Register("metrics:before_row", before),
+		callback.Row().After("gorm:row").Register("metrics:after_row", after("row")),
+		callback.Raw().Before("gorm:raw").Register("metrics:before_raw", before),
+		callback.Raw().After("gorm:raw").Register("metrics:after_raw", after("raw")),
+	)
+}
+
+// metricsRedisHook 按命令统计 redis 调用数与耗时，redis.Nil 不计为失败
+type metricsRedisHook struct {
+	commands *prometheus.CounterVec
+	duration *prometheus.HistogramVec
+}
+
+func (h *metricsRedisHook) DialHook(next redis.DialHook) redis.DialHook {
+	return next
+}
+
+func (h *metricsRedisHook) ProcessHook(next redis.ProcessHook) redis.ProcessHook {
+	return func(ctx context.Context, cmd redis.Cmder) error {
+		start := time.Now()
+		err := next(ctx, cmd)
+		h.observe(cmd.Name(), start, err)
+		return err
+	}
+}
+
+func (h *metricsRedisHook) ProcessPipelineHook(next redis.ProcessPipelineHook) redis.ProcessPipelineHook {
+	return func(ctx context.Context, cmds []redis.Cmder) error {
+		start := time.Now()
+		err := next(ctx, cmds)
+		h.observe("pipeline", start, err)
+		return err
+	}
+}
+
+func (h *metricsRedisHook) observe(command string, start time.Time, err error) {
+	result := "ok"
+	if err != nil && !errors.Is(err, redis.Nil) {
+		result = "error"
+	}
+	h.commands.WithLabelValues(command, result).Inc()
+	h.duration.WithLabelValues(command).Observe(time.Since(start).Seconds())
+}
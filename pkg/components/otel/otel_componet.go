@@ -1,12 +1,48 @@
 package otel
 
 import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
 	"log"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sync"
 	"time"
 
+	"github.com/redis/go-redis/extra/redisotel/v9"
+	"github.com/redis/go-redis/v9"
+	"github.com/stones-hub/taurus-pro-common/pkg/logx"
 	"github.com/stones-hub/taurus-pro-config/pkg/config"
+	"github.com/stones-hub/taurus-pro-core/pkg/components/instrument"
+	"github.com/stones-hub/taurus-pro-core/pkg/components/lifecycle"
 	"github.com/stones-hub/taurus-pro-core/pkg/components/types"
 	"github.com/stones-hub/taurus-pro-opentelemetry/pkg/otelemetry"
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/exporters/otlp/otlplog/otlploggrpc"
+	"go.opentelemetry.io/otel/exporters/otlp/otlplog/otlploghttp"
+	"go.opentelemetry.io/otel/exporters/otlp/otlpmetric/otlpmetricgrpc"
+	"go.opentelemetry.io/otel/exporters/otlp/otlpmetric/otlpmetrichttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdoutlog"
+	"go.opentelemetry.io/otel/exporters/stdout/stdoutmetric"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	otellog "go.opentelemetry.io/otel/log"
+	"go.opentelemetry.io/otel/log/global"
+	"go.opentelemetry.io/otel/propagation"
+	sdklog "go.opentelemetry.io/otel/sdk/log"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+	"go.opentelemetry.io/otel/trace/embedded"
+	"gorm.io/gorm"
+	"gorm.io/plugin/opentelemetry/tracing"
 )
 
 var OtelComponent = types.Component{
@@ -17,15 +53,31 @@ var OtelComponent = types.Component{
 	IsCustom:     true,
 	Required:     false,
 	Dependencies: []string{"config"},
-	Wire:         []*types.Wire{otelWire},
+	Wire:         []*types.Wire{otelWire, otelMeterWire, otelLoggerWire},
 }
 
 var otelWire = &types.Wire{
-	RequirePath:  []string{"github.com/stones-hub/taurus-pro-opentelemetry/pkg/otelemetry"},
+	RequirePath: []string{
+		"context", "fmt", "io", "log", "net/http", "os", "path/filepath", "sync", "time",
+		"github.com/stones-hub/taurus-pro-opentelemetry/pkg/otelemetry",
+		"github.com/redis/go-redis/extra/redisotel/v9",
+		"github.com/redis/go-redis/v9",
+		"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc",
+		"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp",
+		"go.opentelemetry.io/otel",
+		"go.opentelemetry.io/otel/attribute",
+		"go.opentelemetry.io/otel/propagation",
+		"go.opentelemetry.io/otel/exporters/stdout/stdouttrace",
+		"sdktrace@go.opentelemetry.io/otel/sdk/trace",
+		"go.opentelemetry.io/otel/trace",
+		"go.opentelemetry.io/otel/trace/embedded",
+		"gorm.io/gorm",
+		"gorm.io/plugin/opentelemetry/tracing",
+	},
 	Name:         "OtelProvider",
 	Type:         "*otelemetry.OTelProvider",
 	ProviderName: "ProvideOtelComponent",
-	Provider: `func {{.ProviderName}}(cfg *config.Config) ({{.Type}}, func(), error) {
+	Provider: `func {{.ProviderName}}(cfg *config.Config, inst *Instrumentation, lc *LifecycleManager) ({{.Type}}, func(), error) {
 
 	enable := cfg.GetBool("otel.enable")
 	if !enable {
@@ -47,23 +99,37 @@ var otelWire = &types.Wire{
 		return nil, func() {}, err
 	}
 
-	provider, cleanup, err := otelemetry.NewOTelProvider(
-		otelemetry.WithServiceName(cfg.GetString("otel.service.name")),
-		otelemetry.WithServiceVersion(cfg.GetString("otel.service.version")),
-		otelemetry.WithEnvironment(cfg.GetString("otel.service.environment")), // 环境
+	var (
+		provider       *otelemetry.OTelProvider
+		tracerProvider trace.TracerProvider
+		cleanup        func()
+	)
+	protocol := cfg.GetString("otel.export.protocol")
+	if otelLocalProtocol(protocol) {
+		// stdout、file 不需要收集器，OtelProvider 为 nil，代码中通过 otel.Tracer(name) 或 otelemetry 注册的 tracer 创建 span
+		tracerProvider, cleanup, err = otelLocalTracerProvider(cfg, protocol, batchTimeout, exportTimeout, timeout)
+	} else {
+		provider, cleanup, err = otelemetry.NewOTelProvider(
+			otelemetry.WithServiceName(cfg.GetString("otel.service.name")),
+			otelemetry.WithServiceVersion(cfg.GetString("otel.service.version")),
+			otelemetry.WithEnvironment(cfg.GetString("otel.service.environment")), // 环境
 
-		otelemetry.WithExportProtocol(otelemetry.ExportProtocol(cfg.GetString("otel.export.protocol"))),
-		otelemetry.WithEndpoint(cfg.GetString("otel.export.endpoint")),
-		otelemetry.WithInsecure(cfg.GetBool("otel.export.insecure")),
-		otelemetry.WithTimeout(timeout),
+			otelemetry.WithExportProtocol(otelemetry.ExportProtocol(protocol)),
+			otelemetry.WithEndpoint(cfg.GetString("otel.export.endpoint")),
+			otelemetry.WithInsecure(cfg.GetBool("otel.export.insecure")),
+			otelemetry.WithTimeout(timeout),
 
-		otelemetry.WithSamplingRatio(cfg.GetFloat64("otel.sampling.ratio")),
+			otelemetry.WithSamplingRatio(cfg.GetFloat64("otel.sampling.ratio")),
 
-		otelemetry.WithBatchTimeout(batchTimeout),
-		otelemetry.WithMaxExportBatchSize(cfg.GetInt("otel.batch.max_size")),
-		otelemetry.WithMaxQueueSize(cfg.GetInt("otel.batch.max_queue_size")),
-		otelemetry.WithExportTimeout(exportTimeout),
-	)
+			otelemetry.WithBatchTimeout(batchTimeout),
+			otelemetry.WithMaxExportBatchSize(cfg.GetInt("otel.batch.max_size")),
+			otelemetry.WithMaxQueueSize(cfg.GetInt("otel.batch.max_queue_size")),
+			otelemetry.WithExportTimeout(exportTimeout),
+		)
+		if err == nil {
+			tracerProvider = &otelTracerProvider{provider: provider}
+		}
+	}
 
 	if err != nil {
 		log.Printf("%s🔗 -> Initialize otel components failed. %s\n", "\033[31m", "\033[0m")
@@ -75,17 +141,147 @@ var otelWire = &types.Wire{
 	// 添加配置的tracer
 	tracers := cfg.GetStringSlice("otel.tracers")
 	for _, tracer := range tracers {
-		otelemetry.RegisterTracer(tracer, provider.Tracer(tracer))
+		otelemetry.RegisterTracer(tracer, tracerProvider.Tracer(tracer))
 	}
 
-	return provider, func() {
+	// 设置全局的 TracerProvider 与 W3C 传播器，自动埋点使用同一个 provider，采样率同样来自 otel.sampling.ratio
+	otel.SetTracerProvider(tracerProvider)
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+	otelInstrument(cfg, inst)
+
+	// 在关闭的 resource 阶段导出剩余的数据，wire 的清理函数不再重复关闭
+	shutdown := sync.OnceFunc(func() {
 		cleanup()
 		log.Printf("%s🔗 -> Clean up otel components successfully. %s\n", "\033[32m", "\033[0m")
+	})
+	lc.OnShutdown(LifecyclePhaseResource, "otel", func(ctx context.Context) error {
+		shutdown()
+		return nil
+	})
+
+	return provider, shutdown, nil
+}
+// otelTracerProvider 将 OTelProvider 适配为标准的 trace.TracerProvider，设置为全局后自动埋点与 otel.Tracer 都使用它
+type otelTracerProvider struct {
+	embedded.TracerProvider
+	provider *otelemetry.OTelProvider
+}
+
+func (p *otelTracerProvider) Tracer(name string, _ ...trace.TracerOption) trace.Tracer {
+	return p.provider.Tracer(name)
+}
+
+// otelLocalProtocol stdout、file 协议在本地输出 JSONL，没有收集器时也可以查看追踪、指标与日志
+func otelLocalProtocol(protocol string) bool {
+	return protocol == "stdout" || protocol == "file"
+}
+
+// otelExportWriter 返回本地导出的输出位置，stdout 输出到标准输出，file 追加写入 otel.export.path 目录下的 <signal>.jsonl
+func otelExportWriter(cfg *config.Config, protocol, signal string) (io.Writer, func(), error) {
+	if protocol == "stdout" {
+		return os.Stdout, func() {}, nil
+	}
+
+	dir := cfg.GetString("otel.export.path")
+	if dir == "" {
+		dir = "logs/otel"
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, func() {}, fmt.Errorf("创建 otel 导出目录失败: %v", err)
+	}
+	file, err := os.OpenFile(filepath.Join(dir, signal+".jsonl"), os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return nil, func() {}, fmt.Errorf("打开 otel 导出文件失败: %v", err)
+	}
+	return file, func() { file.Close() }, nil
+}
+
+// otelLocalTracerProvider 创建导出到标准输出或本地文件的 TracerProvider，采样与批量配置和 OTLP 导出一致
+// 每个 span 输出为一行 JSON，可以通过 taurus trace view 以树形查看
+func otelLocalTracerProvider(cfg *config.Config, protocol string, batchTimeout, exportTimeout, timeout time.Duration) (trace.TracerProvider, func(), error) {
+	writer, closeWriter, err := otelExportWriter(cfg, protocol, "traces")
+	if err != nil {
+		return nil, func() {}, err
+	}
+
+	exporter, err := stdouttrace.New(stdouttrace.WithWriter(writer))
+	if err != nil {
+		closeWriter()
+		return nil, func() {}, err
+	}
+
+	res, err := otelResource(cfg)
+	if err != nil {
+		closeWriter()
+		return nil, func() {}, err
+	}
+
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithResource(res),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(cfg.GetFloat64("otel.sampling.ratio")))),
+		sdktrace.WithBatcher(exporter,
+			sdktrace.WithBatchTimeout(batchTimeout),
+			sdktrace.WithMaxExportBatchSize(cfg.GetInt("otel.batch.max_size")),
+			sdktrace.WithMaxQueueSize(cfg.GetInt("otel.batch.max_queue_size")),
+			sdktrace.WithExportTimeout(exportTimeout),
+		),
+	)
+
+	return provider, func() {
+		defer closeWriter()
+		ctx, cancel := context.WithTimeout(context.Background(), timeout)
+		defer cancel()
+		// 关闭前会导出队列中剩余的 span
+		if err := provider.Shutdown(ctx); err != nil {
+			log.Printf("%s🔗 -> Clean up otel tracer failed: %v %s\n", "\033[31m", err, "\033[0m")
+		}
 	}, nil
+}
+
+// otelInstrument 通过埋点注册表为 http、grpc、数据库、redis 自动加上追踪，跨服务的上下文使用 W3C traceparent 传播
+func otelInstrument(cfg *config.Config, inst *Instrumentation) {
+	if cfg.GetBool("otel.instrumentation.http") {
+		inst.UseHttp(func(route string, next http.Handler) http.Handler {
+			return otelhttp.NewHandler(next, route, otelhttp.WithSpanNameFormatter(func(_ string, r *http.Request) string {
+				return r.Method + " " + route
+			}))
+		})
+	}
+
+	if cfg.GetBool("otel.instrumentation.grpc") {
+		// 服务端的 stats.Handler 由 grpc 组件使用；grpc 客户端订阅 InstrumentKindGrpcClientStats，创建连接时通过 grpc.WithStatsHandler 加上
+		inst.Publish(InstrumentKindGrpcStats, "otel", otelgrpc.NewServerHandler())
+		inst.Publish(InstrumentKindGrpcClientStats, "otel", otelgrpc.NewClientHandler())
+	}
+
+	if cfg.GetBool("otel.instrumentation.db") {
+		inst.Subscribe(InstrumentKindGorm, func(name string, resource any) {
+			gormDB, ok := resource.(*gorm.DB)
+			if !ok {
+				return
+			}
+			// 指标由 metrics 组件统计，这里只记录追踪
+			if err := gormDB.Use(tracing.NewPlugin(tracing.WithoutMetrics(), tracing.WithAttributes(attribute.String("db.name", name)))); err != nil {
+				log.Printf("%s🔗 -> Otel instrument db %s failed: %v %s\n", "\033[31m", name, err, "\033[0m")
+			}
+		})
+	}
+
+	if cfg.GetBool("otel.instrumentation.redis") {
+		inst.Subscribe(InstrumentKindRedis, func(name string, resource any) {
+			client, ok := resource.(redis.UniversalClient)
+			if !ok {
+				return
+			}
+			if err := redisotel.InstrumentTracing(client); err != nil {
+				log.Printf("%s🔗 -> Otel instrument redis failed: %v %s\n", "\033[31m", err, "\033[0m")
+			}
+		})
+	}
 }`,
 }
 
-func ProvideOtelComponent(cfg *config.Config) (*otelemetry.OTelProvider, func(), error) {
+func ProvideOtelComponent(cfg *config.Config, inst *instrument.Registry, lc *lifecycle.Manager) (*otelemetry.OTelProvider, func(), error) {
 
 	enable := cfg.GetBool("otel.enable")
 	if !enable {
@@ -107,23 +303,37 @@ func ProvideOtelComponent(cfg *config.Config) (*otelemetry.OTelProvider, func(),
 		return nil, func() {}, err
 	}
 
-	provider, cleanup, err := otelemetry.NewOTelProvider(
-		otelemetry.WithServiceName(cfg.GetString("otel.service.name")),
-		otelemetry.WithServiceVersion(cfg.GetString("otel.service.version")),
-		otelemetry.WithEnvironment(cfg.GetString("otel.service.environment")), // 环境
+	var (
+		provider       *otelemetry.OTelProvider
+		tracerProvider trace.TracerProvider
+		cleanup        func()
+	)
+	protocol := cfg.GetString("otel.export.protocol")
+	if otelLocalProtocol(protocol) {
+		// stdout、file 不需要收集器，OtelProvider 为 nil，代码中通过 otel.Tracer(name) 或 otelemetry 注册的 tracer 创建 span
+		tracerProvider, cleanup, err = otelLocalTracerProvider(cfg, protocol, batchTimeout, exportTimeout, timeout)
+	} else {
+		provider, cleanup, err = otelemetry.NewOTelProvider(
+			otelemetry.WithServiceName(cfg.GetString("otel.service.name")),
+			otelemetry.WithServiceVersion(cfg.GetString("otel.service.version")),
+			otelemetry.WithEnvironment(cfg.GetString("otel.service.environment")), // 环境
 
-		otelemetry.WithExportProtocol(otelemetry.ExportProtocol(cfg.GetString("otel.export.protocol"))),
-		otelemetry.WithEndpoint(cfg.GetString("otel.export.endpoint")),
-		otelemetry.WithInsecure(cfg.GetBool("otel.export.insecure")),
-		otelemetry.WithTimeout(timeout),
+			otelemetry.WithExportProtocol(otelemetry.ExportProtocol(protocol)),
+			otelemetry.WithEndpoint(cfg.GetString("otel.export.endpoint")),
+			otelemetry.WithInsecure(cfg.GetBool("otel.export.insecure")),
+			otelemetry.WithTimeout(timeout),
 
-		otelemetry.WithSamplingRatio(cfg.GetFloat64("otel.sampling.ratio")),
+			otelemetry.WithSamplingRatio(cfg.GetFloat64("otel.sampling.ratio")),
 
-		otelemetry.WithBatchTimeout(batchTimeout),
-		otelemetry.WithMaxExportBatchSize(cfg.GetInt("otel.batch.max_size")),
-		otelemetry.WithMaxQueueSize(cfg.GetInt("otel.batch.max_queue_size")),
-		otelemetry.WithExportTimeout(exportTimeout),
-	)
+			otelemetry.WithBatchTimeout(batchTimeout),
+			otelemetry.WithMaxExportBatchSize(cfg.GetInt("otel.batch.max_size")),
+			otelemetry.WithMaxQueueSize(cfg.GetInt("otel.batch.max_queue_size")),
+			otelemetry.WithExportTimeout(exportTimeout),
+		)
+		if err == nil {
+			tracerProvider = &otelTracerProvider{provider: provider}
+		}
+	}
 
 	if err != nil {
 		log.Printf("%s🔗 -> Initialize otel components failed. %s\n", "\033[31m", "\033[0m")
@@ -135,11 +345,647 @@ func ProvideOtelComponent(cfg *config.Config) (*otelemetry.OTelProvider, func(),
 	// 添加配置的tracer
 	tracers := cfg.GetStringSlice("otel.tracers")
 	for _, tracer := range tracers {
-		otelemetry.RegisterTracer(tracer, provider.Tracer(tracer))
+		otelemetry.RegisterTracer(tracer, tracerProvider.Tracer(tracer))
 	}
 
-	return provider, func() {
+	// 设置全局的 TracerProvider 与 W3C 传播器，自动埋点使用同一个 provider，采样率同样来自 otel.sampling.ratio
+	otel.SetTracerProvider(tracerProvider)
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+	otelInstrument(cfg, inst)
+
+	// 在关闭的 resource 阶段导出剩余的数据，wire 的清理函数不再重复关闭
+	shutdown := sync.OnceFunc(func() {
 		cleanup()
 		log.Printf("%s🔗 -> Clean up otel components successfully. %s\n", "\033[32m", "\033[0m")
+	})
+	lc.OnShutdown(lifecycle.PhaseResource, "otel", func(ctx context.Context) error {
+		shutdown()
+		return nil
+	})
+
+	return provider, shutdown, nil
+}
+
+// otelTracerProvider 将 OTelProvider 适配为标准的 trace.TracerProvider，设置为全局后自动埋点与 otel.Tracer 都使用它
+type otelTracerProvider struct {
+	embedded.TracerProvider
+	provider *otelemetry.OTelProvider
+}
+
+func (p *otelTracerProvider) Tracer(name string, _ ...trace.TracerOption) trace.Tracer {
+	return p.provider.Tracer(name)
+}
+
+// otelLocalProtocol stdout、file 协议在本地输出 JSONL，没有收集器时也可以查看追踪、指标与日志
+func otelLocalProtocol(protocol string) bool {
+	return protocol == "stdout" || protocol == "file"
+}
+
+// otelExportWriter 返回本地导出的输出位置，stdout 输出到标准输出，file 追加写入 otel.export.path 目录下的 <signal>.jsonl
+func otelExportWriter(cfg *config.Config, protocol, signal string) (io.Writer, func(), error) {
+	if protocol == "stdout" {
+		return os.Stdout, func() {}, nil
+	}
+
+	dir := cfg.GetString("otel.export.path")
+	if dir == "" {
+		dir = "logs/otel"
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, func() {}, fmt.Errorf("创建 otel 导出目录失败: %v", err)
+	}
+	file, err := os.OpenFile(filepath.Join(dir, signal+".jsonl"), os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return nil, func() {}, fmt.Errorf("打开 otel 导出文件失败: %v", err)
+	}
+	return file, func() { file.Close() }, nil
+}
+
+// otelLocalTracerProvider 创建导出到标准输出或本地文件的 TracerProvider，采样与批量配置和 OTLP 导出一致
+// 每个 span 输出为一行 JSON，可以通过 taurus trace view 以树形查看
+func otelLocalTracerProvider(cfg *config.Config, protocol string, batchTimeout, exportTimeout, timeout time.Duration) (trace.TracerProvider, func(), error) {
+	writer, closeWriter, err := otelExportWriter(cfg, protocol, "traces")
+	if err != nil {
+		return nil, func() {}, err
+	}
+
+	exporter, err := stdouttrace.New(stdouttrace.WithWriter(writer))
+	if err != nil {
+		closeWriter()
+		return nil, func() {}, err
+	}
+
+	res, err := otelResource(cfg)
+	if err != nil {
+		closeWriter()
+		return nil, func() {}, err
+	}
+
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithResource(res),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(cfg.GetFloat64("otel.sampling.ratio")))),
+		sdktrace.WithBatcher(exporter,
+			sdktrace.WithBatchTimeout(batchTimeout),
+			sdktrace.WithMaxExportBatchSize(cfg.GetInt("otel.batch.max_size")),
+			sdktrace.WithMaxQueueSize(cfg.GetInt("otel.batch.max_queue_size")),
+			sdktrace.WithExportTimeout(exportTimeout),
+		),
+	)
+
+	return provider, func() {
+		defer closeWriter()
+		ctx, cancel := context.WithTimeout(context.Background(), timeout)
+		defer cancel()
+		// 关闭前会导出队列中剩余的 span
+		if err := provider.Shutdown(ctx); err != nil {
+			log.Printf("%s🔗 -> Clean up otel tracer failed: %v %s\n", "\033[31m", err, "\033[0m")
+		}
 	}, nil
 }
+
+// otelInstrument 通过埋点注册表为 http、grpc、数据库、redis 自动加上追踪，跨服务的上下文使用 W3C traceparent 传播
+func otelInstrument(cfg *config.Config, inst *instrument.Registry) {
+	if cfg.GetBool("otel.instrumentation.http") {
+		inst.UseHttp(func(route string, next http.Handler) http.Handler {
+			return otelhttp.NewHandler(next, route, otelhttp.WithSpanNameFormatter(func(_ string, r *http.Request) string {
+				return r.Method + " " + route
+			}))
+		})
+	}
+
+	if cfg.GetBool("otel.instrumentation.grpc") {
+		// 服务端的 stats.Handler 由 grpc 组件使用；grpc 客户端订阅 instrument.KindGrpcClientStats，创建连接时通过 grpc.WithStatsHandler 加上
+		inst.Publish(instrument.KindGrpcStats, "otel", otelgrpc.NewServerHandler())
+		inst.Publish(instrument.KindGrpcClientStats, "otel", otelgrpc.NewClientHandler())
+	}
+
+	if cfg.GetBool("otel.instrumentation.db") {
+		inst.Subscribe(instrument.KindGorm, func(name string, resource any) {
+			gormDB, ok := resource.(*gorm.DB)
+			if !ok {
+				return
+			}
+			// 指标由 metrics 组件统计，这里只记录追踪
+			if err := gormDB.Use(tracing.NewPlugin(tracing.WithoutMetrics(), tracing.WithAttributes(attribute.String("db.name", name)))); err != nil {
+				log.Printf("%s🔗 -> Otel instrument db %s failed: %v %s\n", "\033[31m", name, err, "\033[0m")
+			}
+		})
+	}
+
+	if cfg.GetBool("otel.instrumentation.redis") {
+		inst.Subscribe(instrument.KindRedis, func(name string, resource any) {
+			client, ok := resource.(redis.UniversalClient)
+			if !ok {
+				return
+			}
+			if err := redisotel.InstrumentTracing(client); err != nil {
+				log.Printf("%s🔗 -> Otel instrument redis failed: %v %s\n", "\033[31m", err, "\033[0m")
+			}
+		})
+	}
+}
+
+var otelMeterWire = &types.Wire{
+	RequirePath: []string{
+		"context", "fmt", "io", "log", "sync", "time",
+		"go.opentelemetry.io/otel",
+		"go.opentelemetry.io/otel/attribute",
+		"go.opentelemetry.io/otel/sdk/resource",
+		"sdkmetric@go.opentelemetry.io/otel/sdk/metric",
+		"go.opentelemetry.io/otel/exporters/otlp/otlpmetric/otlpmetricgrpc",
+		"go.opentelemetry.io/otel/exporters/otlp/otlpmetric/otlpmetrichttp",
+		"go.opentelemetry.io/otel/exporters/stdout/stdoutmetric",
+	},
+	Name:         "OtelMeter",
+	Type:         "*sdkmetric.MeterProvider",
+	ProviderName: "ProvideOtelMeterComponent",
+	Provider: `func {{.ProviderName}}(cfg *config.Config, lc *LifecycleManager) ({{.Type}}, func(), error) {
+	if !cfg.GetBool("otel.enable") || !cfg.GetBool("otel.metrics.enable") {
+		return nil, func() {}, nil
+	}
+
+	timeout, err := time.ParseDuration(cfg.GetString("otel.export.timeout"))
+	if err != nil {
+		return nil, func() {}, err
+	}
+
+	interval, err := time.ParseDuration(cfg.GetString("otel.metrics.interval"))
+	if err != nil {
+		return nil, func() {}, err
+	}
+
+	// 与 tracer 使用相同的导出配置
+	ctx := context.Background()
+	endpoint := cfg.GetString("otel.export.endpoint")
+	var exporter sdkmetric.Exporter
+	closeWriter := func() {}
+	switch protocol := cfg.GetString("otel.export.protocol"); protocol {
+	case "grpc":
+		options := []otlpmetricgrpc.Option{otlpmetricgrpc.WithEndpoint(endpoint), otlpmetricgrpc.WithTimeout(timeout)}
+		if cfg.GetBool("otel.export.insecure") {
+			options = append(options, otlpmetricgrpc.WithInsecure())
+		}
+		exporter, err = otlpmetricgrpc.New(ctx, options...)
+	case "http":
+		options := []otlpmetrichttp.Option{otlpmetrichttp.WithEndpoint(endpoint), otlpmetrichttp.WithTimeout(timeout)}
+		if cfg.GetBool("otel.export.insecure") {
+			options = append(options, otlpmetrichttp.WithInsecure())
+		}
+		exporter, err = otlpmetrichttp.New(ctx, options...)
+	case "stdout", "file":
+		var writer io.Writer
+		writer, closeWriter, err = otelExportWriter(cfg, protocol, "metrics")
+		if err == nil {
+			exporter, err = stdoutmetric.New(stdoutmetric.WithWriter(writer))
+		}
+	default:
+		err = fmt.Errorf("不支持的导出协议: %s", protocol)
+	}
+	if err != nil {
+		closeWriter()
+		log.Printf("%s🔗 -> Initialize otel meter failed: %v %s\n", "\033[31m", err, "\033[0m")
+		return nil, func() {}, err
+	}
+
+	res, err := otelResource(cfg)
+	if err != nil {
+		closeWriter()
+		return nil, func() {}, err
+	}
+
+	provider := sdkmetric.NewMeterProvider(
+		sdkmetric.WithResource(res),
+		sdkmetric.WithReader(sdkmetric.NewPeriodicReader(exporter, sdkmetric.WithInterval(interval))),
+	)
+	otel.SetMeterProvider(provider)
+
+	log.Printf("%s🔗 -> Initialize otel meter successfully. %s\n", "\033[32m", "\033[0m")
+
+	// 在关闭的 resource 阶段导出剩余的数据，wire 的清理函数不再重复关闭
+	shutdown := sync.OnceFunc(func() {
+		defer closeWriter()
+		ctx, cancel := context.WithTimeout(context.Background(), timeout)
+		defer cancel()
+		// 关闭前会导出最后一批指标
+		if err := provider.Shutdown(ctx); err != nil {
+			log.Printf("%s🔗 -> Clean up otel meter failed: %v %s\n", "\033[31m", err, "\033[0m")
+			return
+		}
+		log.Printf("%s🔗 -> Clean up otel meter successfully. %s\n", "\033[32m", "\033[0m")
+	})
+	lc.OnShutdown(LifecyclePhaseResource, "otel-meter", func(ctx context.Context) error {
+		shutdown()
+		return nil
+	})
+
+	return provider, shutdown, nil
+}
+
+// otelResource 指标与日志使用与 tracer 相同的服务信息，后端可以按服务关联三类数据
+func otelResource(cfg *config.Config) (*resource.Resource, error) {
+	return resource.Merge(resource.Default(), resource.NewSchemaless(
+		attribute.String("service.name", cfg.GetString("otel.service.name")),
+		attribute.String("service.version", cfg.GetString("otel.service.version")),
+		attribute.String("deployment.environment", cfg.GetString("otel.service.environment")),
+	))
+}`,
+}
+
+func ProvideOtelMeterComponent(cfg *config.Config, lc *lifecycle.Manager) (*sdkmetric.MeterProvider, func(), error) {
+	if !cfg.GetBool("otel.enable") || !cfg.GetBool("otel.metrics.enable") {
+		return nil, func() {}, nil
+	}
+
+	timeout, err := time.ParseDuration(cfg.GetString("otel.export.timeout"))
+	if err != nil {
+		return nil, func() {}, err
+	}
+
+	interval, err := time.ParseDuration(cfg.GetString("otel.metrics.interval"))
+	if err != nil {
+		return nil, func() {}, err
+	}
+
+	// 与 tracer 使用相同的导出配置
+	ctx := context.Background()
+	endpoint := cfg.GetString("otel.export.endpoint")
+	var exporter sdkmetric.Exporter
+	closeWriter := func() {}
+	switch protocol := cfg.GetString("otel.export.protocol"); protocol {
+	case "grpc":
+		options := []otlpmetricgrpc.Option{otlpmetricgrpc.WithEndpoint(endpoint), otlpmetricgrpc.WithTimeout(timeout)}
+		if cfg.GetBool("otel.export.insecure") {
+			options = append(options, otlpmetricgrpc.WithInsecure())
+		}
+		exporter, err = otlpmetricgrpc.New(ctx, options...)
+	case "http":
+		options := []otlpmetrichttp.Option{otlpmetrichttp.WithEndpoint(endpoint), otlpmetrichttp.WithTimeout(timeout)}
+		if cfg.GetBool("otel.export.insecure") {
+			options = append(options, otlpmetrichttp.WithInsecure())
+		}
+		exporter, err = otlpmetrichttp.New(ctx, options...)
+	case "stdout", "file":
+		var writer io.Writer
+		writer, closeWriter, err = otelExportWriter(cfg, protocol, "metrics")
+		if err == nil {
+			exporter, err = stdoutmetric.New(stdoutmetric.WithWriter(writer))
+		}
+	default:
+		err = fmt.Errorf("不支持的导出协议: %s", protocol)
+	}
+	if err != nil {
+		closeWriter()
+		log.Printf("%s🔗 -> Initialize otel meter failed: %v %s\n", "\033[31m", err, "\033[0m")
+		return nil, func() {}, err
+	}
+
+	res, err := otelResource(cfg)
+	if err != nil {
+		closeWriter()
+		return nil, func() {}, err
+	}
+
+	provider := sdkmetric.NewMeterProvider(
+		sdkmetric.WithResource(res),
+		sdkmetric.WithReader(sdkmetric.NewPeriodicReader(exporter, sdkmetric.WithInterval(interval))),
+	)
+	otel.SetMeterProvider(provider)
+
+	log.Printf("%s🔗 -> Initialize otel meter successfully. %s\n", "\033[32m", "\033[0m")
+
+	// 在关闭的 resource 阶段导出剩余的数据，wire 的清理函数不再重复关闭
+	shutdown := sync.OnceFunc(func() {
+		defer closeWriter()
+		ctx, cancel := context.WithTimeout(context.Background(), timeout)
+		defer cancel()
+		// 关闭前会导出最后一批指标
+		if err := provider.Shutdown(ctx); err != nil {
+			log.Printf("%s🔗 -> Clean up otel meter failed: %v %s\n", "\033[31m", err, "\033[0m")
+			return
+		}
+		log.Printf("%s🔗 -> Clean up otel meter successfully. %s\n", "\033[32m", "\033[0m")
+	})
+	lc.OnShutdown(lifecycle.PhaseResource, "otel-meter", func(ctx context.Context) error {
+		shutdown()
+		return nil
+	})
+
+	return provider, shutdown, nil
+}
+
+// otelResource 指标与日志使用与 tracer 相同的服务信息，后端可以按服务关联三类数据
+func otelResource(cfg *config.Config) (*resource.Resource, error) {
+	return resource.Merge(resource.Default(), resource.NewSchemaless(
+		attribute.String("service.name", cfg.GetString("otel.service.name")),
+		attribute.String("service.version", cfg.GetString("otel.service.version")),
+		attribute.String("deployment.environment", cfg.GetString("otel.service.environment")),
+	))
+}
+
+var otelLoggerWire = &types.Wire{
+	RequirePath: []string{
+		"context", "encoding/json", "fmt", "io", "log", "regexp", "sync", "time",
+		"github.com/stones-hub/taurus-pro-common/pkg/logx",
+		"otellog@go.opentelemetry.io/otel/log",
+		"go.opentelemetry.io/otel/log/global",
+		"sdklog@go.opentelemetry.io/otel/sdk/log",
+		"go.opentelemetry.io/otel/exporters/otlp/otlplog/otlploggrpc",
+		"go.opentelemetry.io/otel/exporters/otlp/otlplog/otlploghttp",
+		"go.opentelemetry.io/otel/exporters/stdout/stdoutlog",
+		"go.opentelemetry.io/otel/trace",
+	},
+	Name:         "OtelLogger",
+	Type:         "*sdklog.LoggerProvider",
+	ProviderName: "ProvideOtelLoggerComponent",
+	Provider: `func {{.ProviderName}}(cfg *config.Config, lc *LifecycleManager) ({{.Type}}, func(), error) {
+	if !cfg.GetBool("otel.enable") || !cfg.GetBool("otel.logs.enable") {
+		return nil, func() {}, nil
+	}
+
+	timeout, err := time.ParseDuration(cfg.GetString("otel.export.timeout"))
+	if err != nil {
+		return nil, func() {}, err
+	}
+
+	// 与 tracer 使用相同的导出配置
+	ctx := context.Background()
+	endpoint := cfg.GetString("otel.export.endpoint")
+	var exporter sdklog.Exporter
+	closeWriter := func() {}
+	switch protocol := cfg.GetString("otel.export.protocol"); protocol {
+	case "grpc":
+		options := []otlploggrpc.Option{otlploggrpc.WithEndpoint(endpoint), otlploggrpc.WithTimeout(timeout)}
+		if cfg.GetBool("otel.export.insecure") {
+			options = append(options, otlploggrpc.WithInsecure())
+		}
+		exporter, err = otlploggrpc.New(ctx, options...)
+	case "http":
+		options := []otlploghttp.Option{otlploghttp.WithEndpoint(endpoint), otlploghttp.WithTimeout(timeout)}
+		if cfg.GetBool("otel.export.insecure") {
+			options = append(options, otlploghttp.WithInsecure())
+		}
+		exporter, err = otlploghttp.New(ctx, options...)
+	case "stdout", "file":
+		var writer io.Writer
+		writer, closeWriter, err = otelExportWriter(cfg, protocol, "logs")
+		if err == nil {
+			exporter, err = stdoutlog.New(stdoutlog.WithWriter(writer))
+		}
+	default:
+		err = fmt.Errorf("不支持的导出协议: %s", protocol)
+	}
+	if err != nil {
+		closeWriter()
+		log.Printf("%s🔗 -> Initialize otel logger failed: %v %s\n", "\033[31m", err, "\033[0m")
+		return nil, func() {}, err
+	}
+
+	res, err := otelResource(cfg)
+	if err != nil {
+		closeWriter()
+		return nil, func() {}, err
+	}
+
+	provider := sdklog.NewLoggerProvider(
+		sdklog.WithResource(res),
+		sdklog.WithProcessor(sdklog.NewBatchProcessor(exporter)),
+	)
+	// 格式为 otel 的 logx 日志通过全局的 LoggerProvider 发送
+	global.SetLoggerProvider(provider)
+
+	log.Printf("%s🔗 -> Initialize otel logger successfully. %s\n", "\033[32m", "\033[0m")
+
+	// 在关闭的 resource 阶段导出剩余的数据，wire 的清理函数不再重复关闭
+	shutdown := sync.OnceFunc(func() {
+		defer closeWriter()
+		ctx, cancel := context.WithTimeout(context.Background(), timeout)
+		defer cancel()
+		if err := provider.Shutdown(ctx); err != nil {
+			log.Printf("%s🔗 -> Clean up otel logger failed: %v %s\n", "\033[31m", err, "\033[0m")
+			return
+		}
+		log.Printf("%s🔗 -> Clean up otel logger successfully. %s\n", "\033[32m", "\033[0m")
+	})
+	lc.OnShutdown(LifecyclePhaseResource, "otel-logger", func(ctx context.Context) error {
+		shutdown()
+		return nil
+	})
+
+	return provider, shutdown, nil
+}
+
+func init() {
+	// logger.yaml 中 formatter 为 otel 的日志同时发送到 otel，需要在日志组件初始化之前注册
+	logx.RegisterFormatter("otel", &otelLogFormatter{})
+}
+
+// otelTracePattern 匹配消息开头由 taurus.TraceFields(ctx) 生成的 trace/span ID
+var otelTracePattern = regexp.MustCompile("^trace_id=([0-9a-f]{32}) span_id=([0-9a-f]{16}) ?")
+
+// otelSeverities logx 日志等级对应的 otel 日志等级，下标为 logx.Level
+var otelSeverities = []struct {
+	severity otellog.Severity
+	text     string
+}{
+	{otellog.SeverityDebug, "DEBUG"},
+	{otellog.SeverityInfo, "INFO"},
+	{otellog.SeverityWarn, "WARN"},
+	{otellog.SeverityError, "ERROR"},
+	{otellog.SeverityFatal, "FATAL"},
+}
+
+// otelLogFormatter logx 日志桥，以 JSON 格式写入日志文件，同时把日志发送到全局的 otel LoggerProvider
+// 未启用 otel 日志时全局的 LoggerProvider 为空实现，只输出 JSON
+type otelLogFormatter struct{}
+
+func (f *otelLogFormatter) Format(level logx.Level, file string, line int, message string) string {
+	now := time.Now()
+	severity := otelSeverities[0]
+	if int(level) >= 0 && int(level) < len(otelSeverities) {
+		severity = otelSeverities[level]
+	}
+
+	// 消息开头的 trace/span ID 从正文中移除，作为日志的 trace 上下文
+	ctx := context.Background()
+	entry := map[string]any{
+		"time":   now.Format(time.RFC3339Nano),
+		"level":  severity.text,
+		"caller": fmt.Sprintf("%s:%d", file, line),
+	}
+	if match := otelTracePattern.FindStringSubmatch(message); match != nil {
+		traceID, _ := trace.TraceIDFromHex(match[1])
+		spanID, _ := trace.SpanIDFromHex(match[2])
+		ctx = trace.ContextWithSpanContext(ctx, trace.NewSpanContext(trace.SpanContextConfig{
+			TraceID:    traceID,
+			SpanID:     spanID,
+			TraceFlags: trace.FlagsSampled,
+			Remote:     true,
+		}))
+		message = message[len(match[0]):]
+		entry["trace_id"] = match[1]
+		entry["span_id"] = match[2]
+	}
+	entry["message"] = message
+
+	var record otellog.Record
+	record.SetTimestamp(now)
+	record.SetObservedTimestamp(now)
+	record.SetSeverity(severity.severity)
+	record.SetSeverityText(severity.text)
+	record.SetBody(otellog.StringValue(message))
+	record.AddAttributes(otellog.String("code.filepath", file), otellog.Int("code.lineno", line))
+	global.GetLoggerProvider().Logger("logx").Emit(ctx, record)
+
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return message
+	}
+	return string(data)
+}`,
+}
+
+func ProvideOtelLoggerComponent(cfg *config.Config, lc *lifecycle.Manager) (*sdklog.LoggerProvider, func(), error) {
+	if !cfg.GetBool("otel.enable") || !cfg.GetBool("otel.logs.enable") {
+		return nil, func() {}, nil
+	}
+
+	timeout, err := time.ParseDuration(cfg.GetString("otel.export.timeout"))
+	if err != nil {
+		return nil, func() {}, err
+	}
+
+	// 与 tracer 使用相同的导出配置
+	ctx := context.Background()
+	endpoint := cfg.GetString("otel.export.endpoint")
+	var exporter sdklog.Exporter
+	closeWriter := func() {}
+	switch protocol := cfg.GetString("otel.export.protocol"); protocol {
+	case "grpc":
+		options := []otlploggrpc.Option{otlploggrpc.WithEndpoint(endpoint), otlploggrpc.WithTimeout(timeout)}
+		if cfg.GetBool("otel.export.insecure") {
+			options = append(options, otlploggrpc.WithInsecure())
+		}
+		exporter, err = otlploggrpc.New(ctx, options...)
+	case "http":
+		options := []otlploghttp.Option{otlploghttp.WithEndpoint(endpoint), otlploghttp.WithTimeout(timeout)}
+		if cfg.GetBool("otel.export.insecure") {
+			options = append(options, otlploghttp.WithInsecure())
+		}
+		exporter, err = otlploghttp.New(ctx, options...)
+	case "stdout", "file":
+		var writer io.Writer
+		writer, closeWriter, err = otelExportWriter(cfg, protocol, "logs")
+		if err == nil {
+			exporter, err = stdoutlog.New(stdoutlog.WithWriter(writer))
+		}
+	default:
+		err = fmt.Errorf("不支持的导出协议: %s", protocol)
+	}
+	if err != nil {
+		closeWriter()
+		log.Printf("%s🔗 -> Initialize otel logger failed: %v %s\n", "\033[31m", err, "\033[0m")
+		return nil, func() {}, err
+	}
+
+	res, err := otelResource(cfg)
+	if err != nil {
+		closeWriter()
+		return nil, func() {}, err
+	}
+
+	provider := sdklog.NewLoggerProvider(
+		sdklog.WithResource(res),
+		sdklog.WithProcessor(sdklog.NewBatchProcessor(exporter)),
+	)
+	// 格式为 otel 的 logx 日志通过全局的 LoggerProvider 发送
+	global.SetLoggerProvider(provider)
+
+	log.Printf("%s🔗 -> Initialize otel logger successfully. %s\n", "\033[32m", "\033[0m")
+
+	// 在关闭的 resource 阶段导出剩余的数据，wire 的清理函数不再重复关闭
+	shutdown := sync.OnceFunc(func() {
+		defer closeWriter()
+		ctx, cancel := context.WithTimeout(context.Background(), timeout)
+		defer cancel()
+		if err := provider.Shutdown(ctx); err != nil {
+			log.Printf("%s🔗 -> Clean up otel logger failed: %v %s\n", "\033[31m", err, "\033[0m")
+			return
+		}
+		log.Printf("%s🔗 -> Clean up otel logger successfully. %s\n", "\033[32m", "\033[0m")
+	})
+	lc.OnShutdown(lifecycle.PhaseResource, "otel-logger", func(ctx context.Context) error {
+		shutdown()
+		return nil
+	})
+
+	return provider, shutdown, nil
+}
+
+func init() {
+	// logger.yaml 中 formatter 为 otel 的日志同时发送到 otel，需要在日志组件初始化之前注册
+	logx.RegisterFormatter("otel", &otelLogFormatter{})
+}
+
+// otelTracePattern 匹配消息开头由 taurus.TraceFields(ctx) 生成的 trace/span ID
+var otelTracePattern = regexp.MustCompile("^trace_id=([0-9a-f]{32}) span_id=([0-9a-f]{16}) ?")
+
+// otelSeverities logx 日志等级对应的 otel 日志等级，下标为 logx.Level
+var otelSeverities = []struct {
+	severity otellog.Severity
+	text     string
+}{
+	{otellog.SeverityDebug, "DEBUG"},
+	{otellog.SeverityInfo, "INFO"},
+	{otellog.SeverityWarn, "WARN"},
+	{otellog.SeverityError, "ERROR"},
+	{otellog.SeverityFatal, "FATAL"},
+}
+
+// otelLogFormatter logx 日志桥，以 JSON 格式写入日志文件，同时把日志发送到全局的 otel LoggerProvider
+// 未启用 otel 日志时全局的 LoggerProvider 为空实现，只输出 JSON
+type otelLogFormatter struct{}
+
+func (f *otelLogFormatter) Format(level logx.Level, file string, line int, message string) string {
+	now := time.Now()
+	severity := otelSeverities[0]
+	if int(level) >= 0 && int(level) < len(otelSeverities) {
+		severity = otelSeverities[level]
+	}
+
+	// 消息开头的 trace/span ID 从正文中移除，作为日志的 trace 上下文
+	ctx := context.Background()
+	entry := map[string]any{
+		"time":   now.Format(time.RFC3339Nano),
+		"level":  severity.text,
+		"caller": fmt.Sprintf("%s:%d", file, line),
+	}
+	if match := otelTracePattern.FindStringSubmatch(message); match != nil {
+		traceID, _ := trace.TraceIDFromHex(match[1])
+		spanID, _ := trace.SpanIDFromHex(match[2])
+		ctx = trace.ContextWithSpanContext(ctx, trace.NewSpanContext(trace.SpanContextConfig{
+			TraceID:    traceID,
+			SpanID:     spanID,
+			TraceFlags: trace.FlagsSampled,
+			Remote:     true,
+		}))
+		message = message[len(match[0]):]
+		entry["trace_id"] = match[1]
+		entry["span_id"] = match[2]
+	}
+	entry["message"] = message
+
+	var record otellog.Record
+	record.SetTimestamp(now)
+	record.SetObservedTimestamp(now)
+	record.SetSeverity(severity.severity)
+	record.SetSeverityText(severity.text)
+	record.SetBody(otellog.StringValue(message))
+	record.AddAttributes(otellog.String("code.filepath", file), otellog.Int("code.lineno", line))
+	global.GetLoggerProvider().Logger("logx").Emit(ctx, record)
+
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return message
+	}
+	return string(data)
+}
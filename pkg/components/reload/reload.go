@@ -0,0 +1,205 @@
+// Package reload 是生成项目中 internal/taurus/reload.go 的对应实现，供组件的 Go 版本 Provider 使用
+package reload
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Func 应用重载后的配置，changed 为注册时关心的配置中发生变化的键
+type Func func(changed []string) error
+
+// Report 一次重载的结果
+type Report struct {
+	Changed []string          `json:"changed"`           // 发生变化的配置键，如 loggers.0.log_level
+	Applied []string          `json:"applied"`           // 已经应用新配置的组件
+	Failed  map[string]string `json:"failed,omitempty"`  // 应用失败的组件与错误
+	Restart []string          `json:"restart,omitempty"` // 没有组件能够热更新、需要重启才能生效的配置键
+}
+
+// entry 注册到重载器的组件
+type entry struct {
+	name     string
+	prefixes []string
+	fn       Func
+	every    bool
+	applied  map[string]any // 组件最后一次成功应用的配置，应用失败的变化在下一次重载时重新分发
+}
+
+// Reloader 重新加载配置并把变化分发给注册的组件，由 SIGHUP 触发
+// 组件在 Provider 中注册自己可以热更新的配置前缀，没有组件处理的变化会在报告中标记为需要重启
+type Reloader struct {
+	mu       sync.Mutex
+	load     func() (string, error)
+	snapshot map[string]any
+	entries  []*entry
+	names    map[string]int // 按名称统计注册的组件，同名的组件在报告中按注册顺序加上序号
+}
+
+// NewReloader 创建配置重载器，snapshot 为当前配置的 JSON，load 重新读取配置并返回新配置的 JSON
+func NewReloader(snapshot string, load func() (string, error)) *Reloader {
+	r := &Reloader{load: load, snapshot: make(map[string]any)}
+	if values, err := flattenJSON(snapshot); err == nil {
+		r.snapshot = values
+	}
+	return r
+}
+
+// Register 注册组件的重载函数，prefixes 为组件可以热更新的配置前缀，如 "http.rate_limit"、"cron.schedules"
+// 前缀下的任意配置发生变化时调用 fn
+func (r *Reloader) Register(name string, fn Func, prefixes ...string) {
+	r.add(&entry{name: name, prefixes: prefixes, fn: fn})
+}
+
+// RegisterEvery 与 Register 相同，但每次重载都会调用 fn，即使配置没有变化，如重新打开日志文件
+func (r *Reloader) RegisterEvery(name string, fn Func, prefixes ...string) {
+	r.add(&entry{name: name, prefixes: prefixes, fn: fn, every: true})
+}
+
+// add 添加组件，组件从当前的配置开始记录已经应用的配置
+// 同名的组件在报告中记为 name#2、name#3，应用结果不会互相覆盖
+func (r *Reloader) add(e *entry) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.names == nil {
+		r.names = make(map[string]int)
+	}
+	r.names[e.name]++
+	if count := r.names[e.name]; count > 1 {
+		name := fmt.Sprintf("%s#%d", e.name, count)
+		log.Printf("%s🔗 -> 重载组件 %s 重复注册，报告中记为 %s %s\n", "\033[33m", e.name, name, "\033[0m")
+		e.name = name
+	}
+	e.applied = r.snapshot
+	r.entries = append(r.entries, e)
+}
+
+// Reload 重新加载配置，按注册顺序把变化分发给组件，返回本次重载的报告
+// 加载失败时返回错误，正在使用的配置保持不变；组件应用失败只记录在报告中，不影响其他组件，
+// 失败的组件保留原来已经应用的配置，下一次重载时重新分发这些变化
+func (r *Reloader) Reload() (*Report, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	raw, err := r.load()
+	if err != nil {
+		return nil, err
+	}
+	values, err := flattenJSON(raw)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &Report{
+		Changed: changedKeys(r.snapshot, values),
+		Applied: make([]string, 0),
+		Failed:  make(map[string]string),
+	}
+	r.snapshot = values
+
+	handled := make(map[string]bool, len(report.Changed))
+	for _, entry := range r.entries {
+		for _, key := range report.Changed {
+			if match(key, entry.prefixes) {
+				handled[key] = true
+			}
+		}
+		// 与组件已经应用的配置比较，包括上一次应用失败的变化
+		changed := make([]string, 0)
+		for _, key := range changedKeys(entry.applied, values) {
+			if match(key, entry.prefixes) {
+				changed = append(changed, key)
+			}
+		}
+		if !entry.every && len(changed) == 0 {
+			continue
+		}
+
+		if err := entry.fn(changed); err != nil {
+			report.Failed[entry.name] = err.Error()
+			log.Printf("%s🔗 -> Reload %s failed: %v %s\n", "\033[31m", entry.name, err, "\033[0m")
+			continue
+		}
+		entry.applied = values
+		report.Applied = append(report.Applied, entry.name)
+	}
+
+	for _, key := range report.Changed {
+		if !handled[key] {
+			report.Restart = append(report.Restart, key)
+		}
+	}
+	return report, nil
+}
+
+// match 判断配置键是否属于前缀，前缀按层级匹配，"http.rate_limit" 不匹配 "http.rate_limiter"
+func match(key string, prefixes []string) bool {
+	for _, prefix := range prefixes {
+		if key == prefix || strings.HasPrefix(key, prefix+".") {
+			return true
+		}
+	}
+	return false
+}
+
+// flattenJSON 把配置 JSON 展开为以点分隔的键，列表元素使用下标，如 loggers.0.log_level
+func flattenJSON(raw string) (map[string]any, error) {
+	var value any
+	if err := json.Unmarshal([]byte(raw), &value); err != nil {
+		return nil, fmt.Errorf("解析配置失败: %v", err)
+	}
+	values := make(map[string]any)
+	flattenValue("", value, values)
+	return values, nil
+}
+
+func flattenValue(prefix string, value any, values map[string]any) {
+	join := func(key string) string {
+		if prefix == "" {
+			return key
+		}
+		return prefix + "." + key
+	}
+
+	switch v := value.(type) {
+	case map[string]any:
+		if len(v) == 0 && prefix != "" {
+			values[prefix] = v
+		}
+		for key, item := range v {
+			flattenValue(join(key), item, values)
+		}
+	case []any:
+		if len(v) == 0 && prefix != "" {
+			values[prefix] = v
+		}
+		for i, item := range v {
+			flattenValue(join(strconv.Itoa(i)), item, values)
+		}
+	default:
+		values[prefix] = v
+	}
+}
+
+// changedKeys 返回新增、删除或值发生变化的配置键，按字母排序
+func changedKeys(old, current map[string]any) []string {
+	changed := make([]string, 0)
+	for key, value := range current {
+		if previous, ok := old[key]; !ok || !reflect.DeepEqual(previous, value) {
+			changed = append(changed, key)
+		}
+	}
+	for key := range old {
+		if _, ok := current[key]; !ok {
+			changed = append(changed, key)
+		}
+	}
+	sort.Strings(changed)
+	return changed
+}
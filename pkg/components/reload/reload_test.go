@@ -0,0 +1,149 @@
+package reload
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestReload(t *testing.T) {
+	next := `{"loggers":[{"name":"default","log_level":0}],"http":{"port":8080,"rate_limit":{"capacity":10}}}`
+	r := NewReloader(next, func() (string, error) { return next, nil })
+
+	var (
+		loggerChanged []string
+		rotated       int
+	)
+	r.Register("logger", func(changed []string) error {
+		loggerChanged = changed
+		return nil
+	}, "loggers")
+	r.Register("rate_limit", func(changed []string) error {
+		return errors.New("invalid capacity")
+	}, "http.rate_limit")
+	r.RegisterEvery("rotate", func(changed []string) error {
+		rotated++
+		return nil
+	})
+
+	// 日志级别与限流可以热更新，端口需要重启
+	next = `{"loggers":[{"name":"default","log_level":2}],"http":{"port":9090,"rate_limit":{"capacity":20}}}`
+	report, err := r.Reload()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected := []string{"http.port", "http.rate_limit.capacity", "loggers.0.log_level"}; !reflect.DeepEqual(report.Changed, expected) {
+		t.Errorf("Expected changed %v, got %v", expected, report.Changed)
+	}
+	if !reflect.DeepEqual(loggerChanged, []string{"loggers.0.log_level"}) {
+		t.Errorf("Unexpected logger changes: %v", loggerChanged)
+	}
+	if !reflect.DeepEqual(report.Applied, []string{"logger", "rotate"}) {
+		t.Errorf("Unexpected applied: %v", report.Applied)
+	}
+	if report.Failed["rate_limit"] != "invalid capacity" {
+		t.Errorf("Expected rate_limit failure, got %v", report.Failed)
+	}
+	if !reflect.DeepEqual(report.Restart, []string{"http.port"}) {
+		t.Errorf("Expected http.port to require restart, got %v", report.Restart)
+	}
+
+	// 配置没有变化时调用 RegisterEvery 注册的组件，并重新分发上一次应用失败的变化
+	loggerChanged = nil
+	report, err = r.Reload()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(report.Changed) != 0 || loggerChanged != nil || rotated != 2 {
+		t.Errorf("Unexpected reload without changes: %+v, logger %v, rotated %d", report, loggerChanged, rotated)
+	}
+	if report.Failed["rate_limit"] != "invalid capacity" {
+		t.Errorf("Expected rate_limit to be retried, got %v", report.Failed)
+	}
+
+	// 加载失败时保留原来的快照
+	next = `{`
+	if _, err := r.Reload(); err == nil {
+		t.Error("Expected error for invalid config")
+	}
+}
+
+func TestReloadRetry(t *testing.T) {
+	next := `{"http":{"rate_limit":{"capacity":10,"burst":5}}}`
+	r := NewReloader(next, func() (string, error) { return next, nil })
+
+	var (
+		calls [][]string
+		fail  = true
+	)
+	r.Register("rate_limit", func(changed []string) error {
+		calls = append(calls, changed)
+		if fail {
+			return errors.New("redis unavailable")
+		}
+		return nil
+	}, "http.rate_limit")
+
+	next = `{"http":{"rate_limit":{"capacity":20,"burst":5}}}`
+	if _, err := r.Reload(); err != nil {
+		t.Fatal(err)
+	}
+
+	// 上一次失败的变化与本次的变化一起重新分发
+	fail = false
+	next = `{"http":{"rate_limit":{"capacity":20,"burst":8}}}`
+	report, err := r.Reload()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(report.Changed, []string{"http.rate_limit.burst"}) {
+		t.Errorf("Expected changed [http.rate_limit.burst], got %v", report.Changed)
+	}
+	if !reflect.DeepEqual(report.Applied, []string{"rate_limit"}) || len(report.Failed) != 0 {
+		t.Errorf("Expected rate_limit applied, got %+v", report)
+	}
+	want := [][]string{{"http.rate_limit.capacity"}, {"http.rate_limit.burst", "http.rate_limit.capacity"}}
+	if !reflect.DeepEqual(calls, want) {
+		t.Errorf("Expected calls %v, got %v", want, calls)
+	}
+
+	// 应用成功后不再分发
+	if _, err := r.Reload(); err != nil {
+		t.Fatal(err)
+	}
+	if len(calls) != 2 {
+		t.Errorf("Expected no call after applied, got %v", calls[2:])
+	}
+}
+
+func TestReloadDuplicateNames(t *testing.T) {
+	next := `{"http":{"rate_limit":{"capacity":10}}}`
+	r := NewReloader(next, func() (string, error) { return next, nil })
+
+	// 同名的组件按注册顺序加上序号，失败的结果不会被覆盖
+	r.Register("rate_limit", func(changed []string) error { return errors.New("first") }, "http.rate_limit")
+	r.Register("rate_limit", func(changed []string) error { return nil }, "http.rate_limit")
+	r.Register("rate_limit", func(changed []string) error { return errors.New("third") }, "http.rate_limit")
+
+	next = `{"http":{"rate_limit":{"capacity":20}}}`
+	report, err := r.Reload()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(report.Applied, []string{"rate_limit#2"}) {
+		t.Errorf("Expected applied [rate_limit#2], got %v", report.Applied)
+	}
+	want := map[string]string{"rate_limit": "first", "rate_limit#3": "third"}
+	if !reflect.DeepEqual(report.Failed, want) {
+		t.Errorf("Expected failed %v, got %v", want, report.Failed)
+	}
+}
+
+func TestMatch(t *testing.T) {
+	if !match("http.rate_limit.basic.capacity", []string{"http.rate_limit"}) {
+		t.Error("Expected nested key to match")
+	}
+	if match("tcp.rate_limiter", []string{"tcp.rate_limit"}) {
+		t.Error("Expected sibling key not to match")
+	}
+}
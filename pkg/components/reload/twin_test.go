@@ -0,0 +1,21 @@
+package reload
+
+import (
+	"testing"
+
+	"github.com/stones-hub/taurus-pro-core/pkg/components/internal/twintest"
+)
+
+func TestMatchesTemplate(t *testing.T) {
+	twintest.Compare(t, "reload.go", twintest.Template("reload.gotmpl"), map[string]string{
+		"ReloadFunc":         "Func",
+		"ReloadReport":       "Report",
+		"reloadEntry":        "entry",
+		"ConfigReloader":     "Reloader",
+		"NewConfigReloader":  "NewReloader",
+		"reloadMatch":        "match",
+		"flattenConfigJSON":  "flattenJSON",
+		"flattenConfigValue": "flattenValue",
+		"changedConfigKeys":  "changedKeys",
+	})
+}
@@ -1,17 +1,22 @@
 package storage
 
 import (
+	"context"
 	"log"
+	"sync"
 	"time"
 
 	"github.com/stones-hub/taurus-pro-config/pkg/config"
+	"github.com/stones-hub/taurus-pro-core/pkg/components/health"
+	"github.com/stones-hub/taurus-pro-core/pkg/components/instrument"
+	"github.com/stones-hub/taurus-pro-core/pkg/components/lifecycle"
 	"github.com/stones-hub/taurus-pro-core/pkg/components/types"
 	"github.com/stones-hub/taurus-pro-storage/pkg/db"
 	"gorm.io/gorm"
 	"gorm.io/gorm/logger"
 )
 
-func ProvideDbComponent(cfg *config.Config) (map[string]*gorm.DB, func(), error) {
+func ProvideDbComponent(cfg *config.Config, hc *health.Registry, inst *instrument.Registry, lc *lifecycle.Manager) (map[string]*gorm.DB, func(), error) {
 	enable := cfg.GetBool("databases.enable")
 
 	if !enable {
@@ -73,22 +78,40 @@ func ProvideDbComponent(cfg *config.Config) (map[string]*gorm.DB, func(), error)
 		}
 	}
 
+	// 每个数据库连接注册一个健康检查，并发布到埋点注册表
+	for name, gormDB := range db.DbList() {
+		hc.Register("db:"+name, func(ctx context.Context) error {
+			sqlDB, err := gormDB.DB()
+			if err != nil {
+				return err
+			}
+			return sqlDB.PingContext(ctx)
+		})
+		inst.Publish(instrument.KindGorm, name, gormDB)
+	}
+
 	log.Printf("%s🔗 -> Database all initialized successfully. %s\n", "\033[32m", "\033[0m")
 
-	return db.DbList(), func() {
+	// 连接在关闭的 resource 阶段释放，此时服务与后台任务都已停止
+	closeDB := sync.OnceFunc(func() {
 		db.CloseDB()
 		log.Printf("%s🔗 -> Clean up database components successfully. %s\n", "\033[32m", "\033[0m")
+	})
+	lc.OnShutdown(lifecycle.PhaseResource, "db", func(ctx context.Context) error {
+		closeDB()
+		return nil
+	})
 
-	}, nil
+	return db.DbList(), closeDB, nil
 }
 
 var dbWire = &types.Wire{
-	RequirePath:  []string{"github.com/stones-hub/taurus-pro-storage/pkg/db", "gorm.io/gorm", "gorm.io/gorm/logger", "log", "time"},
+	RequirePath:  []string{"github.com/stones-hub/taurus-pro-storage/pkg/db", "gorm.io/gorm", "gorm.io/gorm/logger", "context", "log", "sync", "time"},
 	Name:         "DbList",
 	Type:         "map[string]*gorm.DB",
 	ProviderName: "ProvideDbComponent",
 	Provider: `
-	func {{.ProviderName}}(cfg *config.Config) ({{.Type}}, func(), error) {
+	func {{.ProviderName}}(cfg *config.Config, hc *HealthRegistry, inst *Instrumentation, lc *LifecycleManager) ({{.Type}}, func(), error) {
 
 		enable := cfg.GetBool("databases.enable")
 
@@ -151,14 +174,78 @@ var dbWire = &types.Wire{
 		}
 	}
 
+	// 每个数据库连接注册一个健康检查，并发布到埋点注册表
+	for name, gormDB := range db.DbList() {
+		hc.Register("db:"+name, func(ctx context.Context) error {
+			sqlDB, err := gormDB.DB()
+			if err != nil {
+				return err
+			}
+			return sqlDB.PingContext(ctx)
+		})
+		inst.Publish(InstrumentKindGorm, name, gormDB)
+	}
+
 	log.Printf("%s🔗 -> Database all initialized successfully. %s\n", "\033[32m", "\033[0m")
 
-	return db.DbList(), func() {
+	// 连接在关闭的 resource 阶段释放，此时服务与后台任务都已停止
+	closeDB := sync.OnceFunc(func() {
 		db.CloseDB()
 		log.Printf("%s🔗 -> Clean up database components successfully. %s\n", "\033[32m", "\033[0m")
+	})
+	lc.OnShutdown(LifecyclePhaseResource, "db", func(ctx context.Context) error {
+		closeDB()
+		return nil
+	})
 
-	}, nil
+	return db.DbList(), closeDB, nil
 	
 }
 `,
+	TestRequirePath:  []string{"context", "gorm.io/driver/sqlite", "gorm.io/gorm", "gorm.io/gorm/logger"},
+	TestProviderName: "ProvideTestDbComponent",
+	TestProvider: `// ProvideTestDbComponent 为每个数据库创建独立的内存 sqlite，名称来自 Options.Databases 或 databases.list，
+// 表结构需要在测试中通过 AutoMigrate 创建（如 model.Migrate）
+func {{.TestProviderName}}(t testing.TB, c *taurus.Components, h *Harness) (map[string]*gorm.DB, error) {
+	if !c.Config.GetBool("databases.enable") {
+		return nil, nil
+	}
+
+	names := h.opts.Databases
+	if len(names) == 0 {
+		rawList, _ := c.Config.Get("databases.list").([]interface{})
+		for _, raw := range rawList {
+			if dbOptions, ok := raw.(map[string]interface{}); ok {
+				if name, ok := dbOptions["dbname"].(string); ok {
+					names = append(names, name)
+				}
+			}
+		}
+	}
+	if len(names) == 0 {
+		names = []string{"default"}
+	}
+
+	dbList := make(map[string]*gorm.DB, len(names))
+	for _, name := range names {
+		gormDB, err := gorm.Open(sqlite.Open("file::memory:"), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+		if err != nil {
+			return nil, err
+		}
+		// 内存数据库每个连接相互独立，限制为单连接保证数据可见
+		sqlDB, err := gormDB.DB()
+		if err != nil {
+			return nil, err
+		}
+		sqlDB.SetMaxOpenConns(1)
+		t.Cleanup(func() { sqlDB.Close() })
+
+		c.Health.Register("db:"+name, func(ctx context.Context) error {
+			return sqlDB.PingContext(ctx)
+		})
+		c.Instrument.Publish(taurus.InstrumentKindGorm, name, gormDB)
+		dbList[name] = gormDB
+	}
+	return dbList, nil
+}`,
 }
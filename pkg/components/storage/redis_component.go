@@ -1,15 +1,20 @@
 package storage
 
 import (
+	"context"
 	"log"
+	"sync"
 	"time"
 
 	"github.com/stones-hub/taurus-pro-config/pkg/config"
+	"github.com/stones-hub/taurus-pro-core/pkg/components/health"
+	"github.com/stones-hub/taurus-pro-core/pkg/components/instrument"
+	"github.com/stones-hub/taurus-pro-core/pkg/components/lifecycle"
 	"github.com/stones-hub/taurus-pro-core/pkg/components/types"
 	"github.com/stones-hub/taurus-pro-storage/pkg/redisx"
 )
 
-func ProvideRedisComponent(cfg *config.Config) (*redisx.RedisClient, func(), error) {
+func ProvideRedisComponent(cfg *config.Config, hc *health.Registry, inst *instrument.Registry, lc *lifecycle.Manager) (*redisx.RedisClient, func(), error) {
 
 	enable := cfg.GetBool("redis.enable")
 	if !enable {
@@ -74,20 +79,32 @@ func ProvideRedisComponent(cfg *config.Config) (*redisx.RedisClient, func(), err
 		return nil, func() {}, err
 	}
 
+	hc.Register("redis", func(ctx context.Context) error {
+		return redisx.Redis.GetClient().Ping(ctx).Err()
+	})
+	inst.Publish(instrument.KindRedis, "default", redisx.Redis.GetClient())
+
 	log.Printf("%s🔗 -> Redis all initialized successfully. %s\n", "\033[32m", "\033[0m")
 
-	return redisx.Redis, func() {
+	// 连接在关闭的 resource 阶段释放，此时服务与后台任务都已停止
+	closeRedis := sync.OnceFunc(func() {
 		redisx.Redis.Close()
 		log.Printf("%s🔗 -> Clean up redis components successfully. %s\n", "\033[32m", "\033[0m")
-	}, nil
+	})
+	lc.OnShutdown(lifecycle.PhaseResource, "redis", func(ctx context.Context) error {
+		closeRedis()
+		return nil
+	})
+
+	return redisx.Redis, closeRedis, nil
 }
 
 var redisWire = &types.Wire{
-	RequirePath:  []string{"github.com/stones-hub/taurus-pro-storage/pkg/redisx", "log", "time"},
+	RequirePath:  []string{"github.com/stones-hub/taurus-pro-storage/pkg/redisx", "context", "log", "sync", "time"},
 	Name:         "Redis",
 	Type:         "*redisx.RedisClient",
 	ProviderName: "ProvideRedisComponent",
-	Provider: `func {{.ProviderName}}(cfg *config.Config) ({{.Type}}, func(), error) {
+	Provider: `func {{.ProviderName}}(cfg *config.Config, hc *HealthRegistry, inst *Instrumentation, lc *LifecycleManager) ({{.Type}}, func(), error) {
 
 		enable := cfg.GetBool("redis.enable")
 	if !enable {
@@ -152,12 +169,45 @@ var redisWire = &types.Wire{
 		return nil, func() {}, err
 	}
 
+	hc.Register("redis", func(ctx context.Context) error {
+		return redisx.Redis.GetClient().Ping(ctx).Err()
+	})
+	inst.Publish(InstrumentKindRedis, "default", redisx.Redis.GetClient())
+
 	log.Printf("%s🔗 -> Redis all initialized successfully. %s\n", "\033[32m", "\033[0m")
 
-	return redisx.Redis, func() {
+	// 连接在关闭的 resource 阶段释放，此时服务与后台任务都已停止
+	closeRedis := sync.OnceFunc(func() {
 		redisx.Redis.Close()
 		log.Printf("%s🔗 -> Clean up redis components successfully. %s\n", "\033[32m", "\033[0m")
-	}, nil
+	})
+	lc.OnShutdown(LifecyclePhaseResource, "redis", func(ctx context.Context) error {
+		closeRedis()
+		return nil
+	})
+
+	return redisx.Redis, closeRedis, nil
 	
+}`,
+	TestRequirePath:  []string{"context", "github.com/alicebob/miniredis/v2", "github.com/stones-hub/taurus-pro-storage/pkg/redisx"},
+	TestProviderName: "ProvideTestRedisComponent",
+	TestProvider: `// ProvideTestRedisComponent 启动内嵌的 miniredis 并把 redisx 客户端连接到它，
+// 测试中可以通过 h.Miniredis 检查数据或推进过期时间（FastForward）
+func {{.TestProviderName}}(t testing.TB, c *taurus.Components, h *Harness) (*redisx.RedisClient, error) {
+	if !c.Config.GetBool("redis.enable") {
+		return nil, nil
+	}
+
+	h.Miniredis = miniredis.RunT(t)
+	if err := redisx.InitRedis(redisx.WithAddrs(h.Miniredis.Addr())); err != nil {
+		return nil, err
+	}
+	t.Cleanup(func() { redisx.Redis.Close() })
+
+	c.Health.Register("redis", func(ctx context.Context) error {
+		return redisx.Redis.GetClient().Ping(ctx).Err()
+	})
+	c.Instrument.Publish(taurus.InstrumentKindRedis, "default", redisx.Redis.GetClient())
+	return redisx.Redis, nil
 }`,
 }
@@ -1,10 +1,13 @@
 package tcp
 
 import (
+	"context"
 	"log"
+	"sync"
 	"time"
 
 	"github.com/stones-hub/taurus-pro-config/pkg/config"
+	"github.com/stones-hub/taurus-pro-core/pkg/components/lifecycle"
 	"github.com/stones-hub/taurus-pro-core/pkg/components/types"
 	TCPServer "github.com/stones-hub/taurus-pro-tcp/pkg/tcp"
 	"github.com/stones-hub/taurus-pro-tcp/pkg/tcp/protocol"
@@ -22,11 +25,11 @@ var TcpComponent = types.Component{
 }
 
 var tcpWire = &types.Wire{
-	RequirePath:  []string{"TCPServer@github.com/stones-hub/taurus-pro-tcp/pkg/tcp", "github.com/stones-hub/taurus-pro-tcp/pkg/tcp/protocol"},
+	RequirePath:  []string{"TCPServer@github.com/stones-hub/taurus-pro-tcp/pkg/tcp", "github.com/stones-hub/taurus-pro-tcp/pkg/tcp/protocol", "context", "log", "sync", "time"},
 	Name:         "TCPServer",
 	Type:         "*TCPServer.Server",
 	ProviderName: "ProvideTcpComponent",
-	Provider: `func {{.ProviderName}}(cfg *config.Config) ({{.Type}}, func(), error) {
+	Provider: `func {{.ProviderName}}(cfg *config.Config, lc *LifecycleManager) ({{.Type}}, func(), error) {
 	enable := cfg.GetBool("tcp.enable")
 	if !enable {
 		return nil, func() {}, nil
@@ -53,26 +56,31 @@ var tcpWire = &types.Wire{
 		return nil, func() {}, err
 	}
 
-	go func() {
-		log.Printf("%s🔗 -> Tcp server start on %s. %s\n", "\033[32m", cfg.GetString("tcp.address"), "\033[0m")
-		err := server.Start()
-		if err != nil {
-			log.Printf("%s🔗 -> Tcp server start failed. %s\n", "\033[31m", "\033[0m")
-			return
-		}
-	}()
+	// tcp 服务由生命周期管理器统一启动与停止，运行错误汇总到统一的错误通道
+	stop := sync.OnceFunc(func() {
+		cleanup()
+		log.Printf("%s🔗 -> Clean up tcp components successfully. %s\n", "\033[32m", "\033[0m")
+	})
+	lc.Register("tcp", &LifecycleHook{
+		OnStart: func(ctx context.Context) error {
+			log.Printf("%s🔗 -> Tcp server start on %s. %s\n", "\033[32m", cfg.GetString("tcp.address"), "\033[0m")
+			lc.Go("tcp", server.Start)
+			return nil
+		},
+		OnStop: func(ctx context.Context) error {
+			stop()
+			return nil
+		},
+	})
 
 	log.Printf("%s🔗 -> Tcp all initialized successfully. %s\n", "\033[32m", "\033[0m")
 
-	return server, func() {
-		cleanup()
-		log.Printf("%s🔗 -> Clean up tcp components successfully. %s\n", "\033[32m", "\033[0m")
-	}, nil
+	return server, stop, nil
 }
 `,
 }
 
-func ProvideTcpComponent(cfg *config.Config) (*TCPServer.Server, func(), error) {
+func ProvideTcpComponent(cfg *config.Config, lc *lifecycle.Manager) (*TCPServer.Server, func(), error) {
 	enable := cfg.GetBool("tcp.enable")
 	if !enable {
 		return nil, func() {}, nil
@@ -99,19 +107,24 @@ func ProvideTcpComponent(cfg *config.Config) (*TCPServer.Server, func(), error)
 		return nil, func() {}, err
 	}
 
-	go func() {
-		log.Printf("%s🔗 -> Tcp server start on %s. %s\n", "\033[32m", cfg.GetString("tcp.address"), "\033[0m")
-		err := server.Start()
-		if err != nil {
-			log.Printf("%s🔗 -> Tcp server start failed. %s\n", "\033[31m", "\033[0m")
-			return
-		}
-	}()
+	// tcp 服务由生命周期管理器统一启动与停止，运行错误汇总到统一的错误通道
+	stop := sync.OnceFunc(func() {
+		cleanup()
+		log.Printf("%s🔗 -> Clean up tcp components successfully. %s\n", "\033[32m", "\033[0m")
+	})
+	lc.Register("tcp", &lifecycle.Hook{
+		OnStart: func(ctx context.Context) error {
+			log.Printf("%s🔗 -> Tcp server start on %s. %s\n", "\033[32m", cfg.GetString("tcp.address"), "\033[0m")
+			lc.Go("tcp", server.Start)
+			return nil
+		},
+		OnStop: func(ctx context.Context) error {
+			stop()
+			return nil
+		},
+	})
 
 	log.Printf("%s🔗 -> Tcp all initialized successfully. %s\n", "\033[32m", "\033[0m")
 
-	return server, func() {
-		cleanup()
-		log.Printf("%s🔗 -> Clean up tcp components successfully. %s\n", "\033[32m", "\033[0m")
-	}, nil
+	return server, stop, nil
 }
@@ -0,0 +1,178 @@
+// Package tlscert 是生成项目中 internal/taurus/tls.go 的对应实现
+package tlscert
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+)
+
+// Options TLS 配置
+type Options struct {
+	CertFile       string        // 证书文件(PEM)，可以包含中间证书
+	KeyFile        string        // 私钥文件(PEM)
+	ClientCAFile   string        // 校验客户端证书的 CA(PEM)，配置后要求客户端提供证书(mTLS)
+	MinVersion     string        // 最低 TLS 版本: 1.0、1.1、1.2、1.3，默认 1.2
+	ReloadInterval time.Duration // 检查证书文件是否更新的间隔，默认 1 分钟，小于 0 时不检查
+}
+
+// CertReloader 从磁盘加载证书与客户端 CA，文件更新后（如内部 CA 轮换证书）在下一次握手时加载，不需要重启服务
+// 新证书加载失败时继续使用原来的证书
+type CertReloader struct {
+	certFile     string
+	keyFile      string
+	clientCAFile string // 为空时不加载客户端 CA
+	interval     time.Duration
+
+	mu        sync.RWMutex
+	cert      *tls.Certificate
+	clientCAs *x509.CertPool
+	modTime   time.Time // 证书、私钥与客户端 CA 文件中最新的修改时间
+	checked   time.Time // 上一次检查文件的时间
+}
+
+// NewCertReloader 加载证书与客户端 CA，证书无效时返回错误
+func NewCertReloader(certFile, keyFile, clientCAFile string, interval time.Duration) (*CertReloader, error) {
+	if interval == 0 {
+		interval = time.Minute
+	}
+	r := &CertReloader{certFile: certFile, keyFile: keyFile, clientCAFile: clientCAFile, interval: interval}
+	if err := r.Reload(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// Reload 重新加载证书与客户端 CA，任意一个失败时继续使用原来的证书与客户端 CA
+func (r *CertReloader) Reload() error {
+	modTime, err := r.latestModTime()
+	if err != nil {
+		return err
+	}
+	cert, err := tls.LoadX509KeyPair(r.certFile, r.keyFile)
+	if err != nil {
+		return fmt.Errorf("加载证书失败: %v", err)
+	}
+	var pool *x509.CertPool
+	if r.clientCAFile != "" {
+		caCert, err := os.ReadFile(r.clientCAFile)
+		if err != nil {
+			return fmt.Errorf("读取客户端 CA 证书失败: %v", err)
+		}
+		pool = x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(caCert) {
+			return fmt.Errorf("客户端 CA 证书无效: %s", r.clientCAFile)
+		}
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.cert = &cert
+	r.clientCAs = pool
+	r.modTime = modTime
+	r.checked = time.Now()
+	return nil
+}
+
+// GetCertificate 用于 tls.Config.GetCertificate，返回当前的证书
+func (r *CertReloader) GetCertificate(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+	r.refresh()
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.cert, nil
+}
+
+// ClientCAs 返回当前的客户端 CA，没有配置客户端 CA 时返回 nil
+func (r *CertReloader) ClientCAs() *x509.CertPool {
+	r.refresh()
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.clientCAs
+}
+
+// refresh 距离上一次检查超过间隔时比较文件的修改时间，有更新则重新加载
+func (r *CertReloader) refresh() {
+	r.mu.RLock()
+	due := r.interval > 0 && time.Since(r.checked) >= r.interval
+	r.mu.RUnlock()
+	if !due {
+		return
+	}
+
+	r.mu.Lock()
+	r.checked = time.Now()
+	previous := r.modTime
+	r.mu.Unlock()
+
+	// 证书与私钥可能先后写入，所有文件都能读取并且匹配时才替换
+	if modTime, err := r.latestModTime(); err == nil && modTime.After(previous) {
+		_ = r.Reload()
+	}
+}
+
+// latestModTime 返回证书、私钥与客户端 CA 文件中最新的修改时间
+func (r *CertReloader) latestModTime() (time.Time, error) {
+	files := []string{r.certFile, r.keyFile}
+	if r.clientCAFile != "" {
+		files = append(files, r.clientCAFile)
+	}
+	var latest time.Time
+	for _, file := range files {
+		info, err := os.Stat(file)
+		if err != nil {
+			return time.Time{}, fmt.Errorf("读取证书文件失败: %v", err)
+		}
+		if info.ModTime().After(latest) {
+			latest = info.ModTime()
+		}
+	}
+	return latest, nil
+}
+
+// NewConfig 按配置创建服务端的 tls.Config，证书通过 CertReloader 加载，返回的 CertReloader 可以在收到 SIGHUP 时立即重新加载
+func NewConfig(opts *Options) (*tls.Config, *CertReloader, error) {
+	minVersion, err := ParseVersion(opts.MinVersion)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	reloader, err := NewCertReloader(opts.CertFile, opts.KeyFile, opts.ClientCAFile, opts.ReloadInterval)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	config := &tls.Config{
+		MinVersion:     minVersion,
+		GetCertificate: reloader.GetCertificate,
+	}
+
+	if opts.ClientCAFile != "" {
+		config.ClientCAs = reloader.ClientCAs()
+		config.ClientAuth = tls.RequireAndVerifyClientCert
+		// 每次握手使用当前的客户端 CA，内部 CA 轮换后不需要重启服务
+		config.GetConfigForClient = func(*tls.ClientHelloInfo) (*tls.Config, error) {
+			c := config.Clone()
+			c.ClientCAs = reloader.ClientCAs()
+			return c, nil
+		}
+	}
+	return config, reloader, nil
+}
+
+// ParseVersion 解析 TLS 版本，为空时返回 TLS 1.2
+func ParseVersion(version string) (uint16, error) {
+	switch version {
+	case "", "1.2":
+		return tls.VersionTLS12, nil
+	case "1.3":
+		return tls.VersionTLS13, nil
+	case "1.1":
+		return tls.VersionTLS11, nil
+	case "1.0":
+		return tls.VersionTLS10, nil
+	}
+	return 0, fmt.Errorf("不支持的 TLS 版本: %s", version)
+}
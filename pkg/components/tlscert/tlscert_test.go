@@ -0,0 +1,196 @@
+package tlscert
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"io"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// writeCert 生成自签名证书写入 certFile 与 keyFile，并把修改时间设置为 modTime
+func writeCert(t *testing.T, certFile, keyFile, name string, modTime time.Time) {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(time.Now().UnixNano()),
+		Subject:      pkix.Name{CommonName: name},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		IsCA:         true,
+		KeyUsage:     x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := os.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}), 0600); err != nil {
+		t.Fatal(err)
+	}
+	for _, file := range []string{certFile, keyFile} {
+		if err := os.Chtimes(file, modTime, modTime); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func commonName(t *testing.T, cert *tls.Certificate) string {
+	t.Helper()
+	parsed, err := x509.ParseCertificate(cert.Certificate[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	return parsed.Subject.CommonName
+}
+
+func TestCertReload(t *testing.T) {
+	dir := t.TempDir()
+	certFile, keyFile := filepath.Join(dir, "server.crt"), filepath.Join(dir, "server.key")
+	now := time.Now()
+	writeCert(t, certFile, keyFile, "first", now.Add(-time.Minute))
+
+	config, _, err := NewConfig(&Options{CertFile: certFile, KeyFile: keyFile, MinVersion: "1.3", ReloadInterval: time.Nanosecond})
+	if err != nil {
+		t.Fatalf("NewConfig failed: %v", err)
+	}
+	if config.MinVersion != tls.VersionTLS13 {
+		t.Errorf("Expected TLS 1.3, got %x", config.MinVersion)
+	}
+	if config.ClientAuth != tls.NoClientCert {
+		t.Errorf("Expected no client auth without client CA, got %v", config.ClientAuth)
+	}
+
+	cert, err := config.GetCertificate(nil)
+	if err != nil || commonName(t, cert) != "first" {
+		t.Fatalf("Expected first certificate, got %v", err)
+	}
+
+	// 轮换后的证书在下一次握手时生效
+	writeCert(t, certFile, keyFile, "second", now)
+	if cert, _ := config.GetCertificate(nil); commonName(t, cert) != "second" {
+		t.Errorf("Expected rotated certificate, got %s", commonName(t, cert))
+	}
+
+	// 写入无效的证书时继续使用原来的证书
+	if err := os.WriteFile(certFile, []byte("invalid"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chtimes(certFile, now.Add(time.Minute), now.Add(time.Minute)); err != nil {
+		t.Fatal(err)
+	}
+	if cert, _ := config.GetCertificate(nil); commonName(t, cert) != "second" {
+		t.Errorf("Expected previous certificate after invalid rotation, got %s", commonName(t, cert))
+	}
+}
+
+func TestClientCA(t *testing.T) {
+	dir := t.TempDir()
+	certFile, keyFile := filepath.Join(dir, "server.crt"), filepath.Join(dir, "server.key")
+	caFile, caKey := filepath.Join(dir, "ca.crt"), filepath.Join(dir, "ca.key")
+	writeCert(t, certFile, keyFile, "server", time.Now())
+	writeCert(t, caFile, caKey, "ca", time.Now())
+
+	config, _, err := NewConfig(&Options{CertFile: certFile, KeyFile: keyFile, ClientCAFile: caFile})
+	if err != nil {
+		t.Fatalf("NewConfig failed: %v", err)
+	}
+	if config.ClientAuth != tls.RequireAndVerifyClientCert || config.ClientCAs == nil {
+		t.Errorf("Expected mTLS with client CA, got %v", config.ClientAuth)
+	}
+	if config.MinVersion != tls.VersionTLS12 {
+		t.Errorf("Expected default TLS 1.2, got %x", config.MinVersion)
+	}
+
+	if _, _, err := NewConfig(&Options{CertFile: certFile, KeyFile: keyFile, MinVersion: "2.0"}); err == nil {
+		t.Error("Expected error for unsupported TLS version")
+	}
+}
+
+// handshake 客户端使用证书 cert 与服务端 config 握手，返回服务端握手的错误
+func handshake(t *testing.T, config *tls.Config, cert tls.Certificate) error {
+	t.Helper()
+	clientConn, serverConn := net.Pipe()
+	defer clientConn.Close()
+	defer serverConn.Close()
+	deadline := time.Now().Add(5 * time.Second)
+	_ = clientConn.SetDeadline(deadline)
+	_ = serverConn.SetDeadline(deadline)
+
+	go func() {
+		client := tls.Client(clientConn, &tls.Config{InsecureSkipVerify: true, Certificates: []tls.Certificate{cert}})
+		if client.Handshake() == nil {
+			// 读取服务端的告警与会话票据，避免服务端写入时阻塞
+			_, _ = io.Copy(io.Discard, client)
+		}
+		clientConn.Close()
+	}()
+	return tls.Server(serverConn, config).Handshake()
+}
+
+func TestClientCAReload(t *testing.T) {
+	dir := t.TempDir()
+	certFile, keyFile := filepath.Join(dir, "server.crt"), filepath.Join(dir, "server.key")
+	caFile, caKey := filepath.Join(dir, "ca.crt"), filepath.Join(dir, "ca.key")
+	now := time.Now()
+	writeCert(t, certFile, keyFile, "server", now.Add(-time.Minute))
+
+	// 客户端证书为自签名证书，CA 文件即客户端证书
+	writeCert(t, caFile, caKey, "first", now.Add(-time.Minute))
+	first, err := tls.LoadX509KeyPair(caFile, caKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	config, _, err := NewConfig(&Options{CertFile: certFile, KeyFile: keyFile, ClientCAFile: caFile, ReloadInterval: time.Nanosecond})
+	if err != nil {
+		t.Fatalf("NewConfig failed: %v", err)
+	}
+	if err := handshake(t, config, first); err != nil {
+		t.Errorf("Expected first client accepted, got %v", err)
+	}
+
+	// 轮换客户端 CA 后，新 CA 签发的客户端证书在下一次握手时通过，旧的被拒绝
+	writeCert(t, caFile, caKey, "second", now)
+	second, err := tls.LoadX509KeyPair(caFile, caKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := handshake(t, config, second); err != nil {
+		t.Errorf("Expected second client accepted after rotation, got %v", err)
+	}
+	if err := handshake(t, config, first); err == nil {
+		t.Errorf("Expected first client rejected after rotation")
+	}
+
+	// 写入无效的 CA 时继续使用原来的 CA
+	if err := os.WriteFile(caFile, []byte("invalid"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chtimes(caFile, now.Add(time.Minute), now.Add(time.Minute)); err != nil {
+		t.Fatal(err)
+	}
+	if err := handshake(t, config, second); err != nil {
+		t.Errorf("Expected previous client CA after invalid rotation, got %v", err)
+	}
+}
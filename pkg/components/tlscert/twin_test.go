@@ -0,0 +1,15 @@
+package tlscert
+
+import (
+	"testing"
+
+	"github.com/stones-hub/taurus-pro-core/pkg/components/internal/twintest"
+)
+
+func TestMatchesTemplate(t *testing.T) {
+	twintest.Compare(t, "tlscert.go", twintest.Template("tls.gotmpl"), map[string]string{
+		"TLSOptions":      "Options",
+		"NewTLSConfig":    "NewConfig",
+		"ParseTLSVersion": "ParseVersion",
+	})
+}
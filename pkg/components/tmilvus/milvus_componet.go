@@ -1,11 +1,16 @@
 package tmilvus
 
 import (
+	"context"
 	"log"
 	"math"
+	"sync"
 	"time"
 
+	"github.com/milvus-io/milvus/client/v2/milvusclient"
 	"github.com/stones-hub/taurus-pro-config/pkg/config"
+	"github.com/stones-hub/taurus-pro-core/pkg/components/health"
+	"github.com/stones-hub/taurus-pro-core/pkg/components/lifecycle"
 	"github.com/stones-hub/taurus-pro-core/pkg/components/types"
 	"github.com/stones-hub/taurus-pro-milvus/pkg/milvus"
 	mclient "github.com/stones-hub/taurus-pro-milvus/pkg/milvus/client"
@@ -23,11 +28,11 @@ var MilvusComponent = types.Component{
 }
 
 var milvusWire = &types.Wire{
-	RequirePath:  []string{"github.com/stones-hub/taurus-pro-milvus/pkg/milvus", "mclient@github.com/stones-hub/taurus-pro-milvus/pkg/milvus/client", "log", "math", "time"},
+	RequirePath:  []string{"github.com/stones-hub/taurus-pro-milvus/pkg/milvus", "mclient@github.com/stones-hub/taurus-pro-milvus/pkg/milvus/client", "github.com/milvus-io/milvus/client/v2/milvusclient", "context", "log", "math", "sync", "time"},
 	Name:         "Milvus",
 	Type:         "milvus.Pool",
 	ProviderName: "ProvideMilvusComponent",
-	Provider: `func {{.ProviderName}}(cfg *config.Config) ({{.Type}}, func(), error) {
+	Provider: `func {{.ProviderName}}(cfg *config.Config, hc *HealthRegistry, lc *LifecycleManager) ({{.Type}}, func(), error) {
 	// 检查是否启用 Milvus
 	if !cfg.GetBool("milvus.enable") {
 		return nil, func() {}, nil
@@ -169,17 +174,36 @@ var milvusWire = &types.Wire{
 		}
 	}
 
+	// 连接池中的每个客户端注册一个健康检查
+	for _, name := range pool.List() {
+		hc.Register("milvus:"+name, func(ctx context.Context) error {
+			client, err := pool.Get(name)
+			if err != nil {
+				return err
+			}
+			_, err = client.GetClient().GetServerVersion(ctx, milvusclient.NewGetServerVersionOption())
+			return err
+		})
+	}
+
 	log.Printf("%s🔗 -> Milvus all initialized successfully. %s\n", "\033[32m", "\033[0m")
 
-	// 返回连接池和清理函数
-	return pool, func() {
+	// 连接池在关闭的 resource 阶段释放，wire 的清理函数不再重复关闭
+	closePool := sync.OnceFunc(func() {
 		pool.Close()
 		log.Printf("%s🔗 -> Clean up milvus components successfully. %s\n", "\033[32m", "\033[0m")
-	}, nil
+	})
+	lc.OnShutdown(LifecyclePhaseResource, "milvus", func(ctx context.Context) error {
+		closePool()
+		return nil
+	})
+
+	// 返回连接池和清理函数
+	return pool, closePool, nil
 }`,
 }
 
-func ProvideMilvusComponent(cfg *config.Config) (milvus.Pool, func(), error) {
+func ProvideMilvusComponent(cfg *config.Config, hc *health.Registry, lc *lifecycle.Manager) (milvus.Pool, func(), error) {
 	// 检查是否启用 Milvus
 	if !cfg.GetBool("milvus.enable") {
 		return nil, func() {}, nil
@@ -321,11 +345,30 @@ func ProvideMilvusComponent(cfg *config.Config) (milvus.Pool, func(), error) {
 		}
 	}
 
+	// 连接池中的每个客户端注册一个健康检查
+	for _, name := range pool.List() {
+		hc.Register("milvus:"+name, func(ctx context.Context) error {
+			client, err := pool.Get(name)
+			if err != nil {
+				return err
+			}
+			_, err = client.GetClient().GetServerVersion(ctx, milvusclient.NewGetServerVersionOption())
+			return err
+		})
+	}
+
 	log.Printf("%s🔗 -> Milvus all initialized successfully. %s\n", "\033[32m", "\033[0m")
 
-	// 返回连接池和清理函数
-	return pool, func() {
+	// 连接池在关闭的 resource 阶段释放，wire 的清理函数不再重复关闭
+	closePool := sync.OnceFunc(func() {
 		pool.Close()
 		log.Printf("%s🔗 -> Clean up milvus components successfully. %s\n", "\033[32m", "\033[0m")
-	}, nil
+	})
+	lc.OnShutdown(lifecycle.PhaseResource, "milvus", func(ctx context.Context) error {
+		closePool()
+		return nil
+	})
+
+	// 返回连接池和清理函数
+	return pool, closePool, nil
 }
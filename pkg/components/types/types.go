@@ -6,6 +6,10 @@ type Wire struct {
 	Type         string   // 组件类型
 	ProviderName string   // 提供者名称
 	Provider     string   // 提供者函数，如 func ProvideHttpComponent(cfg *config.Config) (*server.Server, error)
+
+	TestRequirePath  []string // 测试实现依赖的包路径
+	TestProviderName string   // 测试实现的名称，为空时 taurustest 构建的组件中该字段为 nil
+	TestProvider     string   // 测试实现，生成到 internal/taurustest，如 func ProvideTestRedisComponent(t testing.TB, c *taurus.Components, h *Harness) (*redisx.RedisClient, error)
 }
 
 // Component 表示一个组件
@@ -0,0 +1,225 @@
+package dev
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/stones-hub/taurus-pro-core/pkg/project"
+)
+
+// Options 开发模式配置
+type Options struct {
+	ProjectRoot string        // 项目根目录
+	MainPath    string        // 服务入口，默认 ./bin/taurus.go
+	Env         string        // 传递给服务的环境变量文件
+	ConfigPath  string        // 传递给服务的配置目录
+	Interval    time.Duration // 文件变化轮询间隔
+	StopTimeout time.Duration // 等待服务优雅关闭的最长时间
+	Args        []string      // 透传给服务的额外参数
+}
+
+// watchDirs 需要监听的项目目录
+var watchDirs = []string{"app", "bin", "internal", "pkg", "config"}
+
+// Run 启动开发模式：监听项目文件变化，自动重新生成 wire、编译并重启服务
+// 新版本编译成功之前，旧的服务进程会一直保持运行
+func Run(ctx context.Context, opts Options) error {
+	root, err := filepath.Abs(opts.ProjectRoot)
+	if err != nil {
+		return fmt.Errorf("获取项目绝对路径失败: %v", err)
+	}
+	if opts.MainPath == "" {
+		opts.MainPath = "./bin/taurus.go"
+	}
+	if opts.Interval <= 0 {
+		opts.Interval = 500 * time.Millisecond
+	}
+	if opts.StopTimeout <= 0 {
+		opts.StopTimeout = 15 * time.Second
+	}
+
+	appPath := filepath.Join(root, "app")
+	binPath := filepath.Join(root, "build", "dev", filepath.Base(root))
+	nextPath := binPath + ".next"
+
+	args := make([]string, 0, len(opts.Args)+4)
+	if opts.Env != "" {
+		args = append(args, "--env", opts.Env)
+	}
+	if opts.ConfigPath != "" {
+		args = append(args, "--config", opts.ConfigPath)
+	}
+	args = append(args, opts.Args...)
+
+	dirs := make([]string, 0, len(watchDirs))
+	for _, dir := range watchDirs {
+		dirs = append(dirs, filepath.Join(root, dir))
+	}
+	extraFiles := make([]string, 0, 1)
+	if opts.Env != "" {
+		extraFiles = append(extraFiles, filepath.Join(root, opts.Env))
+	}
+
+	d := &devServer{
+		root:     root,
+		appPath:  appPath,
+		mainPath: opts.MainPath,
+		binPath:  binPath,
+		nextPath: nextPath,
+		args:     args,
+		opts:     opts,
+	}
+
+	// 启动时总是重新生成一次 wire，保证注入器与代码一致
+	d.signature, _ = wireSignature(appPath)
+	d.reload(true)
+
+	last, err := takeSnapshot(dirs, extraFiles)
+	if err != nil {
+		return fmt.Errorf("扫描项目文件失败: %v", err)
+	}
+
+	log.Printf("%s🔗 -> Watching %s for changes... %s\n", blue, strings.Join(watchDirs, ", "), reset)
+
+	ticker := time.NewTicker(opts.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			d.proc.stop(opts.StopTimeout)
+			return nil
+		case <-ticker.C:
+		}
+
+		current, err := takeSnapshot(dirs, extraFiles)
+		if err != nil {
+			log.Printf("%s🔗 -> Scan project files failed: %v %s\n", red, err, reset)
+			continue
+		}
+
+		changed := last.diff(current)
+		if len(changed) == 0 {
+			continue
+		}
+
+		// 等待文件稳定，避免编辑器分多次写入时重复构建
+		current = d.settle(ctx, dirs, extraFiles, current)
+		changed = last.diff(current)
+
+		for _, path := range changed {
+			rel, _ := filepath.Rel(root, path)
+			log.Printf("%s🔗 -> Changed: %s %s\n", yellow, rel, reset)
+		}
+
+		d.reload(d.providerChanged(changed))
+
+		// 重新生成的 wire 文件不应再次触发构建
+		if last, err = takeSnapshot(dirs, extraFiles); err != nil {
+			log.Printf("%s🔗 -> Scan project files failed: %v %s\n", red, err, reset)
+		}
+	}
+}
+
+// devServer 开发模式下的构建与进程状态
+type devServer struct {
+	root      string
+	appPath   string
+	mainPath  string
+	binPath   string
+	nextPath  string
+	args      []string
+	opts      Options
+	signature string
+	proc      *process
+}
+
+// settle 等待连续两次快照一致后返回最新快照
+func (d *devServer) settle(ctx context.Context, dirs, extraFiles []string, current snapshot) snapshot {
+	for {
+		select {
+		case <-ctx.Done():
+			return current
+		case <-time.After(d.opts.Interval):
+		}
+
+		next, err := takeSnapshot(dirs, extraFiles)
+		if err != nil || len(current.diff(next)) == 0 {
+			return current
+		}
+		current = next
+	}
+}
+
+// providerChanged 判断变化的文件是否改变了 provider set 或构造函数签名
+func (d *devServer) providerChanged(changed []string) bool {
+	appChanged := false
+	for _, path := range changed {
+		if strings.HasPrefix(path, d.appPath+string(filepath.Separator)) &&
+			strings.HasSuffix(path, ".go") && !isGenerated(path) {
+			appChanged = true
+			break
+		}
+	}
+	if !appChanged {
+		return false
+	}
+
+	signature, err := wireSignature(d.appPath)
+	if err != nil {
+		// 语法错误交给编译阶段输出
+		return false
+	}
+	if signature == d.signature {
+		return false
+	}
+	d.signature = signature
+	return true
+}
+
+// reload 按需重新生成 wire，编译成功后替换正在运行的服务
+func (d *devServer) reload(regenerate bool) {
+	if regenerate {
+		log.Printf("%s🔗 -> Provider sets changed, regenerating wire... %s\n", blue, reset)
+		if err := project.RefreshProjectWire(d.appPath); err != nil {
+			d.failed("Wire generation failed", err)
+			return
+		}
+		log.Printf("%s🔗 -> Wire regenerated successfully. %s\n", green, reset)
+	}
+
+	log.Printf("%s🔗 -> Building %s... %s\n", blue, d.mainPath, reset)
+	started := time.Now()
+	if err := build(d.root, d.mainPath, d.nextPath); err != nil {
+		d.failed("Build failed", err)
+		return
+	}
+	log.Printf("%s🔗 -> Build complete in %s. %s\n", green, time.Since(started).Round(time.Millisecond), reset)
+
+	d.proc.stop(d.opts.StopTimeout)
+
+	if err := os.Rename(d.nextPath, d.binPath); err != nil {
+		d.failed("Replace binary failed", err)
+		return
+	}
+
+	proc, err := start(d.root, d.binPath, d.args)
+	if err != nil {
+		d.failed("Start failed", err)
+		return
+	}
+	d.proc = proc
+}
+
+// failed 在终端中输出错误，旧进程保持运行
+func (d *devServer) failed(stage string, err error) {
+	log.Printf("%s🔗 -> %s:\n%v %s\n", red, stage, err, reset)
+	if !d.proc.exited() {
+		log.Printf("%s🔗 -> Keeping previous service (pid %d) running. %s\n", yellow, d.proc.cmd.Process.Pid, reset)
+	}
+}
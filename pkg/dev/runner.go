@@ -0,0 +1,107 @@
+package dev
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"time"
+)
+
+// ANSI escape sequences define colors
+const (
+	reset  = "\033[0m"
+	red    = "\033[31m"
+	green  = "\033[32m"
+	yellow = "\033[33m"
+	blue   = "\033[34m"
+)
+
+// process 正在运行的服务进程
+type process struct {
+	cmd  *exec.Cmd
+	done chan struct{}
+}
+
+// build 编译 bin/ 下的服务入口到指定路径
+func build(projectRoot, mainPath, output string) error {
+	if err := os.MkdirAll(filepath.Dir(output), 0755); err != nil {
+		return fmt.Errorf("创建构建目录失败: %v", err)
+	}
+
+	cmd := exec.Command("go", "build", "-o", output, mainPath)
+	cmd.Dir = projectRoot
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("编译失败: %v\n%s", err, strings.TrimSpace(string(output)))
+	}
+	return nil
+}
+
+// start 启动服务进程
+func start(projectRoot, binPath string, args []string) (*process, error) {
+	cmd := exec.Command(binPath, args...)
+	cmd.Dir = projectRoot
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	cmd.Stdin = os.Stdin
+
+	if err := cmd.Start(); err != nil {
+		return nil, fmt.Errorf("启动服务失败: %v", err)
+	}
+
+	p := &process{cmd: cmd, done: make(chan struct{})}
+	go func() {
+		err := cmd.Wait()
+		if err != nil {
+			log.Printf("%s🔗 -> Service (pid %d) exited: %v %s\n", yellow, cmd.Process.Pid, err, reset)
+		} else {
+			log.Printf("%s🔗 -> Service (pid %d) exited. %s\n", yellow, cmd.Process.Pid, reset)
+		}
+		close(p.done)
+	}()
+
+	log.Printf("%s🔗 -> Service started (pid %d). %s\n", green, cmd.Process.Pid, reset)
+	return p, nil
+}
+
+// stop 发送 SIGTERM 让服务执行优雅关闭，超时后强制结束
+func (p *process) stop(timeout time.Duration) {
+	if p == nil {
+		return
+	}
+
+	select {
+	case <-p.done:
+		return
+	default:
+	}
+
+	log.Printf("%s🔗 -> Sending SIGTERM to service (pid %d)... %s\n", yellow, p.cmd.Process.Pid, reset)
+	if err := p.cmd.Process.Signal(syscall.SIGTERM); err != nil {
+		log.Printf("%s🔗 -> Send SIGTERM failed: %v %s\n", red, err, reset)
+	}
+
+	select {
+	case <-p.done:
+	case <-time.After(timeout):
+		log.Printf("%s🔗 -> Service did not exit within %s, killing it. %s\n", red, timeout, reset)
+		p.cmd.Process.Kill()
+		<-p.done
+	}
+}
+
+// exited 服务进程是否已退出
+func (p *process) exited() bool {
+	if p == nil {
+		return true
+	}
+	select {
+	case <-p.done:
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,176 @@
+package dev
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/printer"
+	"go/token"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// watchExts 需要监听变化的文件类型
+var watchExts = map[string]bool{
+	".go":   true,
+	".yaml": true,
+	".yml":  true,
+	".toml": true,
+	".json": true,
+}
+
+// fileState 文件状态，用于判断文件是否发生变化
+type fileState struct {
+	modTime time.Time
+	size    int64
+}
+
+// snapshot 监听目录的文件快照
+type snapshot map[string]fileState
+
+// takeSnapshot 扫描目录，记录所有被监听文件的状态
+func takeSnapshot(dirs []string, extraFiles []string) (snapshot, error) {
+	snap := make(snapshot)
+	for _, dir := range dirs {
+		if _, err := os.Stat(dir); os.IsNotExist(err) {
+			continue
+		}
+		err := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+			if err != nil {
+				return err
+			}
+			if info.IsDir() {
+				// 跳过隐藏目录
+				if path != dir && strings.HasPrefix(info.Name(), ".") {
+					return filepath.SkipDir
+				}
+				return nil
+			}
+			if !watchExts[filepath.Ext(path)] {
+				return nil
+			}
+			snap[path] = fileState{modTime: info.ModTime(), size: info.Size()}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	for _, file := range extraFiles {
+		if info, err := os.Stat(file); err == nil {
+			snap[file] = fileState{modTime: info.ModTime(), size: info.Size()}
+		}
+	}
+	return snap, nil
+}
+
+// diff 返回两次快照之间新增、修改或删除的文件
+func (s snapshot) diff(other snapshot) []string {
+	changed := make([]string, 0)
+	for path, state := range other {
+		if old, ok := s[path]; !ok || old != state {
+			changed = append(changed, path)
+		}
+	}
+	for path := range s {
+		if _, ok := other[path]; !ok {
+			changed = append(changed, path)
+		}
+	}
+	sort.Strings(changed)
+	return changed
+}
+
+// isGenerated 判断是否为 wire 生成的文件，这些文件的变化不应触发重新生成
+func isGenerated(path string) bool {
+	name := filepath.Base(path)
+	return name == "wire.go" || name == "wire_gen.go"
+}
+
+// wireSignature 计算 app 目录下影响依赖注入的代码签名
+// 只包含 provider set 定义、构造函数签名和结构体定义，函数体的修改不会改变签名
+func wireSignature(appPath string) (string, error) {
+	parts := make([]string, 0)
+
+	err := filepath.Walk(appPath, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() || !strings.HasSuffix(path, ".go") ||
+			strings.HasSuffix(path, "_test.go") || isGenerated(path) {
+			return nil
+		}
+
+		fset := token.NewFileSet()
+		file, err := parser.ParseFile(fset, path, nil, 0)
+		if err != nil {
+			return fmt.Errorf("failed to parse file %s: %v", path, err)
+		}
+
+		rel, _ := filepath.Rel(appPath, path)
+		for _, decl := range file.Decls {
+			switch d := decl.(type) {
+			case *ast.GenDecl:
+				if d.Tok != token.TYPE && d.Tok != token.VAR {
+					continue
+				}
+				for _, spec := range d.Specs {
+					switch sp := spec.(type) {
+					case *ast.TypeSpec:
+						if _, ok := sp.Type.(*ast.StructType); ok {
+							parts = append(parts, rel+":type:"+printNode(fset, sp))
+						}
+					case *ast.ValueSpec:
+						if isNewSet(sp) {
+							parts = append(parts, rel+":set:"+printNode(fset, sp))
+						}
+					}
+				}
+			case *ast.FuncDecl:
+				// 构造函数签名，只比较名称、参数与返回值
+				if d.Recv == nil && strings.HasPrefix(d.Name.Name, "New") {
+					parts = append(parts, rel+":func:"+d.Name.Name+printNode(fset, d.Type))
+				}
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return "", err
+	}
+
+	sort.Strings(parts)
+	sum := sha256.Sum256([]byte(strings.Join(parts, "\n")))
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// isNewSet 判断变量是否由 wire.NewSet 初始化
+func isNewSet(spec *ast.ValueSpec) bool {
+	for _, value := range spec.Values {
+		call, ok := value.(*ast.CallExpr)
+		if !ok {
+			continue
+		}
+		sel, ok := call.Fun.(*ast.SelectorExpr)
+		if !ok {
+			continue
+		}
+		if ident, ok := sel.X.(*ast.Ident); ok && ident.Name == "wire" && sel.Sel.Name == "NewSet" {
+			return true
+		}
+	}
+	return false
+}
+
+func printNode(fset *token.FileSet, node any) string {
+	var buf bytes.Buffer
+	printer.Fprint(&buf, fset, node)
+	return buf.String()
+}
@@ -0,0 +1,173 @@
+package gen
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+	"gorm.io/driver/mysql"
+	"gorm.io/driver/postgres"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// Database databases.list 中的数据库配置，只读取连接数据库所需的字段
+type Database struct {
+	DBName string `yaml:"dbname"` // 数据库名称(标记)，对应 Components.DbList 的 key
+	DBType string `yaml:"dbtype"` // 数据库类型 (postgres, mysql, sqlite)
+	DSN    string `yaml:"dsn"`    // 完整的 DSN 字符串
+}
+
+// envRegexp 匹配配置中的 ${NAME} 与 ${NAME:default}
+var envRegexp = regexp.MustCompile(`\$\{([A-Za-z_][A-Za-z0-9_]*)(?::([^}]*))?\}`)
+
+// LoadDatabases 读取配置目录下所有 yaml 文件中的 databases.list
+// 配置中的 ${NAME:default} 使用 envFile 与进程环境变量展开，进程环境变量优先
+func LoadDatabases(configPath, envFile string) ([]Database, error) {
+	env, err := readEnvFile(envFile)
+	if err != nil {
+		return nil, err
+	}
+
+	databases := make([]Database, 0)
+	err = filepath.Walk(configPath, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		ext := filepath.Ext(path)
+		if info.IsDir() || (ext != ".yaml" && ext != ".yml") {
+			return nil
+		}
+
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return fmt.Errorf("读取配置文件 %s 失败: %v", path, err)
+		}
+		// 只解析包含数据库配置的文件，避免其它配置中的模板语法导致解析失败
+		if !strings.Contains(string(content), "databases:") {
+			return nil
+		}
+		content = []byte(expandEnv(string(content), env))
+
+		var cfg struct {
+			Databases struct {
+				List []Database `yaml:"list"`
+			} `yaml:"databases"`
+		}
+		if err := yaml.Unmarshal(content, &cfg); err != nil {
+			return fmt.Errorf("解析配置文件 %s 失败: %v", path, err)
+		}
+		databases = append(databases, cfg.Databases.List...)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return databases, nil
+}
+
+// FindDatabase 按名称查找 databases.list 中的数据库配置
+func FindDatabase(configPath, envFile, name string) (*Database, error) {
+	databases, err := LoadDatabases(configPath, envFile)
+	if err != nil {
+		return nil, err
+	}
+
+	names := make([]string, 0, len(databases))
+	for i := range databases {
+		if databases[i].DBName == name {
+			return &databases[i], nil
+		}
+		names = append(names, databases[i].DBName)
+	}
+	return nil, fmt.Errorf("在 %s 的 databases.list 中未找到数据库 %s，可用: %s", configPath, name, strings.Join(names, ", "))
+}
+
+// ResolvePath 将 sqlite 的相对路径 DSN 转换为相对于项目根目录的路径，与服务在项目根目录运行时一致
+func (d *Database) ResolvePath(projectRoot string) {
+	dbType := strings.ToLower(d.DBType)
+	if dbType != "sqlite" && dbType != "sqlite3" {
+		return
+	}
+	if d.DSN == "" || strings.HasPrefix(d.DSN, "file:") || strings.HasPrefix(d.DSN, ":memory:") || filepath.IsAbs(d.DSN) {
+		return
+	}
+	d.DSN = filepath.Join(projectRoot, d.DSN)
+}
+
+// Open 按配置的类型与 DSN 连接数据库
+func (d *Database) Open() (*gorm.DB, error) {
+	if d.DSN == "" {
+		return nil, fmt.Errorf("数据库 %s 未配置 dsn", d.DBName)
+	}
+
+	var dialector gorm.Dialector
+	switch strings.ToLower(d.DBType) {
+	case "mysql":
+		dialector = mysql.Open(d.DSN)
+	case "postgres", "postgresql":
+		dialector = postgres.Open(d.DSN)
+	case "sqlite", "sqlite3":
+		dialector = sqlite.Open(d.DSN)
+	default:
+		return nil, fmt.Errorf("不支持的数据库类型: %s", d.DBType)
+	}
+
+	// 使用 Discard 而不是 Silent，sqlite 驱动读取索引时会强制开启 Debug 日志
+	db, err := gorm.Open(dialector, &gorm.Config{Logger: logger.Discard})
+	if err != nil {
+		return nil, fmt.Errorf("连接数据库 %s 失败: %v", d.DBName, err)
+	}
+	return db, nil
+}
+
+// readEnvFile 读取 KEY=VALUE 格式的环境变量文件，文件不存在时返回空集合
+func readEnvFile(path string) (map[string]string, error) {
+	env := make(map[string]string)
+	if path == "" {
+		return env, nil
+	}
+
+	f, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return env, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("读取环境变量文件失败: %v", err)
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		line = strings.TrimPrefix(line, "export ")
+		key, value, ok := strings.Cut(line, "=")
+		if !ok {
+			continue
+		}
+		env[strings.TrimSpace(key)] = strings.Trim(strings.TrimSpace(value), `"'`)
+	}
+	return env, scanner.Err()
+}
+
+// expandEnv 展开 ${NAME} 与 ${NAME:default}
+func expandEnv(content string, env map[string]string) string {
+	return envRegexp.ReplaceAllStringFunc(content, func(match string) string {
+		parts := envRegexp.FindStringSubmatch(match)
+		if value, ok := os.LookupEnv(parts[1]); ok {
+			return value
+		}
+		if value, ok := env[parts[1]]; ok {
+			return value
+		}
+		return parts[2]
+	})
+}
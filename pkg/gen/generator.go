@@ -0,0 +1,324 @@
+package gen
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"log"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"text/template"
+
+	"github.com/stones-hub/taurus-pro-core/pkg/project"
+)
+
+// ANSI escape sequences define colors
+const (
+	reset = "\033[0m"
+	green = "\033[32m"
+	blue  = "\033[34m"
+)
+
+// Options 资源生成选项
+type Options struct {
+	ProjectRoot string // 项目根目录
+	MainPath    string // 注册路由的入口文件，默认 bin/taurus.go
+	Force       bool   // 是否覆盖已存在的文件
+	SkipRoutes  bool   // 是否跳过路由注册
+	SkipWire    bool   // 是否跳过 wire 刷新
+	SkipTest    bool   // 是否跳过 controller 测试生成
+}
+
+// genFile 生成的文件与对应模板
+type genFile struct {
+	path string
+	tmpl string
+}
+
+// Generator 资源代码生成器
+type Generator struct {
+	opts Options
+}
+
+// NewGenerator 创建资源代码生成器
+func NewGenerator(opts Options) *Generator {
+	if opts.ProjectRoot == "" {
+		opts.ProjectRoot = "."
+	}
+	if opts.MainPath == "" {
+		opts.MainPath = filepath.Join("bin", "taurus.go")
+	}
+	return &Generator{opts: opts}
+}
+
+// Generate 生成资源的 model、dto、service、controller 及测试，注册路由，全部完成后刷新一次 wire
+func (g *Generator) Generate(resources ...*Resource) error {
+	module, err := project.GetModuleName(g.opts.ProjectRoot)
+	if err != nil {
+		return fmt.Errorf("获取模块名称失败: %v", err)
+	}
+
+	for _, res := range resources {
+		res.Module = module
+		if err := g.generate(res); err != nil {
+			return err
+		}
+	}
+
+	if !g.opts.SkipWire {
+		log.Printf("%s🔗 -> Refreshing app/wire.go... %s\n", blue, reset)
+		if err := project.RefreshProjectWire(filepath.Join(g.opts.ProjectRoot, "app")); err != nil {
+			return fmt.Errorf("刷新 wire 失败: %v", err)
+		}
+		log.Printf("%s🔗 -> Wire refreshed successfully. %s\n", green, reset)
+	}
+
+	return nil
+}
+
+// generate 生成单个资源的文件并注册路由
+func (g *Generator) generate(res *Resource) error {
+	files := []genFile{
+		{filepath.Join("app", "model", res.Snake+"_model.go"), modelTemplate},
+		{filepath.Join("app", "model", "dto", res.Snake+"_dto.go"), dtoTemplate},
+		{filepath.Join("app", "service", res.Snake+"_service.go"), serviceTemplate},
+		{filepath.Join("app", "controller", res.Snake+"_controller.go"), controllerTemplate},
+	}
+	if !g.opts.SkipTest {
+		files = append(files, genFile{filepath.Join("app", "controller", res.Snake+"_controller_test.go"), controllerTestTemplate})
+	}
+
+	// 先检查再写入，避免生成一半的资源
+	if !g.opts.Force {
+		for _, f := range files {
+			path := filepath.Join(g.opts.ProjectRoot, f.path)
+			if _, err := os.Stat(path); err == nil {
+				return fmt.Errorf("文件已存在: %s，使用 --force 覆盖", f.path)
+			}
+		}
+	}
+
+	for _, f := range files {
+		if err := g.render(f.path, f.tmpl, res); err != nil {
+			return err
+		}
+		log.Printf("%s🔗 -> Generated %s %s\n", green, f.path, reset)
+	}
+
+	if !g.opts.SkipRoutes {
+		if err := g.registerRoutes(res); err != nil {
+			return err
+		}
+		log.Printf("%s🔗 -> Registered routes /%s/* in %s %s\n", green, res.Kebab, g.opts.MainPath, reset)
+	}
+	return nil
+}
+
+// render 渲染模板，格式化后写入项目文件
+func (g *Generator) render(rel, text string, res *Resource) error {
+	tmpl, err := template.New(filepath.Base(rel)).Parse(text)
+	if err != nil {
+		return fmt.Errorf("解析模板失败: %v", err)
+	}
+
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, res); err != nil {
+		return fmt.Errorf("执行模板失败: %v", err)
+	}
+
+	source, err := format.Source(buf.Bytes())
+	if err != nil {
+		return fmt.Errorf("格式化 %s 失败: %v", rel, err)
+	}
+
+	path := filepath.Join(g.opts.ProjectRoot, rel)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("创建目录失败: %v", err)
+	}
+	if err := os.WriteFile(path, source, 0644); err != nil {
+		return fmt.Errorf("写入 %s 失败: %v", rel, err)
+	}
+	return nil
+}
+
+// registerRoutes 在入口文件注册路由的位置调用资源的路由注册函数，并追加该函数定义
+// 注册位置为传给 app.Execute 的路由函数末尾，旧项目的入口文件中为 main 函数的 app.Run() 之前
+func (g *Generator) registerRoutes(res *Resource) error {
+	path := filepath.Join(g.opts.ProjectRoot, g.opts.MainPath)
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("读取 %s 失败: %v", g.opts.MainPath, err)
+	}
+
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, path, content, parser.ParseComments)
+	if err != nil {
+		return fmt.Errorf("解析 %s 失败: %v", g.opts.MainPath, err)
+	}
+
+	funcName := res.Camel + "Routes"
+	for _, decl := range file.Decls {
+		if fn, ok := decl.(*ast.FuncDecl); ok && fn.Recv == nil && fn.Name.Name == funcName {
+			// 已经注册过，保持入口文件不变
+			return nil
+		}
+	}
+
+	callOffset, appName, ok := findRoutesOffset(fset, file)
+	if !ok {
+		return fmt.Errorf("在 %s 的 main 函数中未找到 app.Execute 或 app.Run()", g.opts.MainPath)
+	}
+
+	var routes bytes.Buffer
+	tmpl, err := template.New("routes").Parse(routesTemplate)
+	if err != nil {
+		return fmt.Errorf("解析模板失败: %v", err)
+	}
+	data := struct {
+		*Resource
+		App string
+	}{Resource: res, App: appName}
+	if err := tmpl.Execute(&routes, data); err != nil {
+		return fmt.Errorf("执行模板失败: %v", err)
+	}
+
+	// 按偏移量顺序拼接原文件与插入的内容: 缺失的导入、路由注册调用、路由函数定义
+	var out bytes.Buffer
+	importOffset, missing := missingImports(fset, file, res.Module, appName == "")
+	if len(missing) > 0 && importOffset < 0 {
+		return fmt.Errorf("%s 缺少导入: %s", g.opts.MainPath, strings.Join(missing, ", "))
+	}
+	if importOffset >= 0 {
+		out.Write(content[:importOffset])
+		for _, path := range missing {
+			out.WriteString("\t" + strconv.Quote(path) + "\n")
+		}
+		out.Write(content[importOffset:callOffset])
+	} else {
+		out.Write(content[:callOffset])
+	}
+	out.WriteString("\t" + funcName + "(" + appName + ")\n")
+	out.Write(content[callOffset:])
+	out.WriteString(routes.String())
+
+	source, err := format.Source(out.Bytes())
+	if err != nil {
+		return fmt.Errorf("格式化 %s 失败: %v", g.opts.MainPath, err)
+	}
+	return os.WriteFile(path, source, 0644)
+}
+
+// findRoutesOffset 查找路由注册调用的插入位置与传给路由函数的 *app.App 参数名:
+// main 函数调用 app.Execute(routes) 时为 routes 函数（或函数字面量）的右花括号，参数名为其第一个参数；
+// 调用 app.Run() 或 routes 没有参数的旧入口文件为该语句之前或右花括号，参数名为空，生成的路由函数使用 taurus.Container 与 app.Core
+func findRoutesOffset(fset *token.FileSet, file *ast.File) (int, string, bool) {
+	funcs := make(map[string]*ast.FuncDecl)
+	var main *ast.FuncDecl
+	for _, decl := range file.Decls {
+		fn, ok := decl.(*ast.FuncDecl)
+		if !ok || fn.Recv != nil || fn.Body == nil {
+			continue
+		}
+		funcs[fn.Name.Name] = fn
+		if fn.Name.Name == "main" {
+			main = fn
+		}
+	}
+	if main == nil {
+		return 0, "", false
+	}
+
+	for _, stmt := range main.Body.List {
+		expr, ok := stmt.(*ast.ExprStmt)
+		if !ok {
+			continue
+		}
+		call, ok := expr.X.(*ast.CallExpr)
+		if !ok {
+			continue
+		}
+		sel, ok := call.Fun.(*ast.SelectorExpr)
+		if !ok {
+			continue
+		}
+		if ident, ok := sel.X.(*ast.Ident); !ok || ident.Name != "app" {
+			continue
+		}
+
+		switch sel.Sel.Name {
+		case "Run":
+			return fset.Position(stmt.Pos()).Offset, "", true
+		case "Execute":
+			if len(call.Args) != 1 {
+				return 0, "", false
+			}
+			switch arg := call.Args[0].(type) {
+			case *ast.Ident:
+				if fn, ok := funcs[arg.Name]; ok {
+					return fset.Position(fn.Body.Rbrace).Offset, firstParam(fn.Type), true
+				}
+			case *ast.FuncLit:
+				return fset.Position(arg.Body.Rbrace).Offset, firstParam(arg.Type), true
+			}
+			return 0, "", false
+		}
+	}
+	return 0, "", false
+}
+
+// firstParam 返回函数的第一个参数名，没有参数或参数未命名时为空
+func firstParam(fn *ast.FuncType) string {
+	if fn.Params == nil || len(fn.Params.List) == 0 || len(fn.Params.List[0].Names) == 0 {
+		return ""
+	}
+	if name := fn.Params.List[0].Names[0].Name; name != "_" {
+		return name
+	}
+	return ""
+}
+
+// missingImports 返回路由注册代码需要但入口文件尚未导入的包，以及插入位置（import 块的右括号）
+// global 为 true 时路由函数通过 taurus.Container 访问组件并在路由组上加上 recovery 中间件，需要导入 internal/taurus、fmt 与 middleware
+// 无需补充或入口文件没有带括号的 import 块时，插入位置为 -1
+func missingImports(fset *token.FileSet, file *ast.File, module string, global bool) (int, []string) {
+	required := []string{
+		"net/http",
+		module + "/app",
+		"github.com/stones-hub/taurus-pro-http/pkg/router",
+	}
+	if global {
+		// 旧入口文件的路由组带有 recovery 中间件
+		required = append(required, "fmt", module+"/internal/taurus", "github.com/stones-hub/taurus-pro-http/pkg/middleware")
+	}
+
+	imported := make(map[string]bool)
+	for _, spec := range file.Imports {
+		path, _ := strconv.Unquote(spec.Path.Value)
+		imported[path] = true
+	}
+
+	missing := make([]string, 0)
+	for _, path := range required {
+		if !imported[path] {
+			missing = append(missing, path)
+		}
+	}
+	if len(missing) == 0 {
+		return -1, nil
+	}
+
+	for _, decl := range file.Decls {
+		gen, ok := decl.(*ast.GenDecl)
+		if ok && gen.Tok == token.IMPORT && gen.Rparen.IsValid() {
+			// 插入到右括号所在行的行首
+			p := fset.Position(gen.Rparen)
+			return p.Offset - (p.Column - 1), missing
+		}
+	}
+	return -1, missing
+}
@@ -0,0 +1,244 @@
+package gen
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+// entityMethods 生成的实体已占用的方法名，列名转换后不能与之冲突
+var entityMethods = map[string]bool{
+	"TableName": true,
+	"DB":        true,
+}
+
+// numericRegexp 匹配数字字面量默认值
+var numericRegexp = regexp.MustCompile(`^-?\d+(\.\d+)?$`)
+
+// NewResourceFromTable 读取数据库表的列与索引信息创建资源定义
+// name 为空时由表名推导，如 admin_users -> AdminUser
+func NewResourceFromTable(db *gorm.DB, table, name, dbName string) (*Resource, error) {
+	migrator := db.Migrator()
+	if !migrator.HasTable(table) {
+		return nil, fmt.Errorf("数据表 %s 不存在", table)
+	}
+
+	columns, err := migrator.ColumnTypes(table)
+	if err != nil {
+		return nil, fmt.Errorf("读取数据表 %s 的列信息失败: %v", table, err)
+	}
+	if len(columns) == 0 {
+		return nil, fmt.Errorf("数据表 %s 没有任何列", table)
+	}
+
+	dialect := db.Dialector.Name()
+	fields := make([]Field, 0, len(columns))
+	positions := make(map[string]int, len(columns))
+	for _, column := range columns {
+		field, err := columnField(dialect, column)
+		if err != nil {
+			return nil, fmt.Errorf("数据表 %s: %v", table, err)
+		}
+		positions[field.Column] = len(fields)
+		fields = append(fields, field)
+	}
+
+	// 索引信息，部分数据库不支持时退回到列上的唯一约束
+	indexes, err := migrator.GetIndexes(table)
+	if err == nil {
+		for _, index := range indexes {
+			if pk, _ := index.PrimaryKey(); pk {
+				continue
+			}
+			unique, _ := index.Unique()
+			cols := index.Columns()
+			for i, col := range cols {
+				pos, ok := positions[col]
+				if !ok {
+					continue
+				}
+				tag := IndexTag{Name: index.Name(), Unique: unique}
+				if len(cols) > 1 {
+					tag.Priority = i + 1
+				}
+				fields[pos].Indexes = append(fields[pos].Indexes, tag)
+			}
+		}
+	} else {
+		for i, column := range columns {
+			if unique, ok := column.Unique(); ok && unique && !fields[i].PrimaryKey {
+				fields[i].Unique = true
+			}
+		}
+	}
+
+	if name == "" {
+		name = ToPascal(Singular(table))
+	}
+	if !identRegexp.MatchString(name) {
+		return nil, fmt.Errorf("资源名称无效: %s，请使用 --name 指定", name)
+	}
+	return newResource(ToSnake(name), table, fields, dbName)
+}
+
+// columnField 将数据库列转换为资源字段
+func columnField(dialect string, column gorm.ColumnType) (Field, error) {
+	name := column.Name()
+	goName := ToPascal(name)
+	if !identRegexp.MatchString(goName) {
+		return Field{}, fmt.Errorf("列名 %s 无法转换为 Go 字段名", name)
+	}
+	if entityMethods[goName] {
+		return Field{}, fmt.Errorf("列 %s 与实体方法 %s 冲突", name, goName)
+	}
+
+	dbType := column.DatabaseTypeName()
+	if full, ok := column.ColumnType(); ok && full != "" {
+		dbType = full
+	}
+	goType, typ := goTypeOf(dialect, column.DatabaseTypeName(), dbType)
+
+	field := Field{
+		Name:   goName,
+		Column: name,
+		JSON:   name,
+		Type:   typ,
+		GoType: goType,
+		DBType: strings.ToLower(dbType),
+	}
+
+	if nullable, ok := column.Nullable(); ok {
+		field.Nullable = nullable
+	}
+	if pk, ok := column.PrimaryKey(); ok && pk {
+		field.PrimaryKey = true
+		field.Nullable = false
+	}
+	if auto, ok := column.AutoIncrement(); ok && auto {
+		field.AutoIncrement = true
+	}
+	if comment, ok := column.Comment(); ok {
+		field.Comment = comment
+	}
+
+	defaultValue, _ := column.DefaultValue()
+	defaultValue = strings.TrimSpace(defaultValue)
+	if field.PrimaryKey && isInteger(goType) {
+		// sqlite 的 INTEGER PRIMARY KEY 是 rowid 别名，postgres 的 serial 使用 nextval 默认值
+		if dialect == "sqlite" || strings.HasPrefix(strings.ToLower(defaultValue), "nextval(") {
+			field.AutoIncrement = true
+		}
+	}
+
+	switch {
+	case name == "created_at" && (field.IsTime() || isInteger(goType)):
+		field.AutoCreateTime = true
+	case name == "updated_at" && (field.IsTime() || isInteger(goType)):
+		field.AutoUpdateTime = true
+	case !field.AutoIncrement:
+		field.Default = normalizeDefault(dialect, defaultValue, goType)
+	}
+
+	return field, nil
+}
+
+// goTypeOf 根据数据库类型推导 Go 类型与生成器字段类型
+func goTypeOf(dialect, typeName, fullType string) (string, string) {
+	t := strings.ToLower(typeName)
+	full := strings.ToLower(fullType)
+	unsigned := strings.Contains(full, "unsigned")
+
+	// 带长度的类型名，如 sqlite 的 varchar(100)
+	if i := strings.Index(t, "("); i > 0 {
+		t = strings.TrimSpace(t[:i])
+	}
+
+	integer := func(bits string) (string, string) {
+		if unsigned {
+			return "uint" + bits, "uint" + bits
+		}
+		return "int" + bits, "int" + bits
+	}
+
+	switch t {
+	case "tinyint":
+		if dialect == "mysql" && strings.HasPrefix(full, "tinyint(1)") {
+			return "bool", "bool"
+		}
+		return integer("8")
+	case "smallint", "int2", "smallserial", "serial2", "year":
+		return integer("16")
+	case "mediumint", "int", "int4", "serial", "serial4":
+		return integer("32")
+	case "integer":
+		// sqlite 的整数都是 64 位
+		if dialect == "sqlite" {
+			return integer("64")
+		}
+		return integer("32")
+	case "bigint", "int8", "bigserial", "serial8":
+		return integer("64")
+	case "bool", "boolean":
+		return "bool", "bool"
+	case "float", "float4", "real":
+		if dialect == "sqlite" {
+			return "float64", "float64"
+		}
+		return "float32", "float32"
+	case "double", "double precision", "float8":
+		return "float64", "float64"
+	case "decimal", "numeric":
+		return "float64", "decimal"
+	case "date":
+		return "time.Time", "date"
+	case "datetime", "timestamp", "timestamptz", "timestamp with time zone", "timestamp without time zone":
+		return "time.Time", "time"
+	case "blob", "tinyblob", "mediumblob", "longblob", "binary", "varbinary", "bytea":
+		return "[]byte", "bytes"
+	case "text", "tinytext", "mediumtext", "longtext":
+		return "string", "text"
+	default:
+		// char、varchar、enum、set、json、uuid、time 等按字符串处理
+		return "string", "string"
+	}
+}
+
+// normalizeDefault 将数据库返回的默认值转换为 gorm 标签可用的形式，无法安全转换时忽略
+func normalizeDefault(dialect, value, goType string) string {
+	if value == "" || strings.EqualFold(value, "null") {
+		return ""
+	}
+
+	// postgres 的默认值带有类型转换，如 'abc'::character varying
+	if i := strings.Index(value, "::"); i > 0 {
+		value = value[:i]
+	}
+	value = strings.TrimSpace(strings.Trim(value, "()"))
+	// 这些字符会破坏 struct tag 或 gorm 标签的解析
+	if strings.ContainsAny(value, "\"\\;`") {
+		return ""
+	}
+
+	switch {
+	case strings.EqualFold(value, "current_timestamp"):
+		return "CURRENT_TIMESTAMP"
+	case numericRegexp.MatchString(value):
+		return value
+	case strings.EqualFold(value, "true"), strings.EqualFold(value, "false"):
+		return strings.ToLower(value)
+	case len(value) >= 2 && value[0] == '\'' && value[len(value)-1] == '\'':
+		return value
+	case dialect == "mysql" && goType == "string":
+		// mysql 返回未加引号的字符串默认值
+		return "'" + strings.ReplaceAll(value, "'", "''") + "'"
+	default:
+		return ""
+	}
+}
+
+// isInteger 是否为整数类型
+func isInteger(goType string) bool {
+	return strings.HasPrefix(goType, "int") || strings.HasPrefix(goType, "uint")
+}
@@ -0,0 +1,71 @@
+package gen
+
+import (
+	"testing"
+
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+func TestNewResourceFromTable(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open("file::memory:"), &gorm.Config{Logger: logger.Discard})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sqlDB, err := db.DB(); err == nil {
+		sqlDB.SetMaxOpenConns(1)
+	}
+
+	for _, sql := range []string{
+		`CREATE TABLE admin_users (user_id INTEGER PRIMARY KEY, username VARCHAR(100) NOT NULL, email VARCHAR(255), status TINYINT NOT NULL DEFAULT 1, nickname VARCHAR(50) NOT NULL DEFAULT 'guest', dept_id BIGINT NOT NULL, role_id BIGINT NOT NULL, last_login_at DATETIME, created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP, updated_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP)`,
+		`CREATE UNIQUE INDEX uk_username ON admin_users (username)`,
+		`CREATE INDEX idx_dept_role ON admin_users (dept_id, role_id)`,
+	} {
+		if err := db.Exec(sql).Error; err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	res, err := NewResourceFromTable(db, "admin_users", "", "admin")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Name != "AdminUser" || res.Table != "admin_users" || res.DBName != "admin" {
+		t.Fatalf("unexpected resource: %s %s %s", res.Name, res.Table, res.DBName)
+	}
+
+	pk := res.PK()
+	if pk.Name != "UserID" || pk.GoType != "int64" || !pk.AutoIncrement {
+		t.Fatalf("unexpected primary key: %+v", pk)
+	}
+
+	tags := make(map[string]string)
+	types := make(map[string]string)
+	for _, f := range res.Fields {
+		tags[f.Column] = f.GormTag()
+		types[f.Column] = f.FieldGoType()
+	}
+
+	expected := map[string]string{
+		"username":   "column:username;type:varchar(100);not null;uniqueIndex:uk_username",
+		"email":      "column:email;type:varchar(255)",
+		"status":     "column:status;type:tinyint;not null;default:1",
+		"nickname":   "column:nickname;type:varchar(50);not null;default:'guest'",
+		"dept_id":    "column:dept_id;type:bigint;not null;index:idx_dept_role,priority:1",
+		"role_id":    "column:role_id;type:bigint;not null;index:idx_dept_role,priority:2",
+		"created_at": "column:created_at;type:datetime;not null;autoCreateTime",
+	}
+	for column, tag := range expected {
+		if tags[column] != tag {
+			t.Errorf("%s: expected %q, got %q", column, tag, tags[column])
+		}
+	}
+
+	if types["email"] != "*string" || types["last_login_at"] != "*time.Time" || types["status"] != "int8" {
+		t.Errorf("unexpected go types: %v", types)
+	}
+	if len(res.InputFields()) != 7 || len(res.EditableFields()) != 7 {
+		t.Errorf("unexpected input fields: %d %d", len(res.InputFields()), len(res.EditableFields()))
+	}
+}
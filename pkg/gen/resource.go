@@ -0,0 +1,446 @@
+package gen
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// Field 资源字段定义
+type Field struct {
+	Name           string     // Go 字段名，如 UserName
+	Column         string     // 数据库列名，如 user_name
+	JSON           string     // JSON 字段名，如 user_name
+	Type           string     // 字段类型，如 string、int、time
+	GoType         string     // Go 类型，如 string、int64、time.Time
+	DBType         string     // 数据库类型，如 varchar(255)，为空时由 GORM 推断
+	Nullable       bool       // 是否允许为空，允许为空时使用指针类型
+	Unique         bool       // 是否唯一索引
+	Index          bool       // 是否普通索引
+	Indexes        []IndexTag // 具名索引，从数据库读取的索引使用
+	PrimaryKey     bool       // 是否主键
+	AutoIncrement  bool       // 是否自增
+	AutoCreateTime bool       // 创建时自动写入时间
+	AutoUpdateTime bool       // 更新时自动写入时间
+	Default        string     // 默认值
+	Comment        string     // 列注释
+}
+
+// IndexTag 字段所属的具名索引
+type IndexTag struct {
+	Name     string // 索引名
+	Unique   bool   // 是否唯一索引
+	Priority int    // 在联合索引中的位置，从 1 开始，单列索引为 0
+}
+
+// Resource 资源定义，描述一个完整的 CRUD 资源
+type Resource struct {
+	Name   string  // 资源名，如 Article
+	Snake  string  // 蛇形命名，如 blog_post
+	Camel  string  // 小驼峰命名，如 blogPost
+	Kebab  string  // 短横线命名，如 blog-post，用作路由前缀
+	Table  string  // 数据库表名，如 blog_posts
+	DBName string  // 使用的数据库连接名，对应 Components.DbList 的 key
+	Module string  // 项目模块名
+	Fields []Field // 全部字段，包含主键与时间戳
+}
+
+// fieldTypes 支持的字段类型: Go 类型与默认数据库类型
+var fieldTypes = map[string]struct {
+	goType string
+	dbType string
+}{
+	"string":  {"string", "varchar(255)"},
+	"text":    {"string", "text"},
+	"int":     {"int", ""},
+	"int8":    {"int8", ""},
+	"int16":   {"int16", ""},
+	"int32":   {"int32", ""},
+	"int64":   {"int64", ""},
+	"uint":    {"uint", ""},
+	"uint8":   {"uint8", ""},
+	"uint16":  {"uint16", ""},
+	"uint32":  {"uint32", ""},
+	"uint64":  {"uint64", ""},
+	"float32": {"float32", ""},
+	"float64": {"float64", ""},
+	"decimal": {"float64", "decimal(10,2)"},
+	"bool":    {"bool", ""},
+	"time":    {"time.Time", ""},
+	"date":    {"time.Time", "date"},
+	"bytes":   {"[]byte", ""},
+}
+
+// reservedColumns 由生成器自动添加的列
+var reservedColumns = map[string]bool{
+	"id":         true,
+	"created_at": true,
+	"updated_at": true,
+}
+
+var identRegexp = regexp.MustCompile(`^[A-Za-z][A-Za-z0-9_]*$`)
+
+// NewResource 根据资源名和字段描述创建资源定义
+// fields 格式: "name:string,age:int,email:string:unique,bio:text:null"
+// 第三段及以后为可选修饰符: unique、index、null
+func NewResource(name, fields, dbName string) (*Resource, error) {
+	parsed, err := ParseFields(fields)
+	if err != nil {
+		return nil, err
+	}
+	return NewResourceWithFields(name, parsed, dbName)
+}
+
+// NewResourceWithFields 根据资源名和已解析的字段创建资源定义
+func NewResourceWithFields(name string, fields []Field, dbName string) (*Resource, error) {
+	if !identRegexp.MatchString(name) {
+		return nil, fmt.Errorf("资源名称无效: %s", name)
+	}
+	if len(fields) == 0 {
+		return nil, fmt.Errorf("资源 %s 至少需要一个字段", name)
+	}
+
+	all := make([]Field, 0, len(fields)+3)
+	all = append(all, Field{
+		Name: "ID", Column: "id", JSON: "id", Type: "uint64", GoType: "uint64",
+		PrimaryKey: true, AutoIncrement: true, Comment: "自增ID",
+	})
+	all = append(all, fields...)
+	all = append(all,
+		Field{Name: "CreatedAt", Column: "created_at", JSON: "created_at", Type: "time", GoType: "time.Time", AutoCreateTime: true, Comment: "创建时间"},
+		Field{Name: "UpdatedAt", Column: "updated_at", JSON: "updated_at", Type: "time", GoType: "time.Time", AutoUpdateTime: true, Comment: "更新时间"},
+	)
+
+	snake := ToSnake(name)
+	return newResource(snake, Plural(snake), all, dbName)
+}
+
+// newResource 根据蛇形名称、表名和完整字段创建资源定义
+func newResource(snake, table string, fields []Field, dbName string) (*Resource, error) {
+	pks := 0
+	for _, f := range fields {
+		if f.PrimaryKey {
+			pks++
+		}
+	}
+	if pks != 1 {
+		return nil, fmt.Errorf("表 %s 需要且只能有一个主键列，实际 %d 个", table, pks)
+	}
+	if dbName == "" {
+		dbName = "default"
+	}
+
+	return &Resource{
+		Name:   ToPascal(snake),
+		Snake:  snake,
+		Camel:  ToCamel(snake),
+		Kebab:  strings.ReplaceAll(snake, "_", "-"),
+		Table:  table,
+		DBName: dbName,
+		Fields: fields,
+	}, nil
+}
+
+// ParseFields 解析字段描述
+func ParseFields(spec string) ([]Field, error) {
+	fields := make([]Field, 0)
+	seen := make(map[string]bool)
+
+	for _, item := range strings.Split(spec, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+
+		parts := strings.Split(item, ":")
+		if len(parts) < 2 {
+			return nil, fmt.Errorf("字段格式错误: %s，应为 name:type", item)
+		}
+
+		field, err := NewField(strings.TrimSpace(parts[0]), strings.TrimSpace(parts[1]))
+		if err != nil {
+			return nil, err
+		}
+
+		for _, modifier := range parts[2:] {
+			switch strings.ToLower(strings.TrimSpace(modifier)) {
+			case "unique":
+				field.Unique = true
+			case "index":
+				field.Index = true
+			case "null", "nullable":
+				field.Nullable = true
+			default:
+				return nil, fmt.Errorf("字段 %s 的修饰符无效: %s", field.Column, modifier)
+			}
+		}
+
+		if seen[field.Column] {
+			return nil, fmt.Errorf("字段重复: %s", field.Column)
+		}
+		seen[field.Column] = true
+		fields = append(fields, field)
+	}
+
+	if len(fields) == 0 {
+		return nil, fmt.Errorf("字段不能为空")
+	}
+	return fields, nil
+}
+
+// NewField 根据列名和字段类型创建字段
+func NewField(column, typ string) (Field, error) {
+	if !identRegexp.MatchString(column) {
+		return Field{}, fmt.Errorf("字段名无效: %s", column)
+	}
+	column = ToSnake(column)
+	if reservedColumns[column] {
+		return Field{}, fmt.Errorf("字段 %s 由生成器自动添加，无需声明", column)
+	}
+
+	t, ok := fieldTypes[strings.ToLower(typ)]
+	if !ok {
+		return Field{}, fmt.Errorf("字段 %s 的类型不支持: %s", column, typ)
+	}
+
+	return Field{
+		Name:   ToPascal(column),
+		Column: column,
+		JSON:   column,
+		Type:   strings.ToLower(typ),
+		GoType: t.goType,
+		DBType: t.dbType,
+	}, nil
+}
+
+// FieldGoType 返回字段在实体中的 Go 类型
+func (f Field) FieldGoType() string {
+	if f.Nullable && f.GoType != "[]byte" {
+		return "*" + f.GoType
+	}
+	return f.GoType
+}
+
+// GormTag 返回字段的 gorm 标签
+func (f Field) GormTag() string {
+	parts := make([]string, 0)
+	if f.PrimaryKey {
+		parts = append(parts, "primaryKey")
+	}
+	if f.AutoIncrement {
+		parts = append(parts, "autoIncrement")
+	}
+	parts = append(parts, "column:"+f.Column)
+	if f.DBType != "" {
+		parts = append(parts, "type:"+f.DBType)
+	}
+	if !f.Nullable && !f.PrimaryKey {
+		parts = append(parts, "not null")
+	}
+	if f.Default != "" {
+		parts = append(parts, "default:"+f.Default)
+	}
+	if f.AutoCreateTime {
+		parts = append(parts, "autoCreateTime")
+	}
+	if f.AutoUpdateTime {
+		parts = append(parts, "autoUpdateTime")
+	}
+	if f.Unique {
+		parts = append(parts, "uniqueIndex")
+	} else if f.Index {
+		parts = append(parts, "index")
+	}
+	for _, idx := range f.Indexes {
+		tag := "index:" + idx.Name
+		if idx.Unique {
+			tag = "uniqueIndex:" + idx.Name
+		}
+		if idx.Priority > 0 {
+			tag += ",priority:" + strconv.Itoa(idx.Priority)
+		}
+		parts = append(parts, tag)
+	}
+	if f.Comment != "" {
+		parts = append(parts, "comment:"+strings.NewReplacer(";", "，", "`", "'", "\"", "'", "\n", " ").Replace(f.Comment))
+	}
+	return strings.Join(parts, ";")
+}
+
+// IsTime 字段是否为时间类型
+func (f Field) IsTime() bool {
+	return f.GoType == "time.Time"
+}
+
+// IsString 字段是否为字符串类型
+func (f Field) IsString() bool {
+	return f.GoType == "string"
+}
+
+// IsUnsigned 字段是否为无符号整数类型
+func (f Field) IsUnsigned() bool {
+	return strings.HasPrefix(f.GoType, "uint")
+}
+
+// Zero 返回字段类型零值的 Go 字面量
+func (f Field) Zero() string {
+	if f.IsString() {
+		return `""`
+	}
+	return "0"
+}
+
+// PK 返回资源的主键字段
+func (r *Resource) PK() Field {
+	for _, f := range r.Fields {
+		if f.PrimaryKey {
+			return f
+		}
+	}
+	return Field{}
+}
+
+// InputFields 创建请求中的字段，不包含自增主键与自动维护的时间戳
+func (r *Resource) InputFields() []Field {
+	fields := make([]Field, 0, len(r.Fields))
+	for _, f := range r.Fields {
+		if f.AutoIncrement || f.AutoCreateTime || f.AutoUpdateTime {
+			continue
+		}
+		fields = append(fields, f)
+	}
+	return fields
+}
+
+// EditableFields 更新请求中可修改的字段，不包含主键与自动维护的时间戳
+func (r *Resource) EditableFields() []Field {
+	fields := make([]Field, 0, len(r.Fields))
+	for _, f := range r.InputFields() {
+		if !f.PrimaryKey {
+			fields = append(fields, f)
+		}
+	}
+	return fields
+}
+
+// HasTime 资源实体是否包含时间字段
+func (r *Resource) HasTime() bool {
+	for _, f := range r.Fields {
+		if f.IsTime() {
+			return true
+		}
+	}
+	return false
+}
+
+// InputHasTime 请求 DTO 是否包含时间字段
+func (r *Resource) InputHasTime() bool {
+	for _, f := range r.InputFields() {
+		if f.IsTime() {
+			return true
+		}
+	}
+	return false
+}
+
+// ToSnake 转换为蛇形命名: BlogPost -> blog_post
+func ToSnake(s string) string {
+	s = strings.ReplaceAll(s, "-", "_")
+	runes := []rune(s)
+	var b strings.Builder
+	for i, r := range runes {
+		if unicode.IsUpper(r) {
+			if i > 0 && runes[i-1] != '_' &&
+				(unicode.IsLower(runes[i-1]) || unicode.IsDigit(runes[i-1]) ||
+					(i+1 < len(runes) && unicode.IsLower(runes[i+1]))) {
+				b.WriteByte('_')
+			}
+			b.WriteRune(unicode.ToLower(r))
+			continue
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// commonInitialisms 常见缩写，转换为 Go 命名时保持全大写
+var commonInitialisms = map[string]bool{
+	"id": true, "ip": true, "url": true, "uri": true, "api": true,
+	"http": true, "json": true, "sql": true, "uuid": true, "html": true,
+}
+
+// ToPascal 转换为大驼峰命名: blog_post -> BlogPost, user_id -> UserID
+func ToPascal(s string) string {
+	var b strings.Builder
+	for _, part := range strings.Split(ToSnake(s), "_") {
+		if part == "" {
+			continue
+		}
+		if commonInitialisms[part] {
+			b.WriteString(strings.ToUpper(part))
+			continue
+		}
+		runes := []rune(part)
+		runes[0] = unicode.ToUpper(runes[0])
+		b.WriteString(string(runes))
+	}
+	return b.String()
+}
+
+// ToCamel 转换为小驼峰命名: blog_post -> blogPost
+func ToCamel(s string) string {
+	snake := ToSnake(s)
+	parts := strings.SplitN(snake, "_", 2)
+	if len(parts) == 1 {
+		return snake
+	}
+	return parts[0] + ToPascal(parts[1])
+}
+
+// Plural 英文复数形式，用于推导表名: blog_post -> blog_posts
+func Plural(s string) string {
+	switch {
+	case strings.HasSuffix(s, "s"), strings.HasSuffix(s, "x"), strings.HasSuffix(s, "z"),
+		strings.HasSuffix(s, "ch"), strings.HasSuffix(s, "sh"):
+		return s + "es"
+	case strings.HasSuffix(s, "y") && len(s) > 1 && !strings.ContainsRune("aeiou", rune(s[len(s)-2])):
+		return s[:len(s)-1] + "ies"
+	default:
+		return s + "s"
+	}
+}
+
+// Singular 英文单数形式，用于从表名推导资源名: admin_users -> admin_user
+func Singular(s string) string {
+	switch {
+	case strings.HasSuffix(s, "ies") && len(s) > 3:
+		return s[:len(s)-3] + "y"
+	case strings.HasSuffix(s, "sses"), strings.HasSuffix(s, "xes"), strings.HasSuffix(s, "zes"),
+		strings.HasSuffix(s, "ches"), strings.HasSuffix(s, "shes"):
+		return s[:len(s)-2]
+	case strings.HasSuffix(s, "ss"), strings.HasSuffix(s, "us"), strings.HasSuffix(s, "is"):
+		return s
+	case strings.HasSuffix(s, "s") && len(s) > 1:
+		return s[:len(s)-1]
+	default:
+		return s
+	}
+}
+
+// Sample 返回字段的示例值（Go 字面量），用于生成测试数据
+func (f Field) Sample() string {
+	switch f.GoType {
+	case "string":
+		return `"example"`
+	case "bool":
+		return "true"
+	case "time.Time":
+		return `"2025-01-01T00:00:00Z"`
+	case "[]byte":
+		return `"ZXhhbXBsZQ=="`
+	default:
+		return "1"
+	}
+}
@@ -0,0 +1,35 @@
+package gen
+
+import "testing"
+
+func TestNaming(t *testing.T) {
+	cases := []struct{ in, snake, pascal, camel, plural string }{
+		{"Article", "article", "Article", "article", "articles"},
+		{"BlogPost", "blog_post", "BlogPost", "blogPost", "blog_posts"},
+		{"user_id", "user_id", "UserID", "userID", "user_ids"},
+		{"HTTPProxy", "http_proxy", "HTTPProxy", "httpProxy", "http_proxies"},
+		{"Category", "category", "Category", "category", "categories"},
+	}
+	for _, c := range cases {
+		snake := ToSnake(c.in)
+		if snake != c.snake || ToPascal(c.in) != c.pascal || ToCamel(c.in) != c.camel || Plural(snake) != c.plural {
+			t.Errorf("%s: got %s %s %s %s", c.in, snake, ToPascal(c.in), ToCamel(c.in), Plural(snake))
+		}
+	}
+}
+
+func TestParseFields(t *testing.T) {
+	fields, err := ParseFields("name:string:unique, bio:text:null,age:int")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(fields) != 3 || !fields[0].Unique || fields[1].FieldGoType() != "*string" || fields[2].GoType != "int" {
+		t.Fatalf("unexpected fields: %+v", fields)
+	}
+
+	for _, spec := range []string{"", "name", "name:unknown", "id:int", "name:string,name:text", "name:string:primary"} {
+		if _, err := ParseFields(spec); err == nil {
+			t.Errorf("expected error for %q", spec)
+		}
+	}
+}
@@ -0,0 +1,517 @@
+package gen
+
+// model 模板
+const modelTemplate = `package model
+
+import (
+	"context"
+	"fmt"
+	"{{.Module}}/internal/taurus"
+{{- if .HasTime}}
+	"time"
+{{- end}}
+
+	"github.com/stones-hub/taurus-pro-storage/pkg/db/dao"
+	"gorm.io/gorm"
+)
+
+// {{.Name}} {{.Table}} 表实体
+type {{.Name}} struct {
+{{- range .Fields}}
+	{{.Name}} {{.FieldGoType}} ` + "`" + `json:"{{.JSON}}" gorm:"{{.GormTag}}"` + "`" + `
+{{- end}}
+}
+
+// TableName 实现Entity接口 - 返回数据库表名
+func ({{.Name}}) TableName() string {
+	return "{{.Table}}"
+}
+
+// DB 实现Entity接口；数据库连接由 New{{.Name}}Repository(components) 注入仓库，实体不读取全局的 taurus.Container
+func ({{.Name}}) DB() *gorm.DB {
+	return nil
+}
+
+// {{.Name}}Repository {{.Name}} 数据访问
+type {{.Name}}Repository struct {
+	dao.Repository[{{.Name}}]
+}
+
+// New{{.Name}}RepositoryWithDB 使用指定的数据库连接创建{{.Name}} Repository实例
+func New{{.Name}}RepositoryWithDB(db *gorm.DB) *{{.Name}}Repository {
+	return &{{.Name}}Repository{
+		Repository: dao.NewBaseRepository[{{.Name}}](db),
+	}
+}
+
+// New{{.Name}}Repository 使用组件中的 {{.DBName}} 数据库创建{{.Name}} Repository实例
+func New{{.Name}}Repository(components *taurus.Components) (*{{.Name}}Repository, error) {
+	db, exists := components.DbList["{{.DBName}}"]
+	if !exists {
+		// 如果指定的数据库不存在，尝试获取第一个可用的数据库
+		for _, d := range components.DbList {
+			db = d
+			break
+		}
+	}
+	if db == nil {
+		return nil, fmt.Errorf("没有可用的数据库连接")
+	}
+	return New{{.Name}}RepositoryWithDB(db), nil
+}
+
+// Page 分页查询{{.Name}}，按主键倒序
+func (r *{{.Name}}Repository) Page(ctx context.Context, page, pageSize int) ([]{{.Name}}, int64, error) {
+	return r.FindWithPagination(ctx, page, pageSize, "{{.PK.Column}}", true, "")
+}
+`
+
+// dto 模板
+const dtoTemplate = `package dto
+
+import (
+	"{{.Module}}/app/model"
+{{- if .InputHasTime}}
+	"time"
+{{- end}}
+)
+
+// Create{{.Name}}Request 创建{{.Name}}请求
+type Create{{.Name}}Request struct {
+{{- range .InputFields}}
+	{{.Name}} {{.FieldGoType}} ` + "`" + `json:"{{.JSON}}"` + "`" + `
+{{- end}}
+}
+
+// Update{{.Name}}Request 更新{{.Name}}请求，未传的字段保持不变
+type Update{{.Name}}Request struct {
+	{{.PK.Name}} {{.PK.GoType}} ` + "`" + `json:"{{.PK.JSON}}"` + "`" + `
+{{- range .EditableFields}}
+	{{.Name}} {{if eq .GoType "[]byte"}}[]byte{{else}}*{{.GoType}}{{end}} ` + "`" + `json:"{{.JSON}},omitempty"` + "`" + `
+{{- end}}
+}
+
+// {{.Name}}ListRequest {{.Name}}列表请求
+type {{.Name}}ListRequest struct {
+	Page     int ` + "`" + `json:"page"` + "`" + `
+	PageSize int ` + "`" + `json:"page_size"` + "`" + `
+}
+
+// {{.Name}}ListResponse {{.Name}}列表响应
+type {{.Name}}ListResponse struct {
+	List     []model.{{.Name}} ` + "`" + `json:"list"` + "`" + `
+	Total    int64 ` + "`" + `json:"total"` + "`" + `
+	Page     int ` + "`" + `json:"page"` + "`" + `
+	PageSize int ` + "`" + `json:"page_size"` + "`" + `
+}
+`
+
+// service 模板
+const serviceTemplate = `package service
+
+import (
+	"context"
+	"{{.Module}}/app/model"
+	"{{.Module}}/app/model/dto"
+	"{{.Module}}/internal/taurus"
+
+	"github.com/google/wire"
+	"gorm.io/gorm"
+)
+
+// {{.Name}}Service {{.Name}}服务，封装{{.Name}}的增删改查
+type {{.Name}}Service struct {
+	{{.Camel}}Repo *model.{{.Name}}Repository
+}
+
+// {{.Name}}ServiceSet wire provider set
+var {{.Name}}ServiceSet = wire.NewSet(New{{.Name}}Service)
+
+// New{{.Name}}Service 创建{{.Name}}Service实例，使用 {{.DBName}} 数据库，由 wire 注入 components
+func New{{.Name}}Service(components *taurus.Components) *{{.Name}}Service {
+	{{.Camel}}Repo, err := model.New{{.Name}}Repository(components)
+	if err != nil {
+		panic("创建{{.Name}}Repository失败: " + err.Error())
+	}
+	return &{{.Name}}Service{
+		{{.Camel}}Repo: {{.Camel}}Repo,
+	}
+}
+
+// New{{.Name}}ServiceWithDB 使用指定的数据库连接创建{{.Name}}Service实例
+func New{{.Name}}ServiceWithDB(db *gorm.DB) *{{.Name}}Service {
+	return &{{.Name}}Service{
+		{{.Camel}}Repo: model.New{{.Name}}RepositoryWithDB(db),
+	}
+}
+
+// Create{{.Name}} 创建{{.Name}}
+func (s *{{.Name}}Service) Create{{.Name}}(ctx context.Context, req *dto.Create{{.Name}}Request) (*model.{{.Name}}, error) {
+	entity := &model.{{.Name}}{
+{{- range .InputFields}}
+		{{.Name}}: req.{{.Name}},
+{{- end}}
+	}
+
+	if err := s.{{.Camel}}Repo.Create(ctx, entity); err != nil {
+		return nil, err
+	}
+	return entity, nil
+}
+
+// Get{{.Name}}ByID 根据ID获取{{.Name}}
+func (s *{{.Name}}Service) Get{{.Name}}ByID(ctx context.Context, id {{.PK.GoType}}) (*model.{{.Name}}, error) {
+	entity, err := s.{{.Camel}}Repo.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if entity == nil {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return entity, nil
+}
+
+// List{{.Name}} 分页获取{{.Name}}列表
+func (s *{{.Name}}Service) List{{.Name}}(ctx context.Context, req *dto.{{.Name}}ListRequest) (*dto.{{.Name}}ListResponse, error) {
+	page, pageSize := req.Page, req.PageSize
+	if page <= 0 {
+		page = 1
+	}
+	if pageSize <= 0 || pageSize > 100 {
+		pageSize = 20
+	}
+
+	list, total, err := s.{{.Camel}}Repo.Page(ctx, page, pageSize)
+	if err != nil {
+		return nil, err
+	}
+	return &dto.{{.Name}}ListResponse{
+		List:     list,
+		Total:    total,
+		Page:     page,
+		PageSize: pageSize,
+	}, nil
+}
+
+// Update{{.Name}} 更新{{.Name}}，只修改请求中传入的字段
+func (s *{{.Name}}Service) Update{{.Name}}(ctx context.Context, req *dto.Update{{.Name}}Request) (*model.{{.Name}}, error) {
+	entity, err := s.Get{{.Name}}ByID(ctx, req.{{.PK.Name}})
+	if err != nil {
+		return nil, err
+	}
+
+{{- range .EditableFields}}
+	if req.{{.Name}} != nil {
+		entity.{{.Name}} = {{if or .Nullable (eq .GoType "[]byte")}}{{else}}*{{end}}req.{{.Name}}
+	}
+{{- end}}
+
+	if err := s.{{.Camel}}Repo.Update(ctx, entity); err != nil {
+		return nil, err
+	}
+	return entity, nil
+}
+
+// Delete{{.Name}} 删除{{.Name}}
+func (s *{{.Name}}Service) Delete{{.Name}}(ctx context.Context, id {{.PK.GoType}}) error {
+	return s.{{.Camel}}Repo.DeleteByID(ctx, id)
+}
+`
+
+// controller 模板
+const controllerTemplate = `package controller
+
+import (
+	"{{.Module}}/app/model/dto"
+	"{{.Module}}/app/service"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/google/wire"
+	"github.com/stones-hub/taurus-pro-http/pkg/httpx"
+	"gorm.io/gorm"
+)
+
+// {{.Name}}Controller {{.Name}}控制器
+type {{.Name}}Controller struct {
+	{{.Name}}Service *service.{{.Name}}Service
+}
+
+// {{.Name}}ControllerSet wire provider set
+var {{.Name}}ControllerSet = wire.NewSet(wire.Struct(new({{.Name}}Controller), "*"))
+
+// Create{{.Name}} 创建{{.Name}}
+func (c *{{.Name}}Controller) Create{{.Name}}(w http.ResponseWriter, r *http.Request) {
+	var req dto.Create{{.Name}}Request
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		httpx.SendResponse(w, http.StatusBadRequest, map[string]string{"message": "请求参数解析失败: " + err.Error()}, nil)
+		return
+	}
+
+	entity, err := c.{{.Name}}Service.Create{{.Name}}(r.Context(), &req)
+	if err != nil {
+		httpx.SendResponse(w, http.StatusInternalServerError, map[string]string{"message": "创建{{.Name}}失败: " + err.Error()}, nil)
+		return
+	}
+
+	httpx.SendResponse(w, http.StatusCreated, entity, nil)
+}
+
+// Get{{.Name}}ByID 根据ID获取{{.Name}}
+func (c *{{.Name}}Controller) Get{{.Name}}ByID(w http.ResponseWriter, r *http.Request) {
+	id, err := c.parseID(r)
+	if err != nil {
+		httpx.SendResponse(w, http.StatusBadRequest, map[string]string{"message": err.Error()}, nil)
+		return
+	}
+
+	entity, err := c.{{.Name}}Service.Get{{.Name}}ByID(r.Context(), id)
+	if err != nil {
+		c.sendError(w, "获取{{.Name}}失败", err)
+		return
+	}
+
+	httpx.SendResponse(w, http.StatusOK, entity, nil)
+}
+
+// List{{.Name}} 分页获取{{.Name}}列表
+func (c *{{.Name}}Controller) List{{.Name}}(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	page, _ := strconv.Atoi(query.Get("page"))
+	pageSize, _ := strconv.Atoi(query.Get("page_size"))
+
+	resp, err := c.{{.Name}}Service.List{{.Name}}(r.Context(), &dto.{{.Name}}ListRequest{Page: page, PageSize: pageSize})
+	if err != nil {
+		httpx.SendResponse(w, http.StatusInternalServerError, map[string]string{"message": "获取{{.Name}}列表失败: " + err.Error()}, nil)
+		return
+	}
+
+	httpx.SendResponse(w, http.StatusOK, resp, nil)
+}
+
+// Update{{.Name}} 更新{{.Name}}
+func (c *{{.Name}}Controller) Update{{.Name}}(w http.ResponseWriter, r *http.Request) {
+	var req dto.Update{{.Name}}Request
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		httpx.SendResponse(w, http.StatusBadRequest, map[string]string{"message": "请求参数解析失败: " + err.Error()}, nil)
+		return
+	}
+	if req.{{.PK.Name}} == {{.PK.Zero}} {
+		httpx.SendResponse(w, http.StatusBadRequest, map[string]string{"message": "ID不能为空"}, nil)
+		return
+	}
+
+	entity, err := c.{{.Name}}Service.Update{{.Name}}(r.Context(), &req)
+	if err != nil {
+		c.sendError(w, "更新{{.Name}}失败", err)
+		return
+	}
+
+	httpx.SendResponse(w, http.StatusOK, entity, nil)
+}
+
+// Delete{{.Name}} 删除{{.Name}}
+func (c *{{.Name}}Controller) Delete{{.Name}}(w http.ResponseWriter, r *http.Request) {
+	id, err := c.parseID(r)
+	if err != nil {
+		httpx.SendResponse(w, http.StatusBadRequest, map[string]string{"message": err.Error()}, nil)
+		return
+	}
+
+	if err := c.{{.Name}}Service.Delete{{.Name}}(r.Context(), id); err != nil {
+		c.sendError(w, "删除{{.Name}}失败", err)
+		return
+	}
+
+	httpx.SendResponse(w, http.StatusOK, map[string]string{"message": "删除成功"}, nil)
+}
+
+// parseID 解析查询参数中的 id
+func (c *{{.Name}}Controller) parseID(r *http.Request) ({{.PK.GoType}}, error) {
+	value := r.URL.Query().Get("id")
+{{- if .PK.IsString}}
+	if value == "" {
+		return "", errors.New("ID不能为空")
+	}
+	return value, nil
+{{- else if .PK.IsUnsigned}}
+	id, err := strconv.ParseUint(value, 10, 64)
+	if err != nil || id == 0 {
+		return 0, errors.New("ID格式错误")
+	}
+	return {{.PK.GoType}}(id), nil
+{{- else}}
+	id, err := strconv.ParseInt(value, 10, 64)
+	if err != nil || id == 0 {
+		return 0, errors.New("ID格式错误")
+	}
+	return {{.PK.GoType}}(id), nil
+{{- end}}
+}
+
+// sendError 记录不存在时返回 404，其它错误返回 500
+func (c *{{.Name}}Controller) sendError(w http.ResponseWriter, message string, err error) {
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		httpx.SendResponse(w, http.StatusNotFound, map[string]string{"message": "{{.Name}}不存在"}, nil)
+		return
+	}
+	httpx.SendResponse(w, http.StatusInternalServerError, map[string]string{"message": message + ": " + err.Error()}, nil)
+}
+`
+
+// controller 测试模板，使用内存 sqlite 验证完整的增删改查流程
+const controllerTestTemplate = `package controller
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"{{.Module}}/app/model"
+	"{{.Module}}/app/service"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func new{{.Name}}TestController(t *testing.T) *{{.Name}}Controller {
+	t.Helper()
+
+	db, err := gorm.Open(sqlite.Open("file::memory:"), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("打开测试数据库失败: %v", err)
+	}
+	// 内存数据库每个连接相互独立，限制为单连接保证数据可见
+	if sqlDB, err := db.DB(); err == nil {
+		sqlDB.SetMaxOpenConns(1)
+	}
+	if err := db.AutoMigrate(&model.{{.Name}}{}); err != nil {
+		t.Fatalf("迁移{{.Table}}失败: %v", err)
+	}
+
+	return &{{.Name}}Controller{
+		{{.Name}}Service: service.New{{.Name}}ServiceWithDB(db),
+	}
+}
+
+// decode{{.Name}} 解析响应中的{{.Name}}，兼容 data 包装的响应格式
+func decode{{.Name}}(t *testing.T, body []byte) model.{{.Name}} {
+	t.Helper()
+
+	var wrapped struct {
+		Data *model.{{.Name}} ` + "`" + `json:"data"` + "`" + `
+	}
+	if err := json.Unmarshal(body, &wrapped); err == nil && wrapped.Data != nil {
+		return *wrapped.Data
+	}
+
+	var entity model.{{.Name}}
+	if err := json.Unmarshal(body, &entity); err != nil {
+		t.Fatalf("解析响应失败: %v", err)
+	}
+	return entity
+}
+
+func Test{{.Name}}ControllerCRUD(t *testing.T) {
+	c := new{{.Name}}TestController(t)
+
+	// 创建
+	body, _ := json.Marshal(map[string]any{
+{{- range .InputFields}}
+		"{{.JSON}}": {{.Sample}},
+{{- end}}
+	})
+	w := httptest.NewRecorder()
+	c.Create{{.Name}}(w, httptest.NewRequest(http.MethodPost, "/{{.Kebab}}/create", bytes.NewReader(body)))
+	if w.Code != http.StatusCreated {
+		t.Fatalf("创建{{.Name}}失败: %d %s", w.Code, w.Body.String())
+	}
+
+	created := decode{{.Name}}(t, w.Body.Bytes())
+	if created.{{.PK.Name}} == {{.PK.Zero}} {
+		t.Fatalf("创建{{.Name}}未返回ID: %s", w.Body.String())
+	}
+	id := fmt.Sprint(created.{{.PK.Name}})
+
+	// 查询
+	w = httptest.NewRecorder()
+	c.Get{{.Name}}ByID(w, httptest.NewRequest(http.MethodGet, "/{{.Kebab}}/get?id="+id, nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("获取{{.Name}}失败: %d %s", w.Code, w.Body.String())
+	}
+
+	// 列表
+	w = httptest.NewRecorder()
+	c.List{{.Name}}(w, httptest.NewRequest(http.MethodGet, "/{{.Kebab}}/list?page=1&page_size=10", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("获取{{.Name}}列表失败: %d %s", w.Code, w.Body.String())
+	}
+
+	// 更新
+	body, _ = json.Marshal(map[string]any{"{{.PK.JSON}}": created.{{.PK.Name}}})
+	w = httptest.NewRecorder()
+	c.Update{{.Name}}(w, httptest.NewRequest(http.MethodPost, "/{{.Kebab}}/update", bytes.NewReader(body)))
+	if w.Code != http.StatusOK {
+		t.Fatalf("更新{{.Name}}失败: %d %s", w.Code, w.Body.String())
+	}
+
+	// 删除
+	w = httptest.NewRecorder()
+	c.Delete{{.Name}}(w, httptest.NewRequest(http.MethodPost, "/{{.Kebab}}/delete?id="+id, nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("删除{{.Name}}失败: %d %s", w.Code, w.Body.String())
+	}
+
+	w = httptest.NewRecorder()
+	c.Get{{.Name}}ByID(w, httptest.NewRequest(http.MethodGet, "/{{.Kebab}}/get?id="+id, nil))
+	if w.Code != http.StatusNotFound {
+		t.Fatalf("删除后应返回404，实际: %d %s", w.Code, w.Body.String())
+	}
+}
+`
+
+// 路由注册模板，追加到 bin/taurus.go
+// App 为入口文件中路由函数的 *app.App 参数名，为空时（旧入口文件）通过 taurus.Container 与 app.Core 访问，
+// 并在路由组上加上 recovery 中间件；新入口文件由 http.middleware 中的全局中间件处理 panic
+const routesTemplate = `
+// {{.Camel}}Routes 注册{{.Name}} Controller的路由
+{{- $core := "app.Core"}}{{if .App}}{{$core = printf "%s.Core" .App}}{{end}}
+func {{.Camel}}Routes({{if .App}}{{.App}} *app.App{{end}}) {
+	{{if .App}}{{.App}}{{else}}taurus.Container{{end}}.Http.AddRouterGroup(router.RouteGroup{
+		Prefix: "/{{.Kebab}}",
+		{{- if not .App}}
+		Middleware: []router.MiddlewareFunc{
+			middleware.RecoveryMiddleware(func(err any, stack string) {
+				fmt.Printf("Error: %v\nStack: %s\n", err, stack)
+			}),
+		},
+		{{- end}}
+		Routes: []router.Router{
+			{
+				Path:    "/create",
+				Handler: http.HandlerFunc({{$core}}.{{.Name}}Controller.Create{{.Name}}),
+			},
+			{
+				Path:    "/get",
+				Handler: http.HandlerFunc({{$core}}.{{.Name}}Controller.Get{{.Name}}ByID),
+			},
+			{
+				Path:    "/list",
+				Handler: http.HandlerFunc({{$core}}.{{.Name}}Controller.List{{.Name}}),
+			},
+			{
+				Path:    "/update",
+				Handler: http.HandlerFunc({{$core}}.{{.Name}}Controller.Update{{.Name}}),
+			},
+			{
+				Path:    "/delete",
+				Handler: http.HandlerFunc({{$core}}.{{.Name}}Controller.Delete{{.Name}}),
+			},
+		},
+	})
+}
+`
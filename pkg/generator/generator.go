@@ -164,6 +164,12 @@ func (g *ProjectGenerator) generateComponentWire(componentWriePath string) error
 		return fmt.Errorf("生成 wire.go 失败: %v", err)
 	}
 
+	// 生成测试组件 internal/taurustest/components.go
+	taurustestPath := filepath.Join(g.projectPath, "internal", "taurustest")
+	if err := components.GenerateComponentTest(selectedComponents, taurustestPath, filepath.Base(g.projectPath)); err != nil {
+		return fmt.Errorf("生成 taurustest components.go 失败: %v", err)
+	}
+
 	// 执行 go mod tidy
 	tidyCmd := exec.Command("go", "mod", "tidy")
 	tidyCmd.Dir = g.projectPath
@@ -172,9 +178,11 @@ func (g *ProjectGenerator) generateComponentWire(componentWriePath string) error
 	}
 
 	// 对wire.go 文件执行 go fmt
-	fmtCmd := exec.Command("go", "fmt", filepath.Join(componentWriePath, "wire.go"))
-	if output, err := fmtCmd.CombinedOutput(); err != nil {
-		return fmt.Errorf("执行 go fmt 失败: %v\n输出: %s", err, output)
+	for _, file := range []string{filepath.Join(componentWriePath, "wire.go"), filepath.Join(taurustestPath, "components.go")} {
+		fmtCmd := exec.Command("go", "fmt", file)
+		if output, err := fmtCmd.CombinedOutput(); err != nil {
+			return fmt.Errorf("执行 go fmt 失败: %v\n输出: %s", err, output)
+		}
 	}
 
 	// 执行 wire 命令生成实现
@@ -0,0 +1,410 @@
+package graph
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/stones-hub/taurus-pro-core/pkg/project"
+	"github.com/stones-hub/taurus-pro-core/pkg/scanner"
+)
+
+// sourceDirs 参与扫描的项目目录，app 下是 provider，pkg 下的中间件等同样可能访问全局容器
+var sourceDirs = []string{"app", "pkg"}
+
+// packageInfo 单个Go包的解析结果
+type packageInfo struct {
+	name    string                   // 包名
+	files   []*ast.File              // 包内所有文件
+	imports map[*ast.File][]string   // 每个文件导入的项目内包
+	globals map[string]bool          // 包内直接访问的 taurus.Container 字段
+	funcs   map[string]*ast.FuncDecl // 包级函数
+}
+
+// appScanner 扫描应用代码并构建应用依赖图
+type appScanner struct {
+	projectRoot string
+	moduleName  string
+	packages    map[string]*packageInfo
+	components  map[string]string // 组件类型 -> 节点ID
+	componentID map[string]bool
+	reach       map[string]map[string]string
+}
+
+// BuildAppGraph 扫描 app 目录下的 provider set 并构建应用依赖图
+// components 为组件依赖图，用于将应用中对组件类型或全局容器的引用连接到组件节点
+func BuildAppGraph(projectRoot string, components *Graph) (*Graph, error) {
+	projectRoot, err := filepath.Abs(projectRoot)
+	if err != nil {
+		return nil, fmt.Errorf("获取项目绝对路径失败: %v", err)
+	}
+
+	moduleName, err := project.GetModuleName(projectRoot)
+	if err != nil {
+		return nil, err
+	}
+
+	s := &appScanner{
+		projectRoot: projectRoot,
+		moduleName:  moduleName,
+		packages:    make(map[string]*packageInfo),
+		components:  make(map[string]string),
+		componentID: make(map[string]bool),
+		reach:       make(map[string]map[string]string),
+	}
+	for _, node := range components.Nodes {
+		s.components[node.Type] = node.ID
+		s.components[strings.TrimPrefix(node.Type, "*")] = node.ID
+		s.componentID[node.ID] = true
+	}
+
+	for _, dir := range sourceDirs {
+		if err := s.parseDir(filepath.Join(projectRoot, dir)); err != nil {
+			return nil, err
+		}
+	}
+
+	sc := scanner.NewScanner(projectRoot, moduleName)
+	if err := sc.ScanDir(filepath.Join(projectRoot, "app")); err != nil {
+		return nil, fmt.Errorf("扫描 provider set 失败: %v", err)
+	}
+	sets := sc.GetProviderSets()
+
+	g := New()
+	for _, set := range sets {
+		g.AddNode(&Node{
+			ID:       appNodeID(set.PkgPath, set.StructType),
+			Kind:     NodeKindApp,
+			Type:     "*" + appNodeID(set.PkgPath, set.StructType),
+			Provider: set.Name,
+			Package:  moduleName + "/" + set.PkgPath,
+		})
+	}
+
+	for _, set := range sets {
+		pkg, ok := s.packages[set.PkgPath]
+		if !ok {
+			continue
+		}
+		from := appNodeID(set.PkgPath, set.StructType)
+		s.linkProviderSet(g, pkg, set, from)
+	}
+
+	return g, nil
+}
+
+// appNodeID 返回应用 provider 对应的节点ID，如 service.UserService
+func appNodeID(pkgPath, structType string) string {
+	return filepath.Base(pkgPath) + "." + structType
+}
+
+// parseDir 解析目录下所有Go文件，记录导入关系与全局容器访问情况
+func (s *appScanner) parseDir(root string) error {
+	if _, err := os.Stat(root); os.IsNotExist(err) {
+		return nil
+	}
+
+	return filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() || !strings.HasSuffix(path, ".go") ||
+			strings.HasSuffix(path, "_test.go") ||
+			info.Name() == "wire.go" || info.Name() == "wire_gen.go" {
+			return nil
+		}
+
+		file, err := parser.ParseFile(token.NewFileSet(), path, nil, 0)
+		if err != nil {
+			return fmt.Errorf("failed to parse file %s: %v", path, err)
+		}
+
+		rel, err := filepath.Rel(s.projectRoot, filepath.Dir(path))
+		if err != nil {
+			return err
+		}
+		rel = filepath.ToSlash(rel)
+
+		pkg, ok := s.packages[rel]
+		if !ok {
+			pkg = &packageInfo{
+				name:    file.Name.Name,
+				imports: make(map[*ast.File][]string),
+				globals: make(map[string]bool),
+				funcs:   make(map[string]*ast.FuncDecl),
+			}
+			s.packages[rel] = pkg
+		}
+		pkg.files = append(pkg.files, file)
+
+		for _, imp := range file.Imports {
+			path, _ := strconv.Unquote(imp.Path.Value)
+			if strings.HasPrefix(path, s.moduleName+"/") {
+				pkg.imports[file] = append(pkg.imports[file], strings.TrimPrefix(path, s.moduleName+"/"))
+			}
+		}
+
+		for field := range containerRefs(file) {
+			pkg.globals[field] = true
+		}
+
+		for _, decl := range file.Decls {
+			if fn, ok := decl.(*ast.FuncDecl); ok && fn.Recv == nil {
+				pkg.funcs[fn.Name.Name] = fn
+			}
+		}
+		return nil
+	})
+}
+
+// linkProviderSet 根据 provider set 的构造方式添加依赖边
+func (s *appScanner) linkProviderSet(g *Graph, pkg *packageInfo, set scanner.ProviderSetInfo, from string) {
+	deps := make([]string, 0)
+	scope := make([]ast.Node, 0) // 属于该 provider 的代码，用于查找全局容器访问
+	files := make([]*ast.File, 0)
+
+	for _, file := range pkg.files {
+		owned := false
+		for _, decl := range file.Decls {
+			switch d := decl.(type) {
+			case *ast.GenDecl:
+				for _, spec := range d.Specs {
+					switch sp := spec.(type) {
+					case *ast.TypeSpec:
+						if sp.Name.Name == set.StructType {
+							owned = true
+						}
+					case *ast.ValueSpec:
+						for i, name := range sp.Names {
+							if name.Name == set.Name && i < len(sp.Values) {
+								deps = append(deps, s.providerDeps(pkg, sp.Values[i], &scope)...)
+							}
+						}
+					}
+				}
+			case *ast.FuncDecl:
+				if receiverName(d) == set.StructType {
+					scope = append(scope, d)
+					owned = true
+				}
+			}
+		}
+		if owned {
+			files = append(files, file)
+		}
+	}
+
+	for _, dep := range deps {
+		if to := s.resolve(g, pkg, dep); to != "" && to != from {
+			g.AddEdge(&Edge{From: from, To: to, Via: EdgeViaField})
+		}
+	}
+
+	// 直接访问全局容器
+	direct := make(map[string]bool)
+	for _, node := range scope {
+		for field := range containerRefs(node) {
+			direct[field] = true
+		}
+	}
+	for _, field := range sortedKeys(direct) {
+		s.addGlobalEdge(g, from, field, "")
+	}
+
+	// 通过导入的项目包间接访问全局容器
+	for _, file := range files {
+		for _, imp := range pkg.imports[file] {
+			reach := s.reachable(imp)
+			for _, field := range sortedKeys(toSet(reach)) {
+				if !direct[field] {
+					s.addGlobalEdge(g, from, field, "via "+reach[field])
+				}
+			}
+		}
+	}
+}
+
+// providerDeps 解析 wire.NewSet 的参数，返回依赖的类型列表
+func (s *appScanner) providerDeps(pkg *packageInfo, expr ast.Expr, scope *[]ast.Node) []string {
+	call, ok := expr.(*ast.CallExpr)
+	if !ok {
+		return nil
+	}
+
+	deps := make([]string, 0)
+	for _, arg := range call.Args {
+		switch a := arg.(type) {
+		case *ast.Ident:
+			// 构造函数，如 wire.NewSet(NewUserService)
+			if fn, ok := pkg.funcs[a.Name]; ok {
+				deps = append(deps, fieldTypes(fn.Type.Params)...)
+				*scope = append(*scope, fn)
+			}
+		case *ast.CallExpr:
+			// wire.Struct(new(UserController), "*")
+			if sel, ok := a.Fun.(*ast.SelectorExpr); ok && sel.Sel.Name == "Struct" && len(a.Args) > 0 {
+				deps = append(deps, s.structFieldDeps(pkg, a)...)
+			}
+		}
+	}
+	return deps
+}
+
+// structFieldDeps 返回 wire.Struct 注入字段的类型
+func (s *appScanner) structFieldDeps(pkg *packageInfo, call *ast.CallExpr) []string {
+	newCall, ok := call.Args[0].(*ast.CallExpr)
+	if !ok || len(newCall.Args) != 1 {
+		return nil
+	}
+	ident, ok := newCall.Args[0].(*ast.Ident)
+	if !ok {
+		return nil
+	}
+
+	wanted := make(map[string]bool)
+	for _, arg := range call.Args[1:] {
+		if lit, ok := arg.(*ast.BasicLit); ok {
+			name, _ := strconv.Unquote(lit.Value)
+			wanted[name] = true
+		}
+	}
+
+	deps := make([]string, 0)
+	for _, file := range pkg.files {
+		ast.Inspect(file, func(n ast.Node) bool {
+			spec, ok := n.(*ast.TypeSpec)
+			if !ok || spec.Name.Name != ident.Name {
+				return true
+			}
+			st, ok := spec.Type.(*ast.StructType)
+			if !ok {
+				return false
+			}
+			for _, field := range st.Fields.List {
+				include := wanted["*"]
+				for _, name := range field.Names {
+					include = include || wanted[name.Name]
+				}
+				if include {
+					deps = append(deps, types.ExprString(field.Type))
+				}
+			}
+			return false
+		})
+	}
+	return deps
+}
+
+// resolve 将依赖类型解析为图中的节点ID
+func (s *appScanner) resolve(g *Graph, pkg *packageInfo, typ string) string {
+	if id, ok := s.components[typ]; ok {
+		return id
+	}
+
+	name := strings.TrimPrefix(typ, "*")
+	if !strings.Contains(name, ".") {
+		name = pkg.name + "." + name
+	}
+	if _, ok := g.Node(name); ok {
+		return name
+	}
+	return ""
+}
+
+// addGlobalEdge 添加通过全局容器访问组件的依赖边
+func (s *appScanner) addGlobalEdge(g *Graph, from, field, note string) {
+	to := ComponentNodeID(field)
+	if !s.componentID[to] {
+		return
+	}
+	g.AddEdge(&Edge{From: from, To: to, Via: EdgeViaGlobal, Note: note})
+}
+
+// reachable 返回从某个项目包出发(含其传递导入)能访问到的全局容器字段，值为直接访问该字段的包
+func (s *appScanner) reachable(dir string) map[string]string {
+	if result, ok := s.reach[dir]; ok {
+		return result
+	}
+
+	result := make(map[string]string)
+	// 先占位，避免包之间的循环导入导致无限递归
+	s.reach[dir] = result
+
+	pkg, ok := s.packages[dir]
+	if !ok {
+		return result
+	}
+	for field := range pkg.globals {
+		result[field] = dir
+	}
+	for _, file := range pkg.files {
+		for _, imp := range pkg.imports[file] {
+			reach := s.reachable(imp)
+			for _, field := range sortedKeys(toSet(reach)) {
+				if _, exists := result[field]; !exists {
+					result[field] = reach[field]
+				}
+			}
+		}
+	}
+	return result
+}
+
+// containerRefs 查找 taurus.Container.<Field> 形式的全局容器访问
+func containerRefs(node ast.Node) map[string]bool {
+	refs := make(map[string]bool)
+	ast.Inspect(node, func(n ast.Node) bool {
+		sel, ok := n.(*ast.SelectorExpr)
+		if !ok {
+			return true
+		}
+		inner, ok := sel.X.(*ast.SelectorExpr)
+		if !ok || inner.Sel.Name != "Container" {
+			return true
+		}
+		if ident, ok := inner.X.(*ast.Ident); ok && ident.Name == "taurus" {
+			refs[sel.Sel.Name] = true
+		}
+		return true
+	})
+	return refs
+}
+
+// receiverName 返回方法接收者的类型名称
+func receiverName(fn *ast.FuncDecl) string {
+	if fn.Recv == nil || len(fn.Recv.List) == 0 {
+		return ""
+	}
+	typ := fn.Recv.List[0].Type
+	if star, ok := typ.(*ast.StarExpr); ok {
+		typ = star.X
+	}
+	if ident, ok := typ.(*ast.Ident); ok {
+		return ident.Name
+	}
+	return ""
+}
+
+func toSet(m map[string]string) map[string]bool {
+	set := make(map[string]bool, len(m))
+	for k := range m {
+		set[k] = true
+	}
+	return set
+}
+
+func sortedKeys(m map[string]bool) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
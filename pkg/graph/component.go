@@ -0,0 +1,242 @@
+package graph
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/template"
+
+	ctypes "github.com/stones-hub/taurus-pro-core/pkg/components/types"
+)
+
+// fixedProviders 配置组件、可重载的配置、生命周期管理器、健康检查、埋点注册表、调试服务与配置重载器在 internal/taurus/wire.go 中是固定生成的，
+// 不属于任何 types.Wire，按 Components 的字段名对应 Provider 函数名
+// 不同版本生成的项目包含的固定组件不同，只有项目中定义了 Provider 的组件才加入依赖图
+var fixedProviders = []struct {
+	field    string
+	provider string
+}{
+	{"Config", "ProvideConfigComponent"},
+	{"ConfigAccessor", "ProvideConfigAccessorComponent"},
+	{"Lifecycle", "ProvideLifecycleComponent"},
+	{"Health", "ProvideHealthComponent"},
+	{"Instrument", "ProvideInstrumentComponent"},
+	{"Debug", "ProvideDebugComponent"},
+	{"Reload", "ProvideReloadComponent"},
+}
+
+// ComponentNodeID 返回组件字段对应的节点ID
+func ComponentNodeID(field string) string {
+	return "taurus." + field
+}
+
+// BuildComponentGraph 根据项目 internal/taurus 中固定生成的组件与组件的 types.Wire 定义构建组件依赖图
+// provider 的参数类型与其他组件的 Type 相同即视为依赖关系
+func BuildComponentGraph(projectRoot string, components []ctypes.Component) (*Graph, error) {
+	g := New()
+	fixed, params, err := fixedNodes(projectRoot)
+	if err != nil {
+		return nil, err
+	}
+	for _, node := range fixed {
+		g.AddNode(node)
+	}
+
+	for _, comp := range components {
+		for _, wire := range comp.Wire {
+			id := ComponentNodeID(wire.Name)
+			g.AddNode(&Node{
+				ID:       id,
+				Kind:     NodeKindComponent,
+				Type:     wire.Type,
+				Provider: wire.ProviderName,
+				Package:  comp.Package,
+			})
+			if params[id], err = providerParams(wire); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	// 类型 -> 节点ID
+	producers := make(map[string]string)
+	for _, node := range g.Nodes {
+		producers[node.Type] = node.ID
+	}
+
+	for _, node := range g.Nodes {
+		for _, param := range params[node.ID] {
+			if to, ok := producers[param]; ok {
+				g.AddEdge(&Edge{From: node.ID, To: to, Via: EdgeViaParam})
+			}
+		}
+	}
+
+	return g, nil
+}
+
+// fixedNodes 解析项目 internal/taurus 中的 Go 文件（不含测试文件），返回项目定义了 Provider 的固定组件节点，
+// 以及按节点ID索引的 Provider 参数类型；节点的类型为 Provider 的第一个返回值
+func fixedNodes(projectRoot string) ([]*Node, map[string][]string, error) {
+	files, err := filepath.Glob(filepath.Join(projectRoot, "internal", "taurus", "*.go"))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	funcs := make(map[string]*ast.FuncDecl)
+	for _, path := range files {
+		if strings.HasSuffix(path, "_test.go") {
+			continue
+		}
+		file, err := parser.ParseFile(token.NewFileSet(), path, nil, 0)
+		if err != nil {
+			return nil, nil, fmt.Errorf("解析 %s 失败: %v", path, err)
+		}
+		for _, decl := range file.Decls {
+			if fn, ok := decl.(*ast.FuncDecl); ok && fn.Recv == nil {
+				funcs[fn.Name.Name] = fn
+			}
+		}
+	}
+
+	nodes := make([]*Node, 0, len(fixedProviders))
+	params := make(map[string][]string)
+	for _, fixed := range fixedProviders {
+		fn, ok := funcs[fixed.provider]
+		if !ok {
+			continue
+		}
+		results := fieldTypes(fn.Type.Results)
+		if len(results) == 0 {
+			return nil, nil, fmt.Errorf("Provider 函数 %s 没有返回值", fixed.provider)
+		}
+		node := &Node{
+			ID:       ComponentNodeID(fixed.field),
+			Kind:     NodeKindComponent,
+			Type:     results[0],
+			Provider: fixed.provider,
+		}
+		nodes = append(nodes, node)
+		params[node.ID] = fieldTypes(fn.Type.Params)
+	}
+	return nodes, params, nil
+}
+
+// providerParams 渲染 Provider 模板并解析出参数类型列表
+func providerParams(wire *ctypes.Wire) ([]string, error) {
+	tmpl, err := template.New("provider").Parse(wire.Provider)
+	if err != nil {
+		return nil, fmt.Errorf("解析 %s 的 Provider 模板失败: %v", wire.ProviderName, err)
+	}
+
+	var src strings.Builder
+	src.WriteString("package taurus\n\n")
+	if err := tmpl.Execute(&src, wire); err != nil {
+		return nil, fmt.Errorf("执行 %s 的 Provider 模板失败: %v", wire.ProviderName, err)
+	}
+
+	file, err := parser.ParseFile(token.NewFileSet(), wire.ProviderName+".go", src.String(), 0)
+	if err != nil {
+		return nil, fmt.Errorf("解析 %s 的 Provider 函数失败: %v", wire.ProviderName, err)
+	}
+
+	for _, decl := range file.Decls {
+		fn, ok := decl.(*ast.FuncDecl)
+		if !ok || fn.Name.Name != wire.ProviderName {
+			continue
+		}
+		return fieldTypes(fn.Type.Params), nil
+	}
+
+	return nil, fmt.Errorf("未找到 Provider 函数: %s", wire.ProviderName)
+}
+
+// fieldTypes 返回字段列表中每个字段(含同类型多名字段)的类型字符串
+func fieldTypes(fields *ast.FieldList) []string {
+	result := make([]string, 0)
+	if fields == nil {
+		return result
+	}
+	for _, field := range fields.List {
+		typ := types.ExprString(field.Type)
+		count := len(field.Names)
+		if count == 0 {
+			count = 1
+		}
+		for i := 0; i < count; i++ {
+			result = append(result, typ)
+		}
+	}
+	return result
+}
+
+// SelectComponents 根据项目 internal/taurus/wire.go 中 Components 结构体的字段筛选出项目实际使用的组件
+// wire.go 不存在时返回全部组件
+func SelectComponents(projectRoot string, all []ctypes.Component) ([]ctypes.Component, error) {
+	wirePath := filepath.Join(projectRoot, "internal", "taurus", "wire.go")
+	if _, err := os.Stat(wirePath); os.IsNotExist(err) {
+		return all, nil
+	}
+
+	file, err := parser.ParseFile(token.NewFileSet(), wirePath, nil, 0)
+	if err != nil {
+		return nil, fmt.Errorf("解析 %s 失败: %v", wirePath, err)
+	}
+
+	fields := make(map[string]bool)
+	ast.Inspect(file, func(n ast.Node) bool {
+		spec, ok := n.(*ast.TypeSpec)
+		if !ok || spec.Name.Name != "Components" {
+			return true
+		}
+		if st, ok := spec.Type.(*ast.StructType); ok {
+			for _, field := range st.Fields.List {
+				for _, name := range field.Names {
+					fields[name.Name] = true
+				}
+			}
+		}
+		return false
+	})
+
+	selected := make([]ctypes.Component, 0)
+	for _, comp := range all {
+		wires := make([]*ctypes.Wire, 0, len(comp.Wire))
+		for _, wire := range comp.Wire {
+			if fields[wire.Name] {
+				wires = append(wires, wire)
+			}
+		}
+		if len(wires) > 0 {
+			comp.Wire = wires
+			selected = append(selected, comp)
+		}
+	}
+	return selected, nil
+}
+
+// Build 构建项目完整的依赖图：组件依赖图 + 应用依赖图
+func Build(projectRoot string, all []ctypes.Component) (*Graph, error) {
+	selected, err := SelectComponents(projectRoot, all)
+	if err != nil {
+		return nil, err
+	}
+
+	g, err := BuildComponentGraph(projectRoot, selected)
+	if err != nil {
+		return nil, err
+	}
+
+	app, err := BuildAppGraph(projectRoot, g)
+	if err != nil {
+		return nil, err
+	}
+
+	g.Merge(app)
+	return g, nil
+}
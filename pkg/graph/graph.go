@@ -0,0 +1,319 @@
+package graph
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+)
+
+// NodeKind 节点类型
+type NodeKind string
+
+const (
+	NodeKindComponent NodeKind = "component" // 组件注入器(internal/taurus)中的节点
+	NodeKindApp       NodeKind = "app"       // 应用注入器(app)中的节点
+)
+
+// EdgeVia 依赖关系的来源
+type EdgeVia string
+
+const (
+	EdgeViaParam  EdgeVia = "param"  // provider 函数参数
+	EdgeViaField  EdgeVia = "field"  // wire.Struct 注入的结构体字段
+	EdgeViaGlobal EdgeVia = "global" // 通过全局 taurus.Container 访问
+)
+
+// Format 输出格式
+type Format string
+
+const (
+	FormatDOT     Format = "dot"
+	FormatMermaid Format = "mermaid"
+	FormatJSON    Format = "json"
+)
+
+// Node 依赖图中的节点
+type Node struct {
+	ID       string   `json:"id"`                 // 唯一标识，如 taurus.Http、service.UserService
+	Kind     NodeKind `json:"kind"`               // 节点类型
+	Type     string   `json:"type"`               // 节点对应的Go类型
+	Provider string   `json:"provider,omitempty"` // 提供者名称
+	Package  string   `json:"package,omitempty"`  // 所在包路径
+}
+
+// Edge 依赖图中的边，From 依赖 To
+type Edge struct {
+	From string  `json:"from"`
+	To   string  `json:"to"`
+	Via  EdgeVia `json:"via"`
+	Note string  `json:"note,omitempty"` // 补充说明，如经由哪个包访问了全局容器
+}
+
+// Graph 依赖图
+type Graph struct {
+	Nodes []*Node `json:"nodes"`
+	Edges []*Edge `json:"edges"`
+
+	index map[string]*Node
+	edges map[string]*Edge
+}
+
+// New 创建空的依赖图
+func New() *Graph {
+	return &Graph{
+		Nodes: make([]*Node, 0),
+		Edges: make([]*Edge, 0),
+		index: make(map[string]*Node),
+		edges: make(map[string]*Edge),
+	}
+}
+
+// AddNode 添加节点，已存在的节点会被忽略
+func (g *Graph) AddNode(node *Node) {
+	if _, exists := g.index[node.ID]; exists {
+		return
+	}
+	g.index[node.ID] = node
+	g.Nodes = append(g.Nodes, node)
+}
+
+// Node 根据ID获取节点
+func (g *Graph) Node(id string) (*Node, bool) {
+	node, ok := g.index[id]
+	return node, ok
+}
+
+// AddEdge 添加边，同一对节点只保留第一条边
+func (g *Graph) AddEdge(edge *Edge) {
+	key := edge.From + "->" + edge.To
+	if _, exists := g.edges[key]; exists {
+		return
+	}
+	g.edges[key] = edge
+	g.Edges = append(g.Edges, edge)
+}
+
+// Merge 合并另一个依赖图
+func (g *Graph) Merge(other *Graph) {
+	for _, node := range other.Nodes {
+		g.AddNode(node)
+	}
+	for _, edge := range other.Edges {
+		g.AddEdge(edge)
+	}
+}
+
+// Cycles 查找依赖图中的循环依赖，每个循环按强连通分量返回，分量内节点已排序
+func (g *Graph) Cycles() [][]string {
+	adjacency := make(map[string][]string)
+	selfLoop := make(map[string]bool)
+	for _, edge := range g.Edges {
+		adjacency[edge.From] = append(adjacency[edge.From], edge.To)
+		if edge.From == edge.To {
+			selfLoop[edge.From] = true
+		}
+	}
+
+	// Tarjan 强连通分量算法
+	var (
+		index   int
+		stack   []string
+		onStack = make(map[string]bool)
+		indices = make(map[string]int)
+		lowLink = make(map[string]int)
+		cycles  = make([][]string, 0)
+	)
+
+	var strongConnect func(id string)
+	strongConnect = func(id string) {
+		indices[id] = index
+		lowLink[id] = index
+		index++
+		stack = append(stack, id)
+		onStack[id] = true
+
+		for _, next := range adjacency[id] {
+			if _, visited := indices[next]; !visited {
+				strongConnect(next)
+				lowLink[id] = min(lowLink[id], lowLink[next])
+			} else if onStack[next] {
+				lowLink[id] = min(lowLink[id], indices[next])
+			}
+		}
+
+		if lowLink[id] != indices[id] {
+			return
+		}
+
+		component := make([]string, 0)
+		for {
+			top := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			onStack[top] = false
+			component = append(component, top)
+			if top == id {
+				break
+			}
+		}
+
+		if len(component) > 1 || selfLoop[id] {
+			sort.Strings(component)
+			cycles = append(cycles, component)
+		}
+	}
+
+	for _, node := range g.Nodes {
+		if _, visited := indices[node.ID]; !visited {
+			strongConnect(node.ID)
+		}
+	}
+
+	sort.Slice(cycles, func(i, j int) bool {
+		return cycles[i][0] < cycles[j][0]
+	})
+	return cycles
+}
+
+// Write 按指定格式输出依赖图
+func (g *Graph) Write(w io.Writer, format Format) error {
+	switch format {
+	case FormatDOT:
+		return g.writeDOT(w)
+	case FormatMermaid:
+		return g.writeMermaid(w)
+	case FormatJSON:
+		return g.writeJSON(w)
+	default:
+		return fmt.Errorf("不支持的输出格式: %s", format)
+	}
+}
+
+// writeDOT 输出 Graphviz DOT 格式
+func (g *Graph) writeDOT(w io.Writer) error {
+	var b strings.Builder
+	cyclic := g.cyclicNodes()
+
+	b.WriteString("digraph taurus {\n")
+	b.WriteString("\trankdir=LR;\n")
+	b.WriteString("\tnode [shape=box, fontname=\"Helvetica\"];\n")
+
+	for _, kind := range []NodeKind{NodeKindComponent, NodeKindApp} {
+		fmt.Fprintf(&b, "\n\tsubgraph \"cluster_%s\" {\n", kind)
+		fmt.Fprintf(&b, "\t\tlabel=%q;\n", clusterLabel(kind))
+		for _, node := range g.Nodes {
+			if node.Kind != kind {
+				continue
+			}
+			attrs := fmt.Sprintf("label=%q", node.ID+"\n"+node.Type)
+			if cyclic[node.ID] {
+				attrs += ", color=red"
+			}
+			fmt.Fprintf(&b, "\t\t%q [%s];\n", node.ID, attrs)
+		}
+		b.WriteString("\t}\n")
+	}
+
+	b.WriteString("\n")
+	for _, edge := range g.Edges {
+		attrs := make([]string, 0, 2)
+		if edge.Via == EdgeViaGlobal {
+			attrs = append(attrs, "style=dashed")
+		}
+		if edge.Note != "" {
+			attrs = append(attrs, fmt.Sprintf("label=%q", edge.Note))
+		}
+		if len(attrs) > 0 {
+			fmt.Fprintf(&b, "\t%q -> %q [%s];\n", edge.From, edge.To, strings.Join(attrs, ", "))
+		} else {
+			fmt.Fprintf(&b, "\t%q -> %q;\n", edge.From, edge.To)
+		}
+	}
+	b.WriteString("}\n")
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// writeMermaid 输出 Mermaid flowchart 格式，可直接嵌入 markdown 文档
+func (g *Graph) writeMermaid(w io.Writer) error {
+	var b strings.Builder
+	ids := make(map[string]string, len(g.Nodes))
+	for i, node := range g.Nodes {
+		ids[node.ID] = fmt.Sprintf("n%d", i)
+	}
+
+	b.WriteString("graph LR\n")
+	for _, kind := range []NodeKind{NodeKindComponent, NodeKindApp} {
+		fmt.Fprintf(&b, "\tsubgraph %s[\"%s\"]\n", kind, clusterLabel(kind))
+		for _, node := range g.Nodes {
+			if node.Kind == kind {
+				fmt.Fprintf(&b, "\t\t%s[\"%s<br/>%s\"]\n", ids[node.ID], mermaidEscape(node.ID), mermaidEscape(node.Type))
+			}
+		}
+		b.WriteString("\tend\n")
+	}
+
+	for _, edge := range g.Edges {
+		arrow := "-->"
+		if edge.Via == EdgeViaGlobal {
+			arrow = "-.->"
+		}
+		if edge.Note != "" {
+			fmt.Fprintf(&b, "\t%s %s|%s| %s\n", ids[edge.From], arrow, mermaidEscape(edge.Note), ids[edge.To])
+		} else {
+			fmt.Fprintf(&b, "\t%s %s %s\n", ids[edge.From], arrow, ids[edge.To])
+		}
+	}
+
+	cyclic := g.cyclicNodes()
+	if len(cyclic) > 0 {
+		b.WriteString("\tclassDef cycle stroke:#f00,stroke-width:2px\n")
+		for _, node := range g.Nodes {
+			if cyclic[node.ID] {
+				fmt.Fprintf(&b, "\tclass %s cycle\n", ids[node.ID])
+			}
+		}
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// writeJSON 输出 JSON 格式，附带检测到的循环依赖
+func (g *Graph) writeJSON(w io.Writer) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(struct {
+		Nodes  []*Node    `json:"nodes"`
+		Edges  []*Edge    `json:"edges"`
+		Cycles [][]string `json:"cycles"`
+	}{
+		Nodes:  g.Nodes,
+		Edges:  g.Edges,
+		Cycles: g.Cycles(),
+	})
+}
+
+// cyclicNodes 返回所有处于循环依赖中的节点
+func (g *Graph) cyclicNodes() map[string]bool {
+	result := make(map[string]bool)
+	for _, cycle := range g.Cycles() {
+		for _, id := range cycle {
+			result[id] = true
+		}
+	}
+	return result
+}
+
+func clusterLabel(kind NodeKind) string {
+	if kind == NodeKindComponent {
+		return "components (internal/taurus)"
+	}
+	return "app (app/wire.go)"
+}
+
+func mermaidEscape(s string) string {
+	return strings.NewReplacer(`"`, "#quot;", "<", "#lt;", ">", "#gt;", "|", "#124;").Replace(s)
+}
@@ -0,0 +1,413 @@
+package graph
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"testing"
+
+	ctypes "github.com/stones-hub/taurus-pro-core/pkg/components/types"
+)
+
+// TestCycles 测试循环依赖检测
+func TestCycles(t *testing.T) {
+	g := New()
+	for _, id := range []string{"taurus.Config", "service.A", "service.B", "controller.C"} {
+		g.AddNode(&Node{ID: id, Kind: NodeKindApp})
+	}
+	g.AddEdge(&Edge{From: "service.A", To: "service.B", Via: EdgeViaField})
+	g.AddEdge(&Edge{From: "service.B", To: "service.A", Via: EdgeViaField})
+	g.AddEdge(&Edge{From: "controller.C", To: "service.A", Via: EdgeViaField})
+	g.AddEdge(&Edge{From: "service.A", To: "taurus.Config", Via: EdgeViaGlobal})
+
+	cycles := g.Cycles()
+	expected := [][]string{{"service.A", "service.B"}}
+	if !reflect.DeepEqual(cycles, expected) {
+		t.Errorf("Expected cycles %v, got %v", expected, cycles)
+	}
+
+	// 没有回边时不应存在循环
+	acyclic := New()
+	for _, node := range g.Nodes {
+		acyclic.AddNode(node)
+	}
+	acyclic.AddEdge(&Edge{From: "controller.C", To: "service.A", Via: EdgeViaField})
+	acyclic.AddEdge(&Edge{From: "service.A", To: "service.B", Via: EdgeViaField})
+	if cycles := acyclic.Cycles(); len(cycles) != 0 {
+		t.Errorf("Expected no cycles, got %v", cycles)
+	}
+}
+
+// newTestGraph 组件 taurus.Config 与互相依赖的 service.A、service.B，service.A 经由 pkg/helper 访问全局容器
+func newTestGraph() *Graph {
+	g := New()
+	g.AddNode(&Node{ID: "taurus.Config", Kind: NodeKindComponent, Type: "*config.Config", Provider: "ProvideConfigComponent"})
+	g.AddNode(&Node{ID: "service.A", Kind: NodeKindApp, Type: "*service.A", Provider: "ASet"})
+	g.AddNode(&Node{ID: "service.B", Kind: NodeKindApp, Type: "*service.B", Provider: "BSet"})
+	g.AddEdge(&Edge{From: "service.A", To: "service.B", Via: EdgeViaField})
+	g.AddEdge(&Edge{From: "service.B", To: "service.A", Via: EdgeViaField})
+	g.AddEdge(&Edge{From: "service.A", To: "taurus.Config", Via: EdgeViaGlobal, Note: "via pkg/helper"})
+	// 同一对节点只保留第一条边
+	g.AddEdge(&Edge{From: "service.A", To: "service.B", Via: EdgeViaGlobal})
+	return g
+}
+
+// TestWriteDOT 测试 DOT 输出：按节点类型分组，循环依赖标红，全局容器访问为虚线
+func TestWriteDOT(t *testing.T) {
+	var buf bytes.Buffer
+	if err := newTestGraph().Write(&buf, FormatDOT); err != nil {
+		t.Fatalf("Failed to write dot: %v", err)
+	}
+	expected := `digraph taurus {
+	rankdir=LR;
+	node [shape=box, fontname="Helvetica"];
+
+	subgraph "cluster_component" {
+		label="components (internal/taurus)";
+		"taurus.Config" [label="taurus.Config\n*config.Config"];
+	}
+
+	subgraph "cluster_app" {
+		label="app (app/wire.go)";
+		"service.A" [label="service.A\n*service.A", color=red];
+		"service.B" [label="service.B\n*service.B", color=red];
+	}
+
+	"service.A" -> "service.B";
+	"service.B" -> "service.A";
+	"service.A" -> "taurus.Config" [style=dashed, label="via pkg/helper"];
+}
+`
+	if buf.String() != expected {
+		t.Errorf("Expected dot:\n%s\ngot:\n%s", expected, buf.String())
+	}
+}
+
+// TestWriteMermaid 测试 Mermaid 输出：节点按顺序编号，循环依赖使用 cycle 样式，全局容器访问为虚线
+func TestWriteMermaid(t *testing.T) {
+	var buf bytes.Buffer
+	if err := newTestGraph().Write(&buf, FormatMermaid); err != nil {
+		t.Fatalf("Failed to write mermaid: %v", err)
+	}
+	expected := `graph LR
+	subgraph component["components (internal/taurus)"]
+		n0["taurus.Config<br/>*config.Config"]
+	end
+	subgraph app["app (app/wire.go)"]
+		n1["service.A<br/>*service.A"]
+		n2["service.B<br/>*service.B"]
+	end
+	n1 --> n2
+	n2 --> n1
+	n1 -.->|via pkg/helper| n0
+	classDef cycle stroke:#f00,stroke-width:2px
+	class n1 cycle
+	class n2 cycle
+`
+	if buf.String() != expected {
+		t.Errorf("Expected mermaid:\n%s\ngot:\n%s", expected, buf.String())
+	}
+
+	// 没有循环依赖时不输出 cycle 样式，标签中的特殊字符需要转义
+	g := New()
+	g.AddNode(&Node{ID: "service.A", Kind: NodeKindApp, Type: "*service.A"})
+	g.AddNode(&Node{ID: "taurus.DbList", Kind: NodeKindComponent, Type: "map[string]*gorm.DB"})
+	g.AddEdge(&Edge{From: "service.A", To: "taurus.DbList", Via: EdgeViaGlobal, Note: `via "a|b" <c>`})
+	buf.Reset()
+	if err := g.Write(&buf, FormatMermaid); err != nil {
+		t.Fatalf("Failed to write mermaid: %v", err)
+	}
+	expected = `graph LR
+	subgraph component["components (internal/taurus)"]
+		n1["taurus.DbList<br/>map[string]*gorm.DB"]
+	end
+	subgraph app["app (app/wire.go)"]
+		n0["service.A<br/>*service.A"]
+	end
+	n0 -.->|via #quot;a#124;b#quot; #lt;c#gt;| n1
+`
+	if buf.String() != expected {
+		t.Errorf("Expected mermaid:\n%s\ngot:\n%s", expected, buf.String())
+	}
+}
+
+// TestWriteJSON 测试 JSON 输出附带循环依赖
+func TestWriteJSON(t *testing.T) {
+	var buf bytes.Buffer
+	if err := newTestGraph().Write(&buf, FormatJSON); err != nil {
+		t.Fatalf("Failed to write json: %v", err)
+	}
+	var decoded struct {
+		Nodes  []*Node    `json:"nodes"`
+		Edges  []*Edge    `json:"edges"`
+		Cycles [][]string `json:"cycles"`
+	}
+	if err := json.Unmarshal(buf.Bytes(), &decoded); err != nil {
+		t.Fatalf("Failed to decode json: %v", err)
+	}
+	g := newTestGraph()
+	if !reflect.DeepEqual(decoded.Nodes, g.Nodes) {
+		t.Errorf("Expected nodes %+v, got %+v", g.Nodes, decoded.Nodes)
+	}
+	if !reflect.DeepEqual(decoded.Edges, g.Edges) {
+		t.Errorf("Expected edges %+v, got %+v", g.Edges, decoded.Edges)
+	}
+	if expected := [][]string{{"service.A", "service.B"}}; !reflect.DeepEqual(decoded.Cycles, expected) {
+		t.Errorf("Expected cycles %v, got %v", expected, decoded.Cycles)
+	}
+
+	// 没有循环依赖时输出空数组而不是 null
+	buf.Reset()
+	if err := New().Write(&buf, FormatJSON); err != nil {
+		t.Fatalf("Failed to write json: %v", err)
+	}
+	if !bytes.Contains(buf.Bytes(), []byte(`"cycles": []`)) {
+		t.Errorf("Expected empty cycles, got %s", buf.String())
+	}
+
+	if err := g.Write(&buf, Format("svg")); err == nil {
+		t.Errorf("Expected error for unsupported format")
+	}
+}
+
+var testFiles = map[string]string{
+	"go.mod": "module demo\n\ngo 1.24\n",
+	"app/service/user_service.go": `package service
+
+import (
+	"demo/internal/taurus"
+
+	"github.com/google/wire"
+	"github.com/stones-hub/taurus-pro-config/pkg/config"
+)
+
+type UserService struct {
+	config *config.Config
+}
+
+var UserServiceSet = wire.NewSet(NewUserService)
+
+func NewUserService(components *taurus.Components, config *config.Config) *UserService {
+	return &UserService{config: config}
+}
+`,
+	"app/service/order_service.go": `package service
+
+import (
+	"demo/internal/taurus"
+
+	"github.com/google/wire"
+)
+
+type OrderService struct {
+	users *UserService
+}
+
+var OrderServiceSet = wire.NewSet(NewOrderService)
+
+func NewOrderService(users *UserService) *OrderService {
+	return &OrderService{users: users}
+}
+
+func (s *OrderService) Cache() {
+	_ = taurus.Container.Redis
+}
+`,
+	"app/service/order_service_test.go": `package service
+
+import (
+	"demo/internal/taurus"
+
+	"github.com/google/wire"
+)
+
+type FakeService struct{}
+
+var FakeServiceSet = wire.NewSet(wire.Struct(new(FakeService)))
+
+func (s *OrderService) testConfig() {
+	_ = taurus.Container.Config
+}
+`,
+	"app/controller/user_controller.go": `package controller
+
+import (
+	"demo/app/service"
+	"demo/pkg/helper"
+
+	"github.com/google/wire"
+)
+
+type UserController struct {
+	UserService *service.UserService
+	Orders      *service.OrderService
+}
+
+var UserControllerSet = wire.NewSet(wire.Struct(new(UserController), "UserService"))
+
+func (c *UserController) Get() {
+	_ = helper.DB()
+}
+`,
+	"pkg/helper/helper.go": `package helper
+
+import "demo/internal/taurus"
+
+func DB() any {
+	return taurus.Container.DbList
+}
+`,
+}
+
+// writeTestProject 在临时目录中创建测试项目
+func writeTestProject(t *testing.T) string {
+	t.Helper()
+	root := t.TempDir()
+	for name, content := range testFiles {
+		path := filepath.Join(root, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return root
+}
+
+// TestBuildAppGraph 测试扫描 app 下的 provider set：构造函数参数与 wire.Struct 字段、直接与经由导入包访问全局容器
+func TestBuildAppGraph(t *testing.T) {
+	components := New()
+	components.AddNode(&Node{ID: ComponentNodeID("Config"), Kind: NodeKindComponent, Type: "*config.Config"})
+	components.AddNode(&Node{ID: ComponentNodeID("Redis"), Kind: NodeKindComponent, Type: "*redisx.RedisClient"})
+	components.AddNode(&Node{ID: ComponentNodeID("DbList"), Kind: NodeKindComponent, Type: "map[string]*gorm.DB"})
+
+	g, err := BuildAppGraph(writeTestProject(t), components)
+	if err != nil {
+		t.Fatalf("Failed to build app graph: %v", err)
+	}
+
+	// 测试文件中的 provider set 不参与扫描
+	ids := make([]string, 0, len(g.Nodes))
+	for _, node := range g.Nodes {
+		ids = append(ids, node.ID)
+	}
+	sort.Strings(ids)
+	if expected := []string{"controller.UserController", "service.OrderService", "service.UserService"}; !reflect.DeepEqual(ids, expected) {
+		t.Fatalf("Expected nodes %v, got %v", expected, ids)
+	}
+	node, _ := g.Node("service.UserService")
+	expectedNode := &Node{ID: "service.UserService", Kind: NodeKindApp, Type: "*service.UserService", Provider: "UserServiceSet", Package: "demo/app/service"}
+	if !reflect.DeepEqual(node, expectedNode) {
+		t.Errorf("Expected node %+v, got %+v", expectedNode, node)
+	}
+
+	edges := make([]string, 0, len(g.Edges))
+	for _, edge := range g.Edges {
+		edges = append(edges, edge.From+" -> "+edge.To+" "+string(edge.Via)+" "+edge.Note)
+	}
+	sort.Strings(edges)
+	expected := []string{
+		// wire.Struct 只注入列出的字段，Orders 不是依赖
+		"controller.UserController -> service.UserService field ",
+		// 经由导入的包访问全局容器，service 包中 OrderService 的访问同样可达
+		"controller.UserController -> taurus.DbList global via pkg/helper",
+		"controller.UserController -> taurus.Redis global via app/service",
+		"service.OrderService -> service.UserService field ",
+		// 方法中直接访问全局容器，测试文件中的访问不计入
+		"service.OrderService -> taurus.Redis global ",
+		// 构造函数参数中的组件类型，*taurus.Components 不是组件节点
+		"service.UserService -> taurus.Config field ",
+	}
+	if !reflect.DeepEqual(edges, expected) {
+		t.Errorf("Expected edges:\n%v\ngot:\n%v", expected, edges)
+	}
+}
+
+// TestBuildComponentGraph 测试固定组件只在项目的 internal/taurus 定义了 Provider 时加入依赖图，依赖关系与 types.Wire 的组件相同按参数类型得到
+func TestBuildComponentGraph(t *testing.T) {
+	root := t.TempDir()
+	dir := filepath.Join(root, "internal", "taurus")
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	// 较早版本生成的项目没有健康检查、埋点注册表与配置重载器，测试文件中的 Provider 不计入
+	files := map[string]string{
+		"wire.go": `package taurus
+
+func ProvideConfigComponent(opts *ConfigOptions) (*config.Config, error) { return nil, nil }
+
+func ProvideConfigAccessorComponent(cfg *config.Config) *ConfigAccessor { return nil }
+
+func ProvideLifecycleComponent(cfg *config.Config) *LifecycleManager { return nil }
+
+func ProvideDebugComponent(cfg *config.Config, lc *LifecycleManager) *DebugServer { return nil }
+`,
+		"wire_test.go": `package taurus
+
+func ProvideReloadComponent(configs *ConfigAccessor) *ConfigReloader { return nil }
+`,
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	components := []ctypes.Component{{
+		Package: "github.com/stones-hub/taurus-pro-storage",
+		Wire: []*ctypes.Wire{{
+			Name:         "Redis",
+			Type:         "*redisx.RedisClient",
+			ProviderName: "ProvideRedisComponent",
+			Provider:     "func ProvideRedisComponent(cfg *config.Config, lc *LifecycleManager, rl *ConfigReloader) (*redisx.RedisClient, error) { return nil, nil }",
+		}},
+	}}
+	g, err := BuildComponentGraph(root, components)
+	if err != nil {
+		t.Fatalf("Failed to build component graph: %v", err)
+	}
+
+	ids := make([]string, 0, len(g.Nodes))
+	for _, node := range g.Nodes {
+		ids = append(ids, node.ID)
+	}
+	if expected := []string{"taurus.Config", "taurus.ConfigAccessor", "taurus.Lifecycle", "taurus.Debug", "taurus.Redis"}; !reflect.DeepEqual(ids, expected) {
+		t.Fatalf("Expected nodes %v, got %v", expected, ids)
+	}
+	node, _ := g.Node("taurus.Config")
+	expectedNode := &Node{ID: "taurus.Config", Kind: NodeKindComponent, Type: "*config.Config", Provider: "ProvideConfigComponent"}
+	if !reflect.DeepEqual(node, expectedNode) {
+		t.Errorf("Expected node %+v, got %+v", expectedNode, node)
+	}
+
+	edges := make([]string, 0, len(g.Edges))
+	for _, edge := range g.Edges {
+		edges = append(edges, edge.From+" -> "+edge.To)
+	}
+	sort.Strings(edges)
+	expected := []string{
+		"taurus.ConfigAccessor -> taurus.Config",
+		"taurus.Debug -> taurus.Config",
+		"taurus.Debug -> taurus.Lifecycle",
+		"taurus.Lifecycle -> taurus.Config",
+		// 项目中没有配置重载器，*ConfigReloader 不是依赖
+		"taurus.Redis -> taurus.Config",
+		"taurus.Redis -> taurus.Lifecycle",
+	}
+	if !reflect.DeepEqual(edges, expected) {
+		t.Errorf("Expected edges:\n%v\ngot:\n%v", expected, edges)
+	}
+
+	// 没有 internal/taurus 时只有 types.Wire 的组件
+	g, err = BuildComponentGraph(t.TempDir(), components)
+	if err != nil {
+		t.Fatalf("Failed to build component graph: %v", err)
+	}
+	if len(g.Nodes) != 1 || len(g.Edges) != 0 {
+		t.Errorf("Expected only taurus.Redis without edges, got %d nodes and %d edges", len(g.Nodes), len(g.Edges))
+	}
+}
@@ -0,0 +1,1152 @@
+package openapi
+
+import (
+	"go/ast"
+	"go/token"
+	"strconv"
+	"strings"
+)
+
+// maxInlineDepth 内联分析被调用方法的最大深度
+const maxInlineDepth = 4
+
+// statusCodes net/http 中常用状态码常量
+var statusCodes = map[string]int{
+	"StatusContinue":              100,
+	"StatusOK":                    200,
+	"StatusCreated":               201,
+	"StatusAccepted":              202,
+	"StatusNoContent":             204,
+	"StatusPartialContent":        206,
+	"StatusMovedPermanently":      301,
+	"StatusFound":                 302,
+	"StatusSeeOther":              303,
+	"StatusNotModified":           304,
+	"StatusTemporaryRedirect":     307,
+	"StatusPermanentRedirect":     308,
+	"StatusBadRequest":            400,
+	"StatusUnauthorized":          401,
+	"StatusPaymentRequired":       402,
+	"StatusForbidden":             403,
+	"StatusNotFound":              404,
+	"StatusMethodNotAllowed":      405,
+	"StatusNotAcceptable":         406,
+	"StatusRequestTimeout":        408,
+	"StatusConflict":              409,
+	"StatusGone":                  410,
+	"StatusPreconditionFailed":    412,
+	"StatusRequestEntityTooLarge": 413,
+	"StatusUnsupportedMediaType":  415,
+	"StatusUnprocessableEntity":   422,
+	"StatusTooManyRequests":       429,
+	"StatusInternalServerError":   500,
+	"StatusNotImplemented":        501,
+	"StatusBadGateway":            502,
+	"StatusServiceUnavailable":    503,
+	"StatusGatewayTimeout":        504,
+}
+
+// handlerInfo 从处理函数中分析出的接口信息
+type handlerInfo struct {
+	summary     string
+	description string
+	allowed     []string                   // r.Method == X 或 switch 中出现的方法
+	required    []string                   // r.Method != X 中出现的方法
+	query       []string                   // 查询参数
+	params      []Parameter                // binding.Bind 绑定的路径、查询与请求头参数
+	form        []string                   // 表单参数
+	body        *Schema                    // JSON 请求体
+	responses   map[int]map[string]*Schema // 状态码 -> 内容类型 -> schema
+	rawWrite    bool                       // 直接调用了 w.Write 或 fmt.Fprint
+}
+
+// methods 返回接口支持的 HTTP 方法
+func (h *handlerInfo) methods() []string {
+	switch {
+	case len(h.required) > 0:
+		return unique(h.required)
+	case len(h.allowed) > 0:
+		return unique(h.allowed)
+	case h.body != nil || len(h.form) > 0:
+		return []string{"POST"}
+	default:
+		return []string{"GET"}
+	}
+}
+
+// addResponse 记录响应，同一状态码与内容类型保留信息更完整的 schema
+func (h *handlerInfo) addResponse(status int, contentType string, schema *Schema) {
+	if h.responses[status] == nil {
+		h.responses[status] = make(map[string]*Schema)
+	}
+	if contentType == "" {
+		return
+	}
+	if existing, ok := h.responses[status][contentType]; ok && score(existing) >= score(schema) {
+		return
+	}
+	h.responses[status][contentType] = schema
+}
+
+// addBodyProperty 记录 httpx.ParseJson 读取的请求体字段
+func (h *handlerInfo) addBodyProperty(name string, schema *Schema) {
+	if h.body == nil {
+		h.body = &Schema{Type: "object"}
+	}
+	if h.body.Ref != "" {
+		return
+	}
+	if h.body.Properties == nil {
+		h.body.Properties = make(map[string]*Schema)
+	}
+	if _, ok := h.body.Properties[name]; !ok {
+		h.body.Properties[name] = schema
+	}
+}
+
+// binding 局部变量的声明类型或赋值表达式
+type binding struct {
+	typ   ast.Expr
+	value ast.Expr
+	index int // 多返回值中的位置
+}
+
+// argument 内联分析时参数对应的调用方实参
+type argument struct {
+	expr  ast.Expr
+	scope *scope
+}
+
+// scope 函数体的分析上下文
+type scope struct {
+	fn        *ast.FuncType
+	body      *ast.BlockStmt
+	file      *ast.File
+	pkg       *pkgInfo
+	recv      string    // 接收者变量名
+	recvType  *typeInfo // 接收者类型
+	locals    map[string]binding
+	args      map[string]argument
+	jsonBody  map[string]bool // 保存 httpx.ParseJson 结果的变量
+	bindOnly  bool            // 调用 binding.Bind 的辅助函数，只分析请求参数
+	depth     int
+	callStack map[*ast.FuncDecl]bool
+}
+
+// analyzer 处理函数分析器
+type analyzer struct {
+	src     *source
+	schemas *schemaBuilder
+}
+
+// analyzeMethod 分析控制器方法
+func (a *analyzer) analyzeMethod(fn *funcInfo, recvType *typeInfo) *handlerInfo {
+	info := &handlerInfo{responses: make(map[int]map[string]*Schema)}
+	info.summary, info.description = docText(fn.decl.Doc, fn.decl.Name.Name)
+	sc := a.newScope(fn.decl, fn.file, fn.pkg, recvType, nil, 0, nil)
+	a.walk(sc, info)
+	a.finish(info)
+	return info
+}
+
+// analyzeLiteral 分析路由中直接定义的匿名处理函数
+func (a *analyzer) analyzeLiteral(lit *ast.FuncLit, file *ast.File, pkg *pkgInfo) *handlerInfo {
+	info := &handlerInfo{responses: make(map[int]map[string]*Schema)}
+	sc := &scope{
+		fn:        lit.Type,
+		body:      lit.Body,
+		file:      file,
+		pkg:       pkg,
+		locals:    make(map[string]binding),
+		args:      make(map[string]argument),
+		jsonBody:  make(map[string]bool),
+		callStack: make(map[*ast.FuncDecl]bool),
+	}
+	a.walk(sc, info)
+	a.finish(info)
+	return info
+}
+
+// finish 没有识别到任何响应时补充默认响应，并为直接写入的响应补充内容类型
+func (a *analyzer) finish(info *handlerInfo) {
+	if len(info.responses) == 0 {
+		info.addResponse(200, "", nil)
+	}
+	if !info.rawWrite {
+		return
+	}
+	// w.WriteHeader 之后直接写入的内容按纯文本处理
+	for status, contents := range info.responses {
+		if len(contents) == 0 {
+			info.addResponse(status, "text/plain", &Schema{Type: "string"})
+		}
+	}
+}
+
+// newScope 创建函数或方法的分析上下文，callArgs 为调用方传入的实参
+func (a *analyzer) newScope(decl *ast.FuncDecl, file *ast.File, pkg *pkgInfo, recvType *typeInfo, caller *scope, depth int, callArgs []ast.Expr) *scope {
+	sc := &scope{
+		fn:        decl.Type,
+		body:      decl.Body,
+		file:      file,
+		pkg:       pkg,
+		recvType:  recvType,
+		locals:    make(map[string]binding),
+		args:      make(map[string]argument),
+		jsonBody:  make(map[string]bool),
+		depth:     depth,
+		callStack: make(map[*ast.FuncDecl]bool),
+	}
+	if caller != nil {
+		for k, v := range caller.callStack {
+			sc.callStack[k] = v
+		}
+	}
+	sc.callStack[decl] = true
+
+	if decl.Recv != nil && len(decl.Recv.List) > 0 && len(decl.Recv.List[0].Names) > 0 {
+		sc.recv = decl.Recv.List[0].Names[0].Name
+	}
+
+	if caller != nil {
+		i := 0
+		for _, field := range decl.Type.Params.List {
+			names := field.Names
+			if len(names) == 0 {
+				i++
+				continue
+			}
+			for _, name := range names {
+				if i < len(callArgs) {
+					sc.args[name.Name] = argument{expr: callArgs[i], scope: caller}
+					if ident, ok := unparen(callArgs[i]).(*ast.Ident); ok && caller.jsonBody[ident.Name] {
+						sc.jsonBody[name.Name] = true
+					}
+				}
+				i++
+			}
+		}
+	}
+	return sc
+}
+
+// walk 收集局部变量后分析函数体中的调用与比较表达式
+func (a *analyzer) walk(sc *scope, info *handlerInfo) {
+	if sc.body == nil {
+		return
+	}
+	a.collectLocals(sc)
+
+	// 调用 binding.Bind 的辅助方法（如 BaseController.Bind）只提供请求参数，其中的参数错误响应不是接口的响应
+	if sc.depth > 0 {
+		ast.Inspect(sc.body, func(n ast.Node) bool {
+			if call, ok := n.(*ast.CallExpr); ok && a.isBindCall(call, sc) {
+				sc.bindOnly = true
+			}
+			return !sc.bindOnly
+		})
+	}
+
+	ast.Inspect(sc.body, func(n ast.Node) bool {
+		switch node := n.(type) {
+		case *ast.CallExpr:
+			a.call(node, sc, info)
+		case *ast.BinaryExpr:
+			if node.Op != token.EQL && node.Op != token.NEQ {
+				return true
+			}
+			method := ""
+			if a.isRequestMethod(node.X, sc) {
+				method = a.methodName(node.Y, sc)
+			} else if a.isRequestMethod(node.Y, sc) {
+				method = a.methodName(node.X, sc)
+			}
+			if method == "" {
+				return true
+			}
+			if node.Op == token.NEQ {
+				info.required = append(info.required, method)
+			} else {
+				info.allowed = append(info.allowed, method)
+			}
+		case *ast.SwitchStmt:
+			if node.Tag == nil || !a.isRequestMethod(node.Tag, sc) {
+				return true
+			}
+			for _, stmt := range node.Body.List {
+				clause, ok := stmt.(*ast.CaseClause)
+				if !ok {
+					continue
+				}
+				for _, expr := range clause.List {
+					if method := a.methodName(expr, sc); method != "" {
+						info.allowed = append(info.allowed, method)
+					}
+				}
+			}
+		}
+		return true
+	})
+}
+
+// collectLocals 记录函数体中局部变量的类型或首次赋值
+func (a *analyzer) collectLocals(sc *scope) {
+	bind := func(name string, b binding) {
+		if name == "_" {
+			return
+		}
+		if _, ok := sc.locals[name]; !ok {
+			sc.locals[name] = b
+		}
+	}
+
+	ast.Inspect(sc.body, func(n ast.Node) bool {
+		switch node := n.(type) {
+		case *ast.AssignStmt:
+			for i, lhs := range node.Lhs {
+				ident, ok := lhs.(*ast.Ident)
+				if !ok {
+					continue
+				}
+				var value ast.Expr
+				index := 0
+				if len(node.Rhs) == len(node.Lhs) {
+					value = node.Rhs[i]
+				} else if len(node.Rhs) == 1 {
+					value, index = node.Rhs[0], i
+				}
+				if value == nil {
+					continue
+				}
+				if i == 0 && a.isParseJSON(value, sc) {
+					sc.jsonBody[ident.Name] = true
+				}
+				bind(ident.Name, binding{value: value, index: index})
+			}
+		case *ast.GenDecl:
+			if node.Tok != token.VAR {
+				return true
+			}
+			for _, spec := range node.Specs {
+				vs, ok := spec.(*ast.ValueSpec)
+				if !ok {
+					continue
+				}
+				for i, name := range vs.Names {
+					switch {
+					case vs.Type != nil:
+						bind(name.Name, binding{typ: vs.Type})
+					case len(vs.Values) == len(vs.Names):
+						bind(name.Name, binding{value: vs.Values[i]})
+					case len(vs.Values) == 1:
+						bind(name.Name, binding{value: vs.Values[0], index: i})
+					}
+				}
+			}
+		}
+		return true
+	})
+}
+
+// call 分析单个函数调用
+func (a *analyzer) call(call *ast.CallExpr, sc *scope, info *handlerInfo) {
+	if a.isBindCall(call, sc) {
+		a.bind(call.Args[1], sc, info)
+		return
+	}
+	if sc.bindOnly {
+		return
+	}
+
+	sel, ok := call.Fun.(*ast.SelectorExpr)
+	if !ok {
+		if ident, ok := call.Fun.(*ast.Ident); ok {
+			if fn, ok := sc.pkg.funcs[ident.Name]; ok {
+				a.inline(fn, nil, call, sc, info)
+			}
+		}
+		return
+	}
+	name := sel.Sel.Name
+
+	// 包级函数调用
+	if path := a.packageOf(sel.X, sc); path != "" {
+		switch {
+		case path == "encoding/json" && name == "Unmarshal" && len(call.Args) == 2:
+			a.setBody(info, a.exprSchema(call.Args[1], sc))
+		case path == "fmt" && strings.HasPrefix(name, "Fprint") && len(call.Args) > 0 && a.isWriter(call.Args[0], sc):
+			info.rawWrite = true
+		case strings.HasSuffix(path, "/httpx"):
+			a.httpxCall(name, call, sc, info)
+		case strings.HasSuffix(path, "/tmap") && strings.HasPrefix(name, "Get") && len(call.Args) >= 2:
+			if a.isJSONBody(call.Args[0], sc) {
+				if key, ok := a.stringValue(call.Args[1], sc); ok {
+					info.addBodyProperty(key, tmapSchema(name))
+				}
+			}
+		case path == "net/http":
+			a.netHTTPCall(name, call, sc, info)
+		case len(call.Args) >= 2 && a.isJSONBody(call.Args[0], sc):
+			// tstruct.MapToStruct(body, &req) 与 tstruct.MapToStructWithValidation(body, &req, "field", ...)
+			if u, ok := unparen(call.Args[1]).(*ast.UnaryExpr); ok && u.Op == token.AND {
+				required := make([]string, 0)
+				for _, arg := range call.Args[2:] {
+					if key, ok := a.stringValue(arg, sc); ok {
+						required = append(required, key)
+					}
+				}
+				a.setBody(info, a.withRequired(a.exprSchema(u.X, sc), required))
+			}
+		default:
+			if pkg, ok := a.src.pkgs[path]; ok {
+				if fn, ok := pkg.funcs[name]; ok {
+					a.inline(fn, nil, call, sc, info)
+				}
+			}
+		}
+		return
+	}
+
+	switch {
+	case name == "Decode" && len(call.Args) == 1:
+		// json.NewDecoder(r.Body).Decode(&req)
+		if inner, ok := unparen(sel.X).(*ast.CallExpr); ok && len(inner.Args) == 1 && a.isRequestBody(inner.Args[0], sc) {
+			a.setBody(info, a.exprSchema(call.Args[0], sc))
+		}
+	case name == "Encode" && len(call.Args) == 1:
+		// json.NewEncoder(w).Encode(v)
+		if inner, ok := unparen(sel.X).(*ast.CallExpr); ok && len(inner.Args) == 1 && a.isWriter(inner.Args[0], sc) {
+			info.addResponse(200, "application/json", a.exprSchema(call.Args[0], sc))
+		}
+	case name == "Get" && len(call.Args) == 1 && a.isQuery(sel.X, sc):
+		if key, ok := a.stringValue(call.Args[0], sc); ok {
+			info.query = append(info.query, key)
+		}
+	case (name == "FormValue" || name == "PostFormValue") && len(call.Args) == 1 && a.isRequest(sel.X, sc):
+		if key, ok := a.stringValue(call.Args[0], sc); ok {
+			info.form = append(info.form, key)
+		}
+	case name == "WriteHeader" && len(call.Args) == 1 && a.isWriter(sel.X, sc):
+		info.addResponse(a.statusCode(call.Args[0], sc), "", nil)
+	case name == "Write" && a.isWriter(sel.X, sc):
+		info.rawWrite = true
+	default:
+		// 接收者的方法，包括嵌入的 BaseController 等提供的响应方法
+		if ident, ok := sel.X.(*ast.Ident); ok && ident.Name == sc.recv && sc.recvType != nil {
+			if fn, owner := a.src.findMethod(sc.recvType, name); fn != nil {
+				a.inline(fn, owner, call, sc, info)
+			}
+		}
+	}
+}
+
+// isBindCall 是否为 pkg/binding 的 Bind(r, &req) 调用
+func (a *analyzer) isBindCall(call *ast.CallExpr, sc *scope) bool {
+	sel, ok := call.Fun.(*ast.SelectorExpr)
+	if !ok || sel.Sel.Name != "Bind" || len(call.Args) != 2 {
+		return false
+	}
+	return strings.HasSuffix(a.packageOf(sel.X, sc), "/binding")
+}
+
+// bind 按 binding.Bind 目标结构体的标签记录路径、查询、请求头与表单参数，有 JSON 字段时记录请求体
+func (a *analyzer) bind(expr ast.Expr, sc *scope, info *handlerInfo) {
+	typ, file, pkg := a.exprType(expr, sc, 0, 0)
+	if typ == nil {
+		return
+	}
+	st, ok := derefStruct(typ)
+	stFile, stPkg := file, pkg
+	if !ok {
+		owner := a.src.resolveType(typ, file, pkg)
+		if owner == nil {
+			return
+		}
+		if st, ok = owner.spec.Type.(*ast.StructType); !ok {
+			return
+		}
+		stFile, stPkg = owner.file, owner.pkg
+	}
+	if a.bindFields(st, stFile, stPkg, info, 0) {
+		a.setBody(info, a.schemas.typeSchema(typ, file, pkg))
+	}
+}
+
+// bindFields 记录结构体中带 path、query、header、form 标签的字段，返回是否有从 JSON 请求体读取的字段
+func (a *analyzer) bindFields(st *ast.StructType, file *ast.File, pkg *pkgInfo, info *handlerInfo, depth int) bool {
+	hasBody := false
+	for _, field := range st.Fields.List {
+		tag := fieldTag(field)
+		in, name := paramTag(tag)
+		jsonName, _, _, skip := parseJSONTag(tag.Get("json"))
+		switch {
+		case in == "form":
+			info.form = append(info.form, name)
+		case in != "":
+			schema := withRules(a.schemas.typeSchema(field.Type, file, pkg), tag.Get("validate"))
+			info.params = append(info.params, Parameter{Name: name, In: in, Required: in == "path" || isRequired(tag), Schema: schema})
+		case skip:
+		case len(field.Names) == 0 && jsonName == "" && depth < 5:
+			// 嵌入的结构体平铺到外层
+			if embedded := a.src.resolveType(field.Type, file, pkg); embedded != nil {
+				if est, ok := embedded.spec.Type.(*ast.StructType); ok {
+					hasBody = a.bindFields(est, embedded.file, embedded.pkg, info, depth+1) || hasBody
+				}
+			}
+		default:
+			for _, ident := range field.Names {
+				if ident.IsExported() {
+					hasBody = true
+				}
+			}
+		}
+	}
+	return hasBody
+}
+
+// httpxCall 分析 taurus-pro-http 的 httpx 响应函数
+func (a *analyzer) httpxCall(name string, call *ast.CallExpr, sc *scope, info *handlerInfo) {
+	args := call.Args
+	switch name {
+	case "SendResponse":
+		if len(args) >= 3 {
+			info.addResponse(a.statusCode(args[1], sc), "application/json", a.exprSchema(args[2], sc))
+		}
+	case "CustomJSONResponse", "JSONResponse", "JsonResponse":
+		if len(args) >= 2 {
+			info.addResponse(200, "application/json", a.exprSchema(args[1], sc))
+		}
+	case "HTMLResponse":
+		info.addResponse(200, "text/html", &Schema{Type: "string"})
+	case "RedirectResponse":
+		status := 302
+		if len(args) >= 4 {
+			status = a.statusCode(args[3], sc)
+		}
+		info.addResponse(status, "", nil)
+	}
+}
+
+// netHTTPCall 分析直接使用 net/http 写入响应的函数
+func (a *analyzer) netHTTPCall(name string, call *ast.CallExpr, sc *scope, info *handlerInfo) {
+	args := call.Args
+	switch name {
+	case "Error":
+		if len(args) == 3 {
+			info.addResponse(a.statusCode(args[2], sc), "text/plain", &Schema{Type: "string"})
+		}
+	case "NotFound":
+		info.addResponse(404, "text/plain", &Schema{Type: "string"})
+	case "Redirect":
+		if len(args) == 4 {
+			info.addResponse(a.statusCode(args[3], sc), "", nil)
+		}
+	case "ServeFile", "ServeContent":
+		info.addResponse(200, "", nil)
+	}
+}
+
+// withRequired 为请求体添加必填字段，引用类型展开为副本以免修改 components 中的定义
+func (a *analyzer) withRequired(schema *Schema, required []string) *Schema {
+	if schema == nil || len(required) == 0 {
+		return schema
+	}
+	target := schema
+	if schema.Ref != "" {
+		component, ok := a.schemas.schemas[strings.TrimPrefix(schema.Ref, "#/components/schemas/")]
+		if !ok {
+			return schema
+		}
+		target = component
+	}
+	copied := *target
+	copied.Required = nil
+	for _, name := range required {
+		if _, ok := copied.Properties[name]; ok {
+			copied.Required = append(copied.Required, name)
+		}
+	}
+	return &copied
+}
+
+// inline 当调用传入了 ResponseWriter、Request 或请求体时，进入被调用函数继续分析
+func (a *analyzer) inline(fn *funcInfo, owner *typeInfo, call *ast.CallExpr, sc *scope, info *handlerInfo) {
+	if sc.depth >= maxInlineDepth || sc.callStack[fn.decl] || fn.decl.Body == nil {
+		return
+	}
+	relevant := false
+	for _, arg := range call.Args {
+		if a.isWriter(arg, sc) || a.isRequest(arg, sc) {
+			relevant = true
+		}
+		if a.isJSONBody(arg, sc) {
+			relevant = true
+		}
+	}
+	if !relevant {
+		return
+	}
+	if owner == nil {
+		owner = sc.recvType
+	}
+	a.walk(a.newScope(fn.decl, fn.file, fn.pkg, owner, sc, sc.depth+1, call.Args), info)
+}
+
+// setBody 记录 JSON 请求体
+func (a *analyzer) setBody(info *handlerInfo, schema *Schema) {
+	if schema == nil {
+		return
+	}
+	if info.body == nil || score(schema) > score(info.body) {
+		info.body = schema
+	}
+}
+
+// packageOf 表达式为导入的包名时返回导入路径
+func (a *analyzer) packageOf(expr ast.Expr, sc *scope) string {
+	ident, ok := expr.(*ast.Ident)
+	if !ok || sc.isVar(ident.Name) {
+		return ""
+	}
+	return importPath(sc.file, ident.Name)
+}
+
+// isVar 名称是否为局部变量、参数或接收者
+func (sc *scope) isVar(name string) bool {
+	if _, ok := sc.locals[name]; ok {
+		return true
+	}
+	if name == sc.recv {
+		return true
+	}
+	return sc.param(name) != nil
+}
+
+// param 返回参数的声明类型
+func (sc *scope) param(name string) ast.Expr {
+	if sc.fn == nil || sc.fn.Params == nil {
+		return nil
+	}
+	for _, field := range sc.fn.Params.List {
+		for _, n := range field.Names {
+			if n.Name == name {
+				return field.Type
+			}
+		}
+	}
+	return nil
+}
+
+// paramIsType 参数是否为指定包中的类型，如 net/http.ResponseWriter
+func (a *analyzer) paramIsType(expr ast.Expr, sc *scope, path, name string) bool {
+	ident, ok := unparen(expr).(*ast.Ident)
+	if !ok {
+		return false
+	}
+	typ := sc.param(ident.Name)
+	if typ == nil {
+		return false
+	}
+	if star, ok := typ.(*ast.StarExpr); ok {
+		typ = star.X
+	}
+	sel, ok := typ.(*ast.SelectorExpr)
+	if !ok || sel.Sel.Name != name {
+		return false
+	}
+	x, ok := sel.X.(*ast.Ident)
+	return ok && importPath(sc.file, x.Name) == path
+}
+
+// isWriter 表达式是否为 http.ResponseWriter 参数
+func (a *analyzer) isWriter(expr ast.Expr, sc *scope) bool {
+	return a.paramIsType(expr, sc, "net/http", "ResponseWriter")
+}
+
+// isRequest 表达式是否为 *http.Request 参数
+func (a *analyzer) isRequest(expr ast.Expr, sc *scope) bool {
+	return a.paramIsType(expr, sc, "net/http", "Request")
+}
+
+// isRequestBody 表达式是否为 r.Body
+func (a *analyzer) isRequestBody(expr ast.Expr, sc *scope) bool {
+	sel, ok := unparen(expr).(*ast.SelectorExpr)
+	return ok && sel.Sel.Name == "Body" && a.isRequest(sel.X, sc)
+}
+
+// isRequestMethod 表达式是否为 r.Method
+func (a *analyzer) isRequestMethod(expr ast.Expr, sc *scope) bool {
+	sel, ok := unparen(expr).(*ast.SelectorExpr)
+	return ok && sel.Sel.Name == "Method" && a.isRequest(sel.X, sc)
+}
+
+// isQuery 表达式是否为 r.URL.Query() 或保存其结果的变量
+func (a *analyzer) isQuery(expr ast.Expr, sc *scope) bool {
+	expr = unparen(expr)
+	if ident, ok := expr.(*ast.Ident); ok {
+		if b, ok := sc.locals[ident.Name]; ok && b.value != nil {
+			return a.isQuery(b.value, sc)
+		}
+		return false
+	}
+	call, ok := expr.(*ast.CallExpr)
+	if !ok {
+		return false
+	}
+	sel, ok := call.Fun.(*ast.SelectorExpr)
+	if !ok || sel.Sel.Name != "Query" {
+		return false
+	}
+	url, ok := sel.X.(*ast.SelectorExpr)
+	return ok && url.Sel.Name == "URL" && a.isRequest(url.X, sc)
+}
+
+// isJSONBody 表达式是否为保存 httpx.ParseJson 结果的变量
+func (a *analyzer) isJSONBody(expr ast.Expr, sc *scope) bool {
+	ident, ok := unparen(expr).(*ast.Ident)
+	return ok && sc.jsonBody[ident.Name]
+}
+
+// isParseJSON 表达式是否为 httpx.ParseJson(r)
+func (a *analyzer) isParseJSON(expr ast.Expr, sc *scope) bool {
+	call, ok := unparen(expr).(*ast.CallExpr)
+	if !ok {
+		return false
+	}
+	sel, ok := call.Fun.(*ast.SelectorExpr)
+	if !ok || !strings.EqualFold(sel.Sel.Name, "ParseJson") {
+		return false
+	}
+	return strings.HasSuffix(a.packageOf(sel.X, sc), "/httpx")
+}
+
+// methodName 返回 http.MethodPost 或 "POST" 对应的方法名
+func (a *analyzer) methodName(expr ast.Expr, sc *scope) string {
+	switch e := unparen(expr).(type) {
+	case *ast.SelectorExpr:
+		if a.packageOf(e.X, sc) == "net/http" && strings.HasPrefix(e.Sel.Name, "Method") {
+			return strings.ToUpper(strings.TrimPrefix(e.Sel.Name, "Method"))
+		}
+	case *ast.BasicLit:
+		if e.Kind == token.STRING {
+			value, _ := strconv.Unquote(e.Value)
+			return strings.ToUpper(value)
+		}
+	}
+	return ""
+}
+
+// statusCode 解析状态码表达式，无法解析时按 200 处理
+func (a *analyzer) statusCode(expr ast.Expr, sc *scope) int {
+	switch e := unparen(expr).(type) {
+	case *ast.BasicLit:
+		if code, err := strconv.Atoi(e.Value); err == nil {
+			return code
+		}
+	case *ast.SelectorExpr:
+		if a.packageOf(e.X, sc) == "net/http" {
+			if code, ok := statusCodes[e.Sel.Name]; ok {
+				return code
+			}
+		}
+	case *ast.Ident:
+		if arg, ok := sc.args[e.Name]; ok {
+			return a.statusCode(arg.expr, arg.scope)
+		}
+		if b, ok := sc.locals[e.Name]; ok && b.value != nil {
+			return a.statusCode(b.value, sc)
+		}
+		if value, ok := sc.pkg.consts[e.Name]; ok {
+			return a.statusCode(value, sc)
+		}
+	}
+	return 200
+}
+
+// stringValue 解析字符串字面量或常量
+func (a *analyzer) stringValue(expr ast.Expr, sc *scope) (string, bool) {
+	switch e := unparen(expr).(type) {
+	case *ast.BasicLit:
+		if e.Kind == token.STRING {
+			value, err := strconv.Unquote(e.Value)
+			return value, err == nil
+		}
+	case *ast.Ident:
+		if value, ok := sc.pkg.consts[e.Name]; ok {
+			return a.stringValue(value, sc)
+		}
+	case *ast.SelectorExpr:
+		if pkg, ok := a.src.pkgs[a.packageOf(e.X, sc)]; ok {
+			if value, ok := pkg.consts[e.Sel.Name]; ok {
+				return a.stringValue(value, &scope{file: fileOf(pkg, value), pkg: pkg})
+			}
+		}
+	}
+	return "", false
+}
+
+// exprSchema 推导表达式的值对应的 schema，nil 字面量返回 nil
+func (a *analyzer) exprSchema(expr ast.Expr, sc *scope) *Schema {
+	return a.exprSchemaDepth(expr, sc, 0)
+}
+
+func (a *analyzer) exprSchemaDepth(expr ast.Expr, sc *scope, depth int) *Schema {
+	if depth > 8 {
+		return &Schema{}
+	}
+	switch e := unparen(expr).(type) {
+	case *ast.Ident:
+		switch e.Name {
+		case "nil":
+			return nil
+		case "true", "false":
+			return &Schema{Type: "boolean"}
+		}
+		// interface{} 参数使用调用方的实参推导
+		if arg, ok := sc.args[e.Name]; ok && isInterface(sc.param(e.Name)) {
+			return a.exprSchemaDepth(arg.expr, arg.scope, depth+1)
+		}
+		if b, ok := sc.locals[e.Name]; ok && b.typ == nil && b.index == 0 {
+			if lit, ok := unparen(b.value).(*ast.CompositeLit); ok {
+				return a.exprSchemaDepth(lit, sc, depth+1)
+			}
+		}
+	case *ast.BasicLit:
+		switch e.Kind {
+		case token.INT:
+			return &Schema{Type: "integer"}
+		case token.FLOAT:
+			return &Schema{Type: "number"}
+		default:
+			return &Schema{Type: "string"}
+		}
+	case *ast.BinaryExpr:
+		switch e.Op {
+		case token.EQL, token.NEQ, token.LSS, token.GTR, token.LEQ, token.GEQ, token.LAND, token.LOR:
+			return &Schema{Type: "boolean"}
+		}
+		return a.exprSchemaDepth(e.X, sc, depth+1)
+	case *ast.UnaryExpr:
+		if e.Op == token.NOT {
+			return &Schema{Type: "boolean"}
+		}
+		return a.exprSchemaDepth(e.X, sc, depth+1)
+	case *ast.CompositeLit:
+		if mt, ok := e.Type.(*ast.MapType); ok && isString(mt.Key) && len(e.Elts) > 0 {
+			// map 字面量按键展开为对象属性，如 map[string]any{"status": "success"}
+			schema := &Schema{Type: "object", Properties: make(map[string]*Schema)}
+			for _, elt := range e.Elts {
+				kv, ok := elt.(*ast.KeyValueExpr)
+				if !ok {
+					continue
+				}
+				key, ok := a.stringValue(kv.Key, sc)
+				if !ok {
+					return a.schemas.typeSchema(e.Type, sc.file, sc.pkg)
+				}
+				prop := a.exprSchemaDepth(kv.Value, sc, depth+1)
+				if prop == nil {
+					prop = &Schema{Nullable: true}
+				}
+				schema.Properties[key] = prop
+			}
+			return schema
+		}
+	}
+
+	typ, file, pkg := a.exprType(expr, sc, 0, depth)
+	if typ == nil {
+		return &Schema{}
+	}
+	return a.schemas.typeSchema(typ, file, pkg)
+}
+
+// exprType 推导表达式的类型，返回类型表达式及其所在的文件与包
+func (a *analyzer) exprType(expr ast.Expr, sc *scope, index, depth int) (ast.Expr, *ast.File, *pkgInfo) {
+	if depth > 8 {
+		return nil, nil, nil
+	}
+	switch e := unparen(expr).(type) {
+	case *ast.UnaryExpr:
+		return a.exprType(e.X, sc, 0, depth+1)
+	case *ast.StarExpr:
+		return a.exprType(e.X, sc, 0, depth+1)
+	case *ast.CompositeLit:
+		if e.Type != nil {
+			return e.Type, sc.file, sc.pkg
+		}
+	case *ast.TypeAssertExpr:
+		if e.Type != nil {
+			return e.Type, sc.file, sc.pkg
+		}
+	case *ast.BasicLit:
+		switch e.Kind {
+		case token.INT:
+			return ast.NewIdent("int"), sc.file, sc.pkg
+		case token.FLOAT:
+			return ast.NewIdent("float64"), sc.file, sc.pkg
+		case token.STRING:
+			return ast.NewIdent("string"), sc.file, sc.pkg
+		}
+	case *ast.Ident:
+		if e.Name == sc.recv && sc.recvType != nil {
+			return sc.recvType.spec.Name, sc.recvType.file, sc.recvType.pkg
+		}
+		if b, ok := sc.locals[e.Name]; ok {
+			if b.typ != nil {
+				return b.typ, sc.file, sc.pkg
+			}
+			return a.exprType(b.value, sc, b.index, depth+1)
+		}
+		if typ := sc.param(e.Name); typ != nil {
+			if arg, ok := sc.args[e.Name]; ok && isInterface(typ) {
+				return a.exprType(arg.expr, arg.scope, 0, depth+1)
+			}
+			return typ, sc.file, sc.pkg
+		}
+		if value, ok := sc.pkg.consts[e.Name]; ok {
+			return a.exprType(value, sc, 0, depth+1)
+		}
+	case *ast.IndexExpr:
+		typ, file, pkg := a.exprType(e.X, sc, 0, depth+1)
+		switch t := typ.(type) {
+		case *ast.ArrayType:
+			return t.Elt, file, pkg
+		case *ast.MapType:
+			return t.Value, file, pkg
+		}
+	case *ast.SelectorExpr:
+		if path := a.packageOf(e.X, sc); path != "" {
+			if pkg, ok := a.src.pkgs[path]; ok {
+				if value, ok := pkg.consts[e.Sel.Name]; ok {
+					return a.exprType(value, &scope{file: fileOf(pkg, value), pkg: pkg}, 0, depth+1)
+				}
+			}
+			return nil, nil, nil
+		}
+		// 结构体字段，如 c.UserService 或 resp.Data
+		typ, file, pkg := a.exprType(e.X, sc, 0, depth+1)
+		if typ == nil {
+			return nil, nil, nil
+		}
+		if st, ok := derefStruct(typ); ok {
+			return structField(st, e.Sel.Name), file, pkg
+		}
+		owner := a.src.resolveType(typ, file, pkg)
+		return a.fieldTypeExpr(owner, e.Sel.Name)
+	case *ast.CallExpr:
+		return a.callType(e, sc, index, depth)
+	}
+	return nil, nil, nil
+}
+
+// callType 推导函数调用第 index 个返回值的类型
+func (a *analyzer) callType(call *ast.CallExpr, sc *scope, index, depth int) (ast.Expr, *ast.File, *pkgInfo) {
+	switch fun := unparen(call.Fun).(type) {
+	case *ast.Ident:
+		switch fun.Name {
+		case "new", "make":
+			if len(call.Args) > 0 {
+				return call.Args[0], sc.file, sc.pkg
+			}
+			return nil, nil, nil
+		case "len", "cap":
+			return ast.NewIdent("int"), sc.file, sc.pkg
+		}
+		if _, ok := basicSchemas[fun.Name]; ok {
+			return fun, sc.file, sc.pkg
+		}
+		if _, ok := sc.pkg.types[fun.Name]; ok {
+			return fun, sc.file, sc.pkg
+		}
+		if fn, ok := sc.pkg.funcs[fun.Name]; ok {
+			return result(fn, index)
+		}
+	case *ast.ArrayType, *ast.MapType:
+		return fun, sc.file, sc.pkg
+	case *ast.SelectorExpr:
+		if path := a.packageOf(fun.X, sc); path != "" {
+			pkg, ok := a.src.pkgs[path]
+			if !ok {
+				return nil, nil, nil
+			}
+			if _, ok := pkg.types[fun.Sel.Name]; ok {
+				return fun, sc.file, sc.pkg
+			}
+			if fn, ok := pkg.funcs[fun.Sel.Name]; ok {
+				return result(fn, index)
+			}
+			return nil, nil, nil
+		}
+		// 方法调用，如 c.UserService.GetUserByID(...)
+		typ, file, pkg := a.exprType(fun.X, sc, 0, depth+1)
+		if typ == nil {
+			return nil, nil, nil
+		}
+		if fn, _ := a.src.findMethod(a.src.resolveType(typ, file, pkg), fun.Sel.Name); fn != nil {
+			return result(fn, index)
+		}
+	}
+	return nil, nil, nil
+}
+
+// fieldTypeExpr 返回命名结构体中字段的类型表达式，包括嵌入字段中的字段
+func (a *analyzer) fieldTypeExpr(owner *typeInfo, name string) (ast.Expr, *ast.File, *pkgInfo) {
+	for depth := 0; owner != nil && depth < 4; depth++ {
+		st, ok := owner.spec.Type.(*ast.StructType)
+		if !ok {
+			return nil, nil, nil
+		}
+		if typ := structField(st, name); typ != nil {
+			return typ, owner.file, owner.pkg
+		}
+		var next *typeInfo
+		for _, field := range st.Fields.List {
+			if len(field.Names) == 0 {
+				if embedded := a.src.resolveType(field.Type, owner.file, owner.pkg); embedded != nil && a.src.fieldType(embedded, name) != nil {
+					next = embedded
+					break
+				}
+			}
+		}
+		owner = next
+	}
+	return nil, nil, nil
+}
+
+// result 返回函数第 index 个返回值的类型
+func result(fn *funcInfo, index int) (ast.Expr, *ast.File, *pkgInfo) {
+	if fn.decl.Type.Results == nil {
+		return nil, nil, nil
+	}
+	i := 0
+	for _, field := range fn.decl.Type.Results.List {
+		n := len(field.Names)
+		if n == 0 {
+			n = 1
+		}
+		if index < i+n {
+			return field.Type, fn.file, fn.pkg
+		}
+		i += n
+	}
+	return nil, nil, nil
+}
+
+// structField 返回匿名结构体中字段的类型
+func structField(st *ast.StructType, name string) ast.Expr {
+	for _, field := range st.Fields.List {
+		for _, n := range field.Names {
+			if n.Name == name {
+				return field.Type
+			}
+		}
+	}
+	return nil
+}
+
+// derefStruct 类型表达式是否为匿名结构体或其指针
+func derefStruct(typ ast.Expr) (*ast.StructType, bool) {
+	if star, ok := typ.(*ast.StarExpr); ok {
+		typ = star.X
+	}
+	st, ok := typ.(*ast.StructType)
+	return st, ok
+}
+
+// tmapSchema 根据 tmap.GetXxx 的函数名推导字段类型
+func tmapSchema(name string) *Schema {
+	kind := strings.ToLower(strings.TrimPrefix(name, "Get"))
+	switch {
+	case strings.HasPrefix(kind, "int64"), strings.HasPrefix(kind, "uint64"):
+		return &Schema{Type: "integer", Format: "int64"}
+	case strings.HasPrefix(kind, "int"), strings.HasPrefix(kind, "uint"):
+		return &Schema{Type: "integer"}
+	case strings.HasPrefix(kind, "float"):
+		return &Schema{Type: "number"}
+	case strings.HasPrefix(kind, "bool"):
+		return &Schema{Type: "boolean"}
+	case strings.HasPrefix(kind, "string"):
+		if kind == "strings" || kind == "stringslice" {
+			return &Schema{Type: "array", Items: &Schema{Type: "string"}}
+		}
+		return &Schema{Type: "string"}
+	case strings.HasPrefix(kind, "slice"), strings.HasPrefix(kind, "array"):
+		return &Schema{Type: "array", Items: &Schema{}}
+	case strings.HasPrefix(kind, "map"):
+		return &Schema{Type: "object"}
+	}
+	return &Schema{}
+}
+
+// score 衡量 schema 包含的信息量，用于在多个候选中选择更完整的一个
+func score(s *Schema) int {
+	if s == nil {
+		return 0
+	}
+	n := 1
+	if s.Ref != "" {
+		n += 3
+	}
+	if s.Type != "" {
+		n++
+	}
+	for _, prop := range s.Properties {
+		n += score(prop)
+	}
+	return n + score(s.Items) + score(s.AdditionalProperties)
+}
+
+// isInterface 类型表达式是否为 interface{} 或 any
+func isInterface(expr ast.Expr) bool {
+	switch t := expr.(type) {
+	case *ast.InterfaceType:
+		return true
+	case *ast.Ident:
+		return t.Name == "any"
+	}
+	return false
+}
+
+// isString 类型表达式是否为 string
+func isString(expr ast.Expr) bool {
+	ident, ok := expr.(*ast.Ident)
+	return ok && ident.Name == "string"
+}
+
+// unparen 去掉表达式外层的括号
+func unparen(expr ast.Expr) ast.Expr {
+	for {
+		p, ok := expr.(*ast.ParenExpr)
+		if !ok {
+			return expr
+		}
+		expr = p.X
+	}
+}
+
+// fileOf 返回包含节点的文件
+func fileOf(pkg *pkgInfo, node ast.Node) *ast.File {
+	for _, file := range pkg.files {
+		if file.FileStart <= node.Pos() && node.End() <= file.FileEnd {
+			return file
+		}
+	}
+	if len(pkg.files) > 0 {
+		return pkg.files[0]
+	}
+	return nil
+}
+
+// unique 去重并保持顺序
+func unique(values []string) []string {
+	seen := make(map[string]bool, len(values))
+	result := make([]string, 0, len(values))
+	for _, v := range values {
+		if !seen[v] {
+			seen[v] = true
+			result = append(result, v)
+		}
+	}
+	return result
+}
@@ -0,0 +1,342 @@
+// Package openapi 静态分析 taurus 项目源码生成 OpenAPI 3 文档
+// 路由来自入口文件中的 AddRouter / AddRouterGroup 调用，请求与响应的结构来自控制器方法的实现
+package openapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"go/ast"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/stones-hub/taurus-pro-core/pkg/project"
+	"gopkg.in/yaml.v3"
+)
+
+// Options 文档生成选项
+type Options struct {
+	ProjectRoot string   // 项目根目录
+	EntryDir    string   // 注册路由的入口目录，默认 bin
+	Title       string   // 文档标题，默认使用模块名
+	Version     string   // 文档版本，默认 1.0.0
+	Description string   // 文档描述
+	Servers     []string // 服务地址，如 http://localhost:9080
+}
+
+// handlerRef 路由处理函数的定义
+type handlerRef struct {
+	fn       *funcInfo    // 控制器方法或函数
+	recvType *typeInfo    // 控制器类型，函数时为 nil
+	lit      *ast.FuncLit // 匿名处理函数
+}
+
+// Generate 分析项目源码生成 OpenAPI 文档
+func Generate(opts Options) (*Document, error) {
+	if opts.ProjectRoot == "" {
+		opts.ProjectRoot = "."
+	}
+	if opts.EntryDir == "" {
+		opts.EntryDir = "bin"
+	}
+	if opts.Version == "" {
+		opts.Version = "1.0.0"
+	}
+
+	module, err := project.GetModuleName(opts.ProjectRoot)
+	if err != nil {
+		return nil, fmt.Errorf("获取模块名称失败: %v", err)
+	}
+	if opts.Title == "" {
+		opts.Title = module
+	}
+
+	src, err := loadSource(opts.ProjectRoot, module, "app", "internal", "pkg", opts.EntryDir)
+	if err != nil {
+		return nil, err
+	}
+	entry, ok := src.pkgs[module+"/"+filepath.ToSlash(filepath.Clean(opts.EntryDir))]
+	if !ok {
+		return nil, fmt.Errorf("入口目录 %s 中没有 Go 文件", opts.EntryDir)
+	}
+
+	a := &analyzer{src: src, schemas: newSchemaBuilder(src)}
+	doc := &Document{
+		OpenAPI: "3.0.3",
+		Info:    Info{Title: opts.Title, Version: opts.Version, Description: opts.Description},
+		Paths:   make(map[string]*PathItem),
+	}
+	for _, url := range opts.Servers {
+		doc.Servers = append(doc.Servers, Server{URL: url})
+	}
+
+	injector := src.injectorTypes()
+	tags := make(map[string]string)
+	operationIDs := make(map[string]int)
+	secured := false
+
+	for _, r := range a.discoverRoutes(entry) {
+		ref, ok := a.resolveHandler(r.handler, r.file, r.pkg, injector)
+		if !ok {
+			continue
+		}
+
+		var info *handlerInfo
+		operationID, tag := "", ""
+		switch {
+		case ref.lit != nil:
+			info = a.analyzeLiteral(ref.lit, r.file, r.pkg)
+			operationID = operationName(r.path)
+		case ref.recvType != nil:
+			info = a.analyzeMethod(ref.fn, ref.recvType)
+			typeName := ref.recvType.spec.Name.Name
+			operationID = typeName + "." + ref.fn.decl.Name.Name
+			tag = strings.TrimSuffix(typeName, "Controller")
+			if tag == "" {
+				tag = typeName
+			}
+			if _, ok := tags[tag]; !ok {
+				tags[tag], _ = docText(ref.recvType.spec.Doc, typeName)
+			}
+		default:
+			info = a.analyzeMethod(ref.fn, nil)
+			operationID = ref.fn.decl.Name.Name
+		}
+
+		item, ok := doc.Paths[r.path]
+		if !ok {
+			item = &PathItem{}
+			doc.Paths[r.path] = item
+		}
+
+		for i, method := range info.methods() {
+			slot := item.operation(method)
+			if *slot != nil {
+				// 同一路径与方法重复注册时以第一次为准，与路由表的行为一致
+				continue
+			}
+
+			op := buildOperation(info, method)
+			id := operationID
+			if i > 0 {
+				// 同一个处理函数支持多个方法，如 GetUser 与 GetUserPost
+				id += strings.ToUpper(method[:1]) + strings.ToLower(method[1:])
+			}
+			op.OperationID = uniqueID(operationIDs, id)
+			if tag != "" {
+				op.Tags = []string{tag}
+			}
+			if r.requiresAuth() {
+				op.Security = []map[string][]string{{"bearerAuth": {}}}
+				secured = true
+			}
+			*slot = op
+		}
+	}
+
+	for _, name := range sortedKeys(tags) {
+		doc.Tags = append(doc.Tags, Tag{Name: name, Description: tags[name]})
+	}
+	if len(a.schemas.schemas) > 0 {
+		doc.Components.Schemas = a.schemas.schemas
+	}
+	if secured {
+		doc.Components.SecuritySchemes = map[string]*SecurityScheme{
+			"bearerAuth": {Type: "http", Scheme: "bearer", BearerFormat: "JWT"},
+		}
+	}
+	return doc, nil
+}
+
+// resolveHandler 解析路由的 Handler 表达式，支持 http.HandlerFunc(app.Core.XxxController.Method)、
+// 中间件包装后的处理函数、包级函数以及匿名函数
+func (a *analyzer) resolveHandler(expr ast.Expr, file *ast.File, pkg *pkgInfo, injector map[string]*typeInfo) (handlerRef, bool) {
+	switch e := unparen(expr).(type) {
+	case *ast.FuncLit:
+		return handlerRef{lit: e}, true
+	case *ast.CallExpr:
+		// http.HandlerFunc(...) 或包装函数，取第一个能解析的参数
+		for _, arg := range e.Args {
+			if ref, ok := a.resolveHandler(arg, file, pkg, injector); ok {
+				return ref, true
+			}
+		}
+	case *ast.Ident:
+		if fn, ok := pkg.funcs[e.Name]; ok && isHandlerFunc(fn.decl.Type) {
+			return handlerRef{fn: fn}, true
+		}
+	case *ast.SelectorExpr:
+		if x, ok := e.X.(*ast.Ident); ok {
+			if target, ok := a.src.pkgs[importPath(file, x.Name)]; ok {
+				if fn, ok := target.funcs[e.Sel.Name]; ok && isHandlerFunc(fn.decl.Type) {
+					return handlerRef{fn: fn}, true
+				}
+				return handlerRef{}, false
+			}
+		}
+		// app.Core.UserController.CreateUser: 倒数第二段为 Injector 字段，即控制器类型名
+		owner := selectorName(e.X)
+		recvType, ok := injector[owner]
+		if !ok {
+			recvType = a.src.findTypeByName(owner)
+		}
+		if recvType == nil {
+			return handlerRef{}, false
+		}
+		if fn, _ := a.src.findMethod(recvType, e.Sel.Name); fn != nil && isHandlerFunc(fn.decl.Type) {
+			return handlerRef{fn: fn, recvType: recvType}, true
+		}
+	}
+	return handlerRef{}, false
+}
+
+// paramOrder 接口参数按路径、查询、请求头的顺序排列
+var paramOrder = map[string]int{"path": 0, "query": 1, "header": 2}
+
+// buildOperation 根据分析结果构建指定方法的接口
+func buildOperation(info *handlerInfo, method string) *Operation {
+	op := &Operation{
+		Summary:     info.summary,
+		Description: info.description,
+		Responses:   make(map[string]*Response),
+	}
+
+	query := unique(info.query)
+	form := unique(info.form)
+	if method == "GET" || method == "DELETE" {
+		// GET 请求的 FormValue 读取的是查询参数
+		query = unique(append(query, form...))
+		form = nil
+	}
+	// binding.Bind 绑定的参数带有类型与必填信息，优先于 r.URL.Query().Get 读取的同名查询参数
+	seen := make(map[string]bool)
+	for _, param := range info.params {
+		if !seen[param.In+":"+param.Name] {
+			seen[param.In+":"+param.Name] = true
+			op.Parameters = append(op.Parameters, param)
+		}
+	}
+	for _, name := range query {
+		if !seen["query:"+name] {
+			op.Parameters = append(op.Parameters, Parameter{Name: name, In: "query", Schema: &Schema{Type: "string"}})
+		}
+	}
+	sort.SliceStable(op.Parameters, func(i, j int) bool {
+		pi, pj := op.Parameters[i], op.Parameters[j]
+		if pi.In != pj.In {
+			return paramOrder[pi.In] < paramOrder[pj.In]
+		}
+		return pi.Name < pj.Name
+	})
+
+	if method != "GET" && (info.body != nil || len(form) > 0) {
+		op.RequestBody = &RequestBody{Content: make(map[string]*MediaType)}
+		if info.body != nil {
+			op.RequestBody.Content["application/json"] = &MediaType{Schema: info.body}
+		}
+		if len(form) > 0 {
+			schema := &Schema{Type: "object", Properties: make(map[string]*Schema)}
+			for _, name := range form {
+				schema.Properties[name] = &Schema{Type: "string"}
+			}
+			op.RequestBody.Content["application/x-www-form-urlencoded"] = &MediaType{Schema: schema}
+		}
+	}
+
+	for status, contents := range info.responses {
+		resp := &Response{Description: http.StatusText(status)}
+		if resp.Description == "" {
+			resp.Description = "Response"
+		}
+		for contentType, schema := range contents {
+			if resp.Content == nil {
+				resp.Content = make(map[string]*MediaType)
+			}
+			if schema == nil {
+				schema = &Schema{}
+			}
+			resp.Content[contentType] = &MediaType{Schema: schema}
+		}
+		op.Responses[strconv.Itoa(status)] = resp
+	}
+	return op
+}
+
+// Write 将文档写入文件，扩展名为 .json 时输出 JSON，否则输出 YAML
+func Write(doc *Document, path string) error {
+	var buf bytes.Buffer
+	if strings.EqualFold(filepath.Ext(path), ".json") {
+		encoder := json.NewEncoder(&buf)
+		encoder.SetEscapeHTML(false)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(doc); err != nil {
+			return fmt.Errorf("序列化 OpenAPI 文档失败: %v", err)
+		}
+	} else {
+		encoder := yaml.NewEncoder(&buf)
+		encoder.SetIndent(2)
+		if err := encoder.Encode(doc); err != nil {
+			return fmt.Errorf("序列化 OpenAPI 文档失败: %v", err)
+		}
+		encoder.Close()
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("创建目录失败: %v", err)
+	}
+	if err := os.WriteFile(path, buf.Bytes(), 0644); err != nil {
+		return fmt.Errorf("写入 %s 失败: %v", path, err)
+	}
+	return nil
+}
+
+// isHandlerFunc 函数签名是否为 func(http.ResponseWriter, *http.Request)
+func isHandlerFunc(fn *ast.FuncType) bool {
+	count := 0
+	for _, field := range fn.Params.List {
+		n := len(field.Names)
+		if n == 0 {
+			n = 1
+		}
+		count += n
+	}
+	return count == 2 && (fn.Results == nil || len(fn.Results.List) == 0)
+}
+
+// selectorName 返回选择器表达式最后一段的名称
+func selectorName(expr ast.Expr) string {
+	switch e := expr.(type) {
+	case *ast.SelectorExpr:
+		return e.Sel.Name
+	case *ast.Ident:
+		return e.Name
+	}
+	return ""
+}
+
+// operationName 根据路径生成匿名处理函数的 operationId，如 /health -> health
+func operationName(p string) string {
+	parts := strings.FieldsFunc(p, func(r rune) bool {
+		return !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9')
+	})
+	if len(parts) == 0 {
+		return "root"
+	}
+	for i := 1; i < len(parts); i++ {
+		parts[i] = strings.ToUpper(parts[i][:1]) + parts[i][1:]
+	}
+	return strings.Join(parts, "")
+}
+
+// uniqueID 同一个处理函数注册到多个路由时为 operationId 加上序号
+func uniqueID(seen map[string]int, id string) string {
+	seen[id]++
+	if seen[id] == 1 {
+		return id
+	}
+	return id + strconv.Itoa(seen[id])
+}
@@ -0,0 +1,294 @@
+package openapi
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+var testFiles = map[string]string{
+	"go.mod": "module demo\n\ngo 1.24\n",
+	"app/wire.go": `package app
+
+import "demo/app/controller"
+
+type Injector struct {
+	UserController *controller.UserController
+}
+`,
+	"app/model/dto/user_dto.go": `package dto
+
+import "time"
+
+// CreateUserRequest 创建用户请求
+type CreateUserRequest struct {
+	Name  string  ` + "`json:\"name\" validate:\"required\"`" + `
+	Email *string ` + "`json:\"email,omitempty\"`" + `
+	Age   int     ` + "`json:\"age\"`" + ` // 年龄
+}
+
+// UserResponse 用户信息
+type UserResponse struct {
+	ID        uint64    ` + "`json:\"id\"`" + `
+	Name      string    ` + "`json:\"name\"`" + `
+	Password  string    ` + "`json:\"-\"`" + `
+	CreatedAt time.Time ` + "`json:\"created_at\"`" + `
+}
+
+// UpdateUserRequest 更新用户请求
+type UpdateUserRequest struct {
+	ID     uint64   ` + "`path:\"id\"`" + `
+	Notify bool     ` + "`query:\"notify\"`" + `
+	Token  string   ` + "`header:\"X-Token\" validate:\"required\"`" + `
+	Name   string   ` + "`json:\"name\" validate:\"required,min=2,max=20\"`" + `
+	Gender int      ` + "`json:\"gender\" validate:\"oneof=0 1 2\"`" + `
+	Email  string   ` + "`json:\"email\" validate:\"email\"`" + `
+	Tags   []string ` + "`json:\"tags\" validate:\"max=5\"`" + `
+}
+`,
+	"pkg/binding/binding.go": `package binding
+
+import "net/http"
+
+func Bind(r *http.Request, v any) error {
+	return nil
+}
+`,
+	"app/service/user_service.go": `package service
+
+import (
+	"context"
+
+	"demo/app/model/dto"
+)
+
+type UserService struct{}
+
+func (s *UserService) Create(ctx context.Context, req dto.CreateUserRequest) (*dto.UserResponse, error) {
+	return &dto.UserResponse{Name: req.Name}, nil
+}
+`,
+	"app/controller/user_controller.go": `package controller
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"demo/app/model/dto"
+	"demo/app/service"
+
+	"github.com/stones-hub/taurus-pro-common/pkg/util/tmap"
+	"github.com/stones-hub/taurus-pro-http/pkg/httpx"
+)
+
+// UserController 用户控制器
+type UserController struct {
+	BaseController
+	UserService *service.UserService
+}
+
+// Create 创建用户
+func (c *UserController) Create(w http.ResponseWriter, r *http.Request) {
+	var req dto.CreateUserRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		httpx.SendResponse(w, http.StatusBadRequest, map[string]string{"message": err.Error()}, nil)
+		return
+	}
+	user, err := c.UserService.Create(r.Context(), req)
+	if err != nil {
+		httpx.SendResponse(w, http.StatusInternalServerError, map[string]string{"message": err.Error()}, nil)
+		return
+	}
+	httpx.SendResponse(w, http.StatusCreated, user, nil)
+}
+
+// Search 搜索用户
+func (c *UserController) Search(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		c.ErrorResponse(w, 400, "请求方法错误")
+		return
+	}
+	body, err := httpx.ParseJson(r)
+	if err != nil {
+		c.ErrorResponse(w, 400, "请求参数格式错误")
+		return
+	}
+	_ = tmap.GetInt(body, "page_no", 1)
+	_ = tmap.GetString(body, "keyword", "")
+	c.Response(w, 0, "ok", []dto.UserResponse{})
+}
+
+// Update 更新用户
+func (c *UserController) Update(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPut {
+		c.ErrorResponse(w, 400, "请求方法错误")
+		return
+	}
+	var req dto.UpdateUserRequest
+	if !c.Bind(w, r, &req) {
+		return
+	}
+	c.Response(w, 0, "ok", map[string]any{"status": "success"})
+}
+`,
+	"app/controller/base_controller.go": `package controller
+
+import (
+	"net/http"
+
+	"demo/pkg/binding"
+
+	"github.com/stones-hub/taurus-pro-http/pkg/httpx"
+)
+
+type BaseController struct{}
+
+func (c *BaseController) Bind(w http.ResponseWriter, r *http.Request, v any) bool {
+	if err := binding.Bind(r, v); err != nil {
+		c.Response(w, 2, err.Error(), map[string]any{"errors": []string{err.Error()}})
+		return false
+	}
+	return true
+}
+
+func (c *BaseController) Response(w http.ResponseWriter, code int, message string, data interface{}) {
+	httpx.CustomJSONResponse(w, map[string]interface{}{
+		"code":    code,
+		"message": message,
+		"data":    data,
+	}, nil)
+}
+
+func (c *BaseController) ErrorResponse(w http.ResponseWriter, code int, message string) {
+	c.Response(w, code, message, nil)
+}
+`,
+	"bin/taurus.go": `package main
+
+import (
+	"demo/app"
+	"net/http"
+
+	tmid "demo/pkg/middleware"
+
+	"github.com/stones-hub/taurus-pro-http/pkg/router"
+)
+
+func main() {
+	taurus.Container.Http.AddRouter(router.Router{
+		Path:    "/user/create",
+		Handler: http.HandlerFunc(app.Core.UserController.Create),
+	})
+	taurus.Container.Http.AddRouterGroup(router.RouteGroup{
+		Prefix:     "/user",
+		Middleware: []router.MiddlewareFunc{tmid.JWTMiddleware()},
+		Routes: []router.Router{
+			{Path: "/search", Handler: http.HandlerFunc(app.Core.UserController.Search)},
+			{Path: "/update/{id}", Handler: http.HandlerFunc(app.Core.UserController.Update)},
+		},
+	})
+	taurus.Container.Http.AddRouter(router.Router{
+		Path: "/health",
+		Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte("ok"))
+		}),
+	})
+	app.Run()
+}
+`,
+}
+
+func TestGenerate(t *testing.T) {
+	root := t.TempDir()
+	for name, content := range testFiles {
+		path := filepath.Join(root, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	doc, err := Generate(Options{ProjectRoot: root})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if doc.Info.Title != "demo" || len(doc.Paths) != 4 {
+		t.Fatalf("unexpected document: %s %d paths", doc.Info.Title, len(doc.Paths))
+	}
+
+	// JSON 请求体与 SendResponse 的多个状态码
+	create := doc.Paths["/user/create"].Post
+	if create == nil || create.OperationID != "UserController.Create" || create.Summary != "创建用户" {
+		t.Fatalf("unexpected create operation: %+v", create)
+	}
+	if ref := create.RequestBody.Content["application/json"].Schema.Ref; ref != "#/components/schemas/CreateUserRequest" {
+		t.Fatalf("unexpected create request: %s", ref)
+	}
+	if ref := create.Responses["201"].Content["application/json"].Schema.Ref; ref != "#/components/schemas/UserResponse" {
+		t.Fatalf("unexpected create response: %s", ref)
+	}
+	for _, status := range []string{"400", "500"} {
+		if _, ok := create.Responses[status]; !ok {
+			t.Fatalf("missing %s response", status)
+		}
+	}
+
+	request := doc.Components.Schemas["CreateUserRequest"]
+	if len(request.Required) != 1 || request.Required[0] != "name" || !request.Properties["email"].Nullable || request.Properties["age"].Description != "年龄" {
+		t.Fatalf("unexpected request schema: %+v", request)
+	}
+	user := doc.Components.Schemas["UserResponse"]
+	if _, ok := user.Properties["Password"]; ok || user.Properties["created_at"].Format != "date-time" {
+		t.Fatalf("unexpected user schema: %+v", user.Properties)
+	}
+
+	// httpx.ParseJson + tmap 的请求体、BaseController 的响应包装、JWT 认证
+	search := doc.Paths["/user/search"].Post
+	if search == nil || len(search.Security) != 1 {
+		t.Fatalf("unexpected search operation: %+v", search)
+	}
+	body := search.RequestBody.Content["application/json"].Schema
+	if body.Properties["page_no"].Type != "integer" || body.Properties["keyword"].Type != "string" {
+		t.Fatalf("unexpected search request: %+v", body.Properties)
+	}
+	envelope := search.Responses["200"].Content["application/json"].Schema
+	if envelope.Properties["code"].Type != "integer" || envelope.Properties["data"].Type != "array" ||
+		envelope.Properties["data"].Items.Ref != "#/components/schemas/UserResponse" {
+		t.Fatalf("unexpected search response: %+v", envelope.Properties)
+	}
+
+	// binding.Bind 的参数、请求体与 validate 约束，BaseController.Bind 的参数错误响应不作为接口响应
+	update := doc.Paths["/user/update/{id}"].Put
+	if update == nil || len(update.Parameters) != 3 {
+		t.Fatalf("unexpected update operation: %+v", update)
+	}
+	id, notify, token := update.Parameters[0], update.Parameters[1], update.Parameters[2]
+	if id.In != "path" || !id.Required || id.Schema.Format != "int64" ||
+		notify.In != "query" || notify.Schema.Type != "boolean" ||
+		token.Name != "X-Token" || token.In != "header" || !token.Required {
+		t.Fatalf("unexpected update parameters: %+v", update.Parameters)
+	}
+	if ref := update.RequestBody.Content["application/json"].Schema.Ref; ref != "#/components/schemas/UpdateUserRequest" {
+		t.Fatalf("unexpected update request: %s", ref)
+	}
+	updateReq := doc.Components.Schemas["UpdateUserRequest"]
+	if len(updateReq.Properties) != 4 || len(updateReq.Required) != 1 || updateReq.Required[0] != "name" {
+		t.Fatalf("unexpected update request schema: %+v", updateReq)
+	}
+	name, gender := updateReq.Properties["name"], updateReq.Properties["gender"]
+	if *name.MinLength != 2 || *name.MaxLength != 20 || len(gender.Enum) != 3 || gender.Enum[2] != int64(2) ||
+		updateReq.Properties["email"].Format != "email" || *updateReq.Properties["tags"].MaxItems != 5 {
+		t.Fatalf("unexpected update request properties: %+v", updateReq.Properties)
+	}
+	if data := update.Responses["200"].Content["application/json"].Schema.Properties["data"]; data.Properties["status"] == nil {
+		t.Fatalf("unexpected update response: %+v", data)
+	}
+
+	health := doc.Paths["/health"].Get
+	if health == nil || health.Responses["200"].Content["text/plain"] == nil {
+		t.Fatalf("unexpected health operation: %+v", health)
+	}
+}
@@ -0,0 +1,178 @@
+package openapi
+
+import (
+	"go/ast"
+	"path"
+	"strings"
+)
+
+// route 入口文件中通过 AddRouter 或 AddRouterGroup 注册的路由
+type route struct {
+	path       string
+	handler    ast.Expr
+	middleware []ast.Expr
+	file       *ast.File
+	pkg        *pkgInfo
+}
+
+// discoverRoutes 查找包中所有 AddRouter(router.Router{...}) 与 AddRouterGroup(router.RouteGroup{...}) 调用
+func (a *analyzer) discoverRoutes(pkg *pkgInfo) []route {
+	routes := make([]route, 0)
+	for _, file := range pkg.files {
+		ast.Inspect(file, func(n ast.Node) bool {
+			call, ok := n.(*ast.CallExpr)
+			if !ok || len(call.Args) != 1 {
+				return true
+			}
+			sel, ok := call.Fun.(*ast.SelectorExpr)
+			if !ok {
+				return true
+			}
+			lit, ok := compositeLit(call.Args[0])
+			if !ok {
+				return true
+			}
+
+			switch sel.Sel.Name {
+			case "AddRouter":
+				if r, ok := a.routerLit(lit, "", nil, file, pkg); ok {
+					routes = append(routes, r)
+				}
+			case "AddRouterGroup":
+				prefix, middleware := "", []ast.Expr(nil)
+				var list *ast.CompositeLit
+				for _, elt := range lit.Elts {
+					kv, ok := elt.(*ast.KeyValueExpr)
+					if !ok {
+						continue
+					}
+					switch keyName(kv) {
+					case "Prefix":
+						prefix, _ = a.constString(kv.Value, file, pkg)
+					case "Middleware":
+						middleware = elements(kv.Value)
+					case "Routes":
+						list, _ = compositeLit(kv.Value)
+					}
+				}
+				if list == nil {
+					return true
+				}
+				for _, elt := range list.Elts {
+					if item, ok := compositeLit(elt); ok {
+						if r, ok := a.routerLit(item, prefix, middleware, file, pkg); ok {
+							routes = append(routes, r)
+						}
+					}
+				}
+			}
+			return true
+		})
+	}
+	return routes
+}
+
+// routerLit 解析 router.Router{Path, Handler, Middleware} 字面量
+func (a *analyzer) routerLit(lit *ast.CompositeLit, prefix string, groupMiddleware []ast.Expr, file *ast.File, pkg *pkgInfo) (route, bool) {
+	r := route{file: file, pkg: pkg}
+	r.middleware = append(r.middleware, groupMiddleware...)
+
+	hasPath := false
+	for _, elt := range lit.Elts {
+		kv, ok := elt.(*ast.KeyValueExpr)
+		if !ok {
+			continue
+		}
+		switch keyName(kv) {
+		case "Path":
+			r.path, hasPath = a.constString(kv.Value, file, pkg)
+		case "Handler":
+			r.handler = kv.Value
+		case "Middleware":
+			r.middleware = append(r.middleware, elements(kv.Value)...)
+		}
+	}
+	if !hasPath || r.handler == nil {
+		return r, false
+	}
+	r.path = joinPath(prefix, r.path)
+	return r, true
+}
+
+// requiresAuth 路由的中间件中是否包含 JWT 或登录认证
+func (r route) requiresAuth() bool {
+	for _, mw := range r.middleware {
+		found := false
+		ast.Inspect(mw, func(n ast.Node) bool {
+			// 不进入 RecoveryMiddleware 等中间件参数中的匿名函数
+			if _, ok := n.(*ast.FuncLit); ok {
+				return false
+			}
+			if ident, ok := n.(*ast.Ident); ok {
+				name := strings.ToLower(ident.Name)
+				if strings.Contains(name, "jwt") || strings.HasPrefix(name, "auth") {
+					found = true
+				}
+			}
+			return !found
+		})
+		if found {
+			return true
+		}
+	}
+	return false
+}
+
+// compositeLit 返回表达式中的复合字面量，支持 &T{...}
+func compositeLit(expr ast.Expr) (*ast.CompositeLit, bool) {
+	if u, ok := expr.(*ast.UnaryExpr); ok {
+		expr = u.X
+	}
+	lit, ok := expr.(*ast.CompositeLit)
+	return lit, ok
+}
+
+// elements 返回切片字面量的元素
+func elements(expr ast.Expr) []ast.Expr {
+	if lit, ok := compositeLit(expr); ok {
+		return lit.Elts
+	}
+	return nil
+}
+
+// keyName 返回键值对的字段名
+func keyName(kv *ast.KeyValueExpr) string {
+	if ident, ok := kv.Key.(*ast.Ident); ok {
+		return ident.Name
+	}
+	return ""
+}
+
+// constString 解析字符串字面量、包内常量或它们的拼接
+func (a *analyzer) constString(expr ast.Expr, file *ast.File, pkg *pkgInfo) (string, bool) {
+	if e, ok := unparen(expr).(*ast.BinaryExpr); ok {
+		left, ok := a.constString(e.X, file, pkg)
+		if !ok {
+			return "", false
+		}
+		right, ok := a.constString(e.Y, file, pkg)
+		return left + right, ok
+	}
+	return a.stringValue(expr, &scope{file: file, pkg: pkg})
+}
+
+// joinPath 拼接路由组前缀与路径
+func joinPath(prefix, p string) string {
+	if prefix == "" {
+		if !strings.HasPrefix(p, "/") {
+			p = "/" + p
+		}
+		return p
+	}
+	joined := path.Join("/", prefix, p)
+	// 保留路径末尾的斜杠，如前缀匹配的 /static/
+	if strings.HasSuffix(p, "/") && !strings.HasSuffix(joined, "/") {
+		joined += "/"
+	}
+	return joined
+}
@@ -0,0 +1,384 @@
+package openapi
+
+import (
+	"go/ast"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// basicSchemas Go 内置类型对应的 schema
+var basicSchemas = map[string]Schema{
+	"string":  {Type: "string"},
+	"bool":    {Type: "boolean"},
+	"int":     {Type: "integer"},
+	"int8":    {Type: "integer", Format: "int32"},
+	"int16":   {Type: "integer", Format: "int32"},
+	"int32":   {Type: "integer", Format: "int32"},
+	"rune":    {Type: "integer", Format: "int32"},
+	"int64":   {Type: "integer", Format: "int64"},
+	"uint":    {Type: "integer"},
+	"uint8":   {Type: "integer", Format: "int32"},
+	"byte":    {Type: "integer", Format: "int32"},
+	"uint16":  {Type: "integer", Format: "int32"},
+	"uint32":  {Type: "integer", Format: "int64"},
+	"uint64":  {Type: "integer", Format: "int64"},
+	"uintptr": {Type: "integer", Format: "int64"},
+	"float32": {Type: "number", Format: "float"},
+	"float64": {Type: "number", Format: "double"},
+	"error":   {Type: "string"},
+}
+
+// externalSchemas 常用第三方类型对应的 schema，key 为 导入路径.类型名
+var externalSchemas = map[string]Schema{
+	"time.Time":                   {Type: "string", Format: "date-time"},
+	"time.Duration":               {Type: "integer", Format: "int64"},
+	"encoding/json.RawMessage":    {},
+	"encoding/json.Number":        {Type: "number"},
+	"database/sql.NullString":     {Type: "string", Nullable: true},
+	"database/sql.NullInt64":      {Type: "integer", Format: "int64", Nullable: true},
+	"database/sql.NullInt32":      {Type: "integer", Format: "int32", Nullable: true},
+	"database/sql.NullInt16":      {Type: "integer", Format: "int32", Nullable: true},
+	"database/sql.NullBool":       {Type: "boolean", Nullable: true},
+	"database/sql.NullFloat64":    {Type: "number", Format: "double", Nullable: true},
+	"database/sql.NullTime":       {Type: "string", Format: "date-time", Nullable: true},
+	"gorm.io/gorm.DeletedAt":      {Type: "string", Format: "date-time", Nullable: true},
+	"gorm.io/datatypes.JSON":      {},
+	"gorm.io/datatypes.JSONMap":   {Type: "object"},
+	"gorm.io/datatypes.Date":      {Type: "string", Format: "date"},
+	"github.com/google/uuid.UUID": {Type: "string", Format: "uuid"},
+}
+
+// schemaBuilder 将 Go 类型转换为 schema，命名结构体登记到 components.schemas
+type schemaBuilder struct {
+	src     *source
+	schemas map[string]*Schema
+	names   map[*typeInfo]string
+}
+
+// newSchemaBuilder 创建 schema 构建器
+func newSchemaBuilder(src *source) *schemaBuilder {
+	return &schemaBuilder{
+		src:     src,
+		schemas: make(map[string]*Schema),
+		names:   make(map[*typeInfo]string),
+	}
+}
+
+// typeSchema 返回类型表达式对应的 schema，file 与 pkg 为表达式所在的文件与包
+func (b *schemaBuilder) typeSchema(expr ast.Expr, file *ast.File, pkg *pkgInfo) *Schema {
+	switch t := expr.(type) {
+	case *ast.Ident:
+		if basic, ok := basicSchemas[t.Name]; ok {
+			return &basic
+		}
+		if t.Name == "any" {
+			return &Schema{}
+		}
+		if pkg != nil {
+			if info, ok := pkg.types[t.Name]; ok {
+				return b.namedSchema(info)
+			}
+		}
+		return &Schema{}
+	case *ast.SelectorExpr:
+		x, ok := t.X.(*ast.Ident)
+		if !ok || file == nil {
+			return &Schema{}
+		}
+		path := importPath(file, x.Name)
+		if ext, ok := externalSchemas[path+"."+t.Sel.Name]; ok {
+			return &ext
+		}
+		if info := b.src.findType(path, t.Sel.Name); info != nil {
+			return b.namedSchema(info)
+		}
+		// 无法解析的外部类型
+		return &Schema{}
+	case *ast.StarExpr:
+		return b.typeSchema(t.X, file, pkg)
+	case *ast.ArrayType:
+		if ident, ok := t.Elt.(*ast.Ident); ok && (ident.Name == "byte" || ident.Name == "uint8") {
+			return &Schema{Type: "string", Format: "byte"}
+		}
+		return &Schema{Type: "array", Items: b.typeSchema(t.Elt, file, pkg)}
+	case *ast.MapType:
+		return &Schema{Type: "object", AdditionalProperties: b.typeSchema(t.Value, file, pkg)}
+	case *ast.InterfaceType:
+		return &Schema{}
+	case *ast.StructType:
+		return b.structSchema(t, file, pkg)
+	case *ast.IndexExpr:
+		return b.typeSchema(t.X, file, pkg)
+	case *ast.IndexListExpr:
+		return b.typeSchema(t.X, file, pkg)
+	}
+	return &Schema{}
+}
+
+// namedSchema 结构体登记到 components 并返回引用，其它命名类型（如 type Status int）展开为底层类型
+func (b *schemaBuilder) namedSchema(info *typeInfo) *Schema {
+	st, ok := info.spec.Type.(*ast.StructType)
+	if !ok {
+		schema := b.typeSchema(info.spec.Type, info.file, info.pkg)
+		if schema.Ref == "" && schema.Description == "" {
+			copied := *schema
+			copied.Description, _ = docText(info.spec.Doc, info.spec.Name.Name)
+			schema = &copied
+		}
+		return schema
+	}
+
+	if name, ok := b.names[info]; ok {
+		return refSchema(name)
+	}
+
+	name := b.componentName(info)
+	b.names[info] = name
+	// 先占位，支持自引用的结构体
+	b.schemas[name] = &Schema{Type: "object"}
+	schema := b.structSchema(st, info.file, info.pkg)
+	schema.Description, _ = docText(info.spec.Doc, info.spec.Name.Name)
+	b.schemas[name] = schema
+	return refSchema(name)
+}
+
+// componentName 返回类型在 components 中的名称，不同包的同名类型加上包名前缀
+func (b *schemaBuilder) componentName(info *typeInfo) string {
+	name := info.spec.Name.Name
+	if _, taken := b.schemas[name]; !taken {
+		return name
+	}
+	prefixed := strings.ToUpper(info.pkg.name[:1]) + info.pkg.name[1:] + name
+	for i := 2; ; i++ {
+		if _, taken := b.schemas[prefixed]; !taken {
+			return prefixed
+		}
+		prefixed = strings.ToUpper(info.pkg.name[:1]) + info.pkg.name[1:] + name + strconv.Itoa(i)
+	}
+}
+
+// structSchema 将结构体转换为 object schema，嵌入字段的属性平铺到外层
+func (b *schemaBuilder) structSchema(st *ast.StructType, file *ast.File, pkg *pkgInfo) *Schema {
+	schema := &Schema{Type: "object", Properties: make(map[string]*Schema)}
+	b.addFields(schema, st, file, pkg, 0)
+	if len(schema.Properties) == 0 {
+		schema.Properties = nil
+	}
+	sort.Strings(schema.Required)
+	return schema
+}
+
+// addFields 添加结构体字段到 schema
+func (b *schemaBuilder) addFields(schema *Schema, st *ast.StructType, file *ast.File, pkg *pkgInfo, depth int) {
+	for _, field := range st.Fields.List {
+		tag := fieldTag(field)
+		jsonName, omitempty, asString, skip := parseJSONTag(tag.Get("json"))
+		// 从路径、查询参数、请求头或表单绑定的字段不在 JSON 请求体中
+		if in, _ := paramTag(tag); skip || in != "" {
+			continue
+		}
+
+		// 嵌入字段：没有 json 名称时平铺
+		if len(field.Names) == 0 {
+			if jsonName == "" && depth < 5 {
+				if embedded := b.src.resolveType(field.Type, file, pkg); embedded != nil {
+					if est, ok := embedded.spec.Type.(*ast.StructType); ok {
+						b.addFields(schema, est, embedded.file, embedded.pkg, depth+1)
+						continue
+					}
+				}
+			}
+			name := jsonName
+			if name == "" {
+				name = embeddedName(field.Type)
+			}
+			if name != "" && ast.IsExported(embeddedName(field.Type)) {
+				schema.Properties[name] = b.typeSchema(field.Type, file, pkg)
+			}
+			continue
+		}
+
+		for _, ident := range field.Names {
+			if !ident.IsExported() {
+				continue
+			}
+			name := jsonName
+			if name == "" {
+				name = ident.Name
+			}
+
+			prop := b.typeSchema(field.Type, file, pkg)
+			if asString {
+				prop = &Schema{Type: "string"}
+			}
+			if _, isPtr := field.Type.(*ast.StarExpr); isPtr && prop.Ref == "" {
+				copied := *prop
+				copied.Nullable = true
+				prop = &copied
+			}
+			prop = withRules(prop, tag.Get("validate"))
+			// $ref 不能有兄弟属性，引用类型的字段说明直接忽略
+			if desc := fieldDoc(field); desc != "" && prop.Ref == "" {
+				copied := *prop
+				copied.Description = desc
+				prop = &copied
+			}
+			schema.Properties[name] = prop
+
+			if !omitempty && isRequired(tag) {
+				schema.Required = append(schema.Required, name)
+			}
+		}
+	}
+}
+
+// fieldTag 返回字段的结构体标签
+func fieldTag(field *ast.Field) reflect.StructTag {
+	if field.Tag != nil {
+		if value, err := strconv.Unquote(field.Tag.Value); err == nil {
+			return reflect.StructTag(value)
+		}
+	}
+	return ""
+}
+
+// parseJSONTag 解析 json 标签，返回字段名、是否 omitempty、是否 string 选项、是否忽略
+func parseJSONTag(tag string) (string, bool, bool, bool) {
+	if tag == "-" {
+		return "", false, false, true
+	}
+	parts := strings.Split(tag, ",")
+	omitempty, asString := false, false
+	for _, opt := range parts[1:] {
+		switch opt {
+		case "omitempty", "omitzero":
+			omitempty = true
+		case "string":
+			asString = true
+		}
+	}
+	return parts[0], omitempty, asString, false
+}
+
+// isRequired 字段是否声明了 binding 或 validate 的 required 规则
+func isRequired(tag reflect.StructTag) bool {
+	for _, key := range []string{"binding", "validate"} {
+		for _, rule := range strings.Split(tag.Get(key), ",") {
+			if strings.TrimSpace(rule) == "required" {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// paramTag 返回字段的 path、query、header 或 form 标签（pkg/binding 的参数来源）与参数名，没有时返回空字符串
+func paramTag(tag reflect.StructTag) (string, string) {
+	for _, in := range []string{"path", "query", "header", "form"} {
+		if name := tag.Get(in); name != "" && name != "-" {
+			return in, name
+		}
+	}
+	return "", ""
+}
+
+// withRules 按 validate 标签为 schema 添加约束：min、max、len 对应长度、元素个数或数值范围，oneof 对应枚举，
+// email 对应 format；引用类型返回原 schema
+func withRules(schema *Schema, rules string) *Schema {
+	if schema.Ref != "" || rules == "" {
+		return schema
+	}
+	copied := *schema
+	for _, rule := range strings.Split(rules, ",") {
+		name, param, _ := strings.Cut(strings.TrimSpace(rule), "=")
+		switch name {
+		case "min", "max", "len":
+			limit, err := strconv.ParseFloat(param, 64)
+			if err != nil {
+				continue
+			}
+			n := int(limit)
+			switch copied.Type {
+			case "string":
+				if name != "max" {
+					copied.MinLength = &n
+				}
+				if name != "min" {
+					copied.MaxLength = &n
+				}
+			case "array":
+				if name != "max" {
+					copied.MinItems = &n
+				}
+				if name != "min" {
+					copied.MaxItems = &n
+				}
+			case "integer", "number":
+				if name != "max" {
+					copied.Minimum = &limit
+				}
+				if name != "min" {
+					copied.Maximum = &limit
+				}
+			}
+		case "oneof":
+			copied.Enum = nil
+			for _, option := range strings.Fields(param) {
+				var value any = option
+				switch copied.Type {
+				case "integer":
+					if n, err := strconv.ParseInt(option, 10, 64); err == nil {
+						value = n
+					}
+				case "number":
+					if f, err := strconv.ParseFloat(option, 64); err == nil {
+						value = f
+					}
+				}
+				copied.Enum = append(copied.Enum, value)
+			}
+		case "email":
+			copied.Format = "email"
+		case "mobile":
+			copied.Format = "mobile"
+		}
+	}
+	return &copied
+}
+
+// embeddedName 返回嵌入字段的类型名
+func embeddedName(expr ast.Expr) string {
+	switch t := expr.(type) {
+	case *ast.StarExpr:
+		return embeddedName(t.X)
+	case *ast.Ident:
+		return t.Name
+	case *ast.SelectorExpr:
+		return t.Sel.Name
+	}
+	return ""
+}
+
+// fieldDoc 返回字段的文档注释或行尾注释
+func fieldDoc(field *ast.Field) string {
+	for _, group := range []*ast.CommentGroup{field.Doc, field.Comment} {
+		if group != nil {
+			if text := strings.TrimSpace(group.Text()); text != "" {
+				return strings.Join(strings.Fields(text), " ")
+			}
+		}
+	}
+	return ""
+}
+
+// sortedKeys 返回排序后的 map 键
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,312 @@
+package openapi
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// pkgInfo 已解析的项目包
+type pkgInfo struct {
+	path    string                          // 导入路径，如 demo/app/model/dto
+	name    string                          // 包名
+	files   []*ast.File                     // 包内文件，按文件名排序
+	types   map[string]*typeInfo            // 类型名 -> 类型定义
+	funcs   map[string]*funcInfo            // 函数名 -> 函数定义
+	methods map[string]map[string]*funcInfo // 接收者类型名 -> 方法名 -> 方法定义
+	consts  map[string]ast.Expr             // 常量名 -> 值
+}
+
+// typeInfo 类型定义及其所在文件
+type typeInfo struct {
+	spec *ast.TypeSpec
+	file *ast.File
+	pkg  *pkgInfo
+}
+
+// funcInfo 函数或方法定义及其所在文件
+type funcInfo struct {
+	decl *ast.FuncDecl
+	file *ast.File
+	pkg  *pkgInfo
+}
+
+// source 项目源码索引
+type source struct {
+	module string
+	pkgs   map[string]*pkgInfo // 导入路径 -> 包
+}
+
+// loadSource 解析项目中指定目录（递归）的所有 Go 文件，跳过测试与 wire 生成的文件
+func loadSource(projectRoot, module string, dirs ...string) (*source, error) {
+	src := &source{module: module, pkgs: make(map[string]*pkgInfo)}
+	fset := token.NewFileSet()
+
+	for _, dir := range dirs {
+		base := filepath.Join(projectRoot, dir)
+		if _, err := os.Stat(base); os.IsNotExist(err) {
+			continue
+		}
+
+		err := filepath.Walk(base, func(path string, info os.FileInfo, err error) error {
+			if err != nil {
+				return err
+			}
+			if info.IsDir() {
+				if path != base && (strings.HasPrefix(info.Name(), ".") || info.Name() == "testdata") {
+					return filepath.SkipDir
+				}
+				return nil
+			}
+			name := info.Name()
+			if !strings.HasSuffix(name, ".go") || strings.HasSuffix(name, "_test.go") || name == "wire_gen.go" {
+				return nil
+			}
+
+			file, err := parser.ParseFile(fset, path, nil, parser.ParseComments)
+			if err != nil {
+				return fmt.Errorf("解析文件 %s 失败: %v", path, err)
+			}
+			// 跳过 wireinject 构建标签的文件，只保留用于查找 Injector 的 app/wire.go
+			if name == "wire.go" && file.Name.Name != "app" {
+				return nil
+			}
+
+			rel, _ := filepath.Rel(projectRoot, filepath.Dir(path))
+			importPath := module + "/" + filepath.ToSlash(rel)
+			src.add(importPath, file)
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	return src, nil
+}
+
+// add 将文件加入所在包的索引
+func (s *source) add(importPath string, file *ast.File) {
+	pkg, ok := s.pkgs[importPath]
+	if !ok {
+		pkg = &pkgInfo{
+			path:    importPath,
+			name:    file.Name.Name,
+			types:   make(map[string]*typeInfo),
+			funcs:   make(map[string]*funcInfo),
+			methods: make(map[string]map[string]*funcInfo),
+			consts:  make(map[string]ast.Expr),
+		}
+		s.pkgs[importPath] = pkg
+	}
+	pkg.files = append(pkg.files, file)
+
+	for _, decl := range file.Decls {
+		switch d := decl.(type) {
+		case *ast.GenDecl:
+			for _, spec := range d.Specs {
+				switch sp := spec.(type) {
+				case *ast.TypeSpec:
+					if sp.Doc == nil && len(d.Specs) == 1 {
+						sp.Doc = d.Doc
+					}
+					pkg.types[sp.Name.Name] = &typeInfo{spec: sp, file: file, pkg: pkg}
+				case *ast.ValueSpec:
+					if d.Tok != token.CONST {
+						continue
+					}
+					for i, name := range sp.Names {
+						if i < len(sp.Values) {
+							pkg.consts[name.Name] = sp.Values[i]
+						}
+					}
+				}
+			}
+		case *ast.FuncDecl:
+			info := &funcInfo{decl: d, file: file, pkg: pkg}
+			if d.Recv == nil || len(d.Recv.List) == 0 {
+				pkg.funcs[d.Name.Name] = info
+				continue
+			}
+			recv := receiverName(d.Recv.List[0].Type)
+			if pkg.methods[recv] == nil {
+				pkg.methods[recv] = make(map[string]*funcInfo)
+			}
+			pkg.methods[recv][d.Name.Name] = info
+		}
+	}
+}
+
+// receiverName 返回接收者的类型名
+func receiverName(expr ast.Expr) string {
+	switch t := expr.(type) {
+	case *ast.StarExpr:
+		return receiverName(t.X)
+	case *ast.Ident:
+		return t.Name
+	case *ast.IndexExpr:
+		return receiverName(t.X)
+	case *ast.IndexListExpr:
+		return receiverName(t.X)
+	}
+	return ""
+}
+
+// imports 返回文件中包名到导入路径的映射
+func imports(file *ast.File) map[string]string {
+	result := make(map[string]string, len(file.Imports))
+	for _, spec := range file.Imports {
+		path, _ := strconv.Unquote(spec.Path.Value)
+		name := filepath.Base(path)
+		if spec.Name != nil {
+			name = spec.Name.Name
+		}
+		result[name] = path
+	}
+	return result
+}
+
+// importPath 返回文件中包名对应的导入路径
+func importPath(file *ast.File, name string) string {
+	if file == nil {
+		return ""
+	}
+	return imports(file)[name]
+}
+
+// findType 在项目包中查找类型
+func (s *source) findType(pkgPath, name string) *typeInfo {
+	if pkg, ok := s.pkgs[pkgPath]; ok {
+		return pkg.types[name]
+	}
+	return nil
+}
+
+// findTypeByName 按类型名在所有项目包中查找，用于 Injector 字段名即类型名的约定
+func (s *source) findTypeByName(name string) *typeInfo {
+	for _, path := range sortedKeys(s.pkgs) {
+		if t, ok := s.pkgs[path].types[name]; ok {
+			if _, isStruct := t.spec.Type.(*ast.StructType); isStruct {
+				return t
+			}
+		}
+	}
+	return nil
+}
+
+// findMethod 查找类型的方法，包括嵌入字段提升的方法
+func (s *source) findMethod(t *typeInfo, name string) (*funcInfo, *typeInfo) {
+	return s.findMethodDepth(t, name, 0)
+}
+
+func (s *source) findMethodDepth(t *typeInfo, name string, depth int) (*funcInfo, *typeInfo) {
+	if t == nil || depth > 3 {
+		return nil, nil
+	}
+	if m, ok := t.pkg.methods[t.spec.Name.Name][name]; ok {
+		return m, t
+	}
+	st, ok := t.spec.Type.(*ast.StructType)
+	if !ok {
+		return nil, nil
+	}
+	for _, field := range st.Fields.List {
+		if len(field.Names) > 0 {
+			continue
+		}
+		if m, owner := s.findMethodDepth(s.resolveType(field.Type, t.file, t.pkg), name, depth+1); m != nil {
+			return m, owner
+		}
+	}
+	return nil, nil
+}
+
+// fieldType 返回结构体字段（包括嵌入字段中的字段）的类型定义
+func (s *source) fieldType(t *typeInfo, name string) *typeInfo {
+	if t == nil {
+		return nil
+	}
+	st, ok := t.spec.Type.(*ast.StructType)
+	if !ok {
+		return nil
+	}
+	for _, field := range st.Fields.List {
+		for _, n := range field.Names {
+			if n.Name == name {
+				return s.resolveType(field.Type, t.file, t.pkg)
+			}
+		}
+	}
+	for _, field := range st.Fields.List {
+		if len(field.Names) == 0 {
+			if ft := s.fieldType(s.resolveType(field.Type, t.file, t.pkg), name); ft != nil {
+				return ft
+			}
+		}
+	}
+	return nil
+}
+
+// resolveType 将类型表达式解析为项目中的命名类型，非项目类型返回 nil
+func (s *source) resolveType(expr ast.Expr, file *ast.File, pkg *pkgInfo) *typeInfo {
+	switch t := expr.(type) {
+	case *ast.StarExpr:
+		return s.resolveType(t.X, file, pkg)
+	case *ast.Ident:
+		return pkg.types[t.Name]
+	case *ast.SelectorExpr:
+		if x, ok := t.X.(*ast.Ident); ok {
+			return s.findType(importPath(file, x.Name), t.Sel.Name)
+		}
+	case *ast.IndexExpr:
+		return s.resolveType(t.X, file, pkg)
+	}
+	return nil
+}
+
+// injectorTypes 读取 app/wire.go 中 Injector 结构体的字段类型
+func (s *source) injectorTypes() map[string]*typeInfo {
+	result := make(map[string]*typeInfo)
+	injector := s.findType(s.module+"/app", "Injector")
+	if injector == nil {
+		return result
+	}
+	st, ok := injector.spec.Type.(*ast.StructType)
+	if !ok {
+		return result
+	}
+	for _, field := range st.Fields.List {
+		t := s.resolveType(field.Type, injector.file, injector.pkg)
+		for _, name := range field.Names {
+			if t != nil {
+				result[name.Name] = t
+			}
+		}
+	}
+	return result
+}
+
+// docText 返回注释文本，去掉以名称开头的部分，如 "CreateUser 创建用户" -> "创建用户"
+func docText(doc *ast.CommentGroup, name string) (string, string) {
+	if doc == nil {
+		return "", ""
+	}
+	text := strings.TrimSpace(doc.Text())
+	if text == "" {
+		return "", ""
+	}
+	lines := strings.SplitN(text, "\n", 2)
+	summary := strings.TrimSpace(lines[0])
+	if first, rest, ok := strings.Cut(summary, " "); ok && first == name {
+		summary = strings.TrimSpace(rest)
+	}
+	description := ""
+	if len(lines) > 1 {
+		description = strings.TrimSpace(lines[1])
+	}
+	return summary, description
+}
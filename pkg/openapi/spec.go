@@ -0,0 +1,131 @@
+package openapi
+
+// Document OpenAPI 3 文档，只包含生成器用到的字段
+type Document struct {
+	OpenAPI    string               `yaml:"openapi" json:"openapi"`
+	Info       Info                 `yaml:"info" json:"info"`
+	Servers    []Server             `yaml:"servers,omitempty" json:"servers,omitempty"`
+	Tags       []Tag                `yaml:"tags,omitempty" json:"tags,omitempty"`
+	Paths      map[string]*PathItem `yaml:"paths" json:"paths"`
+	Components Components           `yaml:"components,omitempty" json:"components,omitempty"`
+}
+
+// Info 文档基本信息
+type Info struct {
+	Title       string `yaml:"title" json:"title"`
+	Description string `yaml:"description,omitempty" json:"description,omitempty"`
+	Version     string `yaml:"version" json:"version"`
+}
+
+// Server 服务地址
+type Server struct {
+	URL         string `yaml:"url" json:"url"`
+	Description string `yaml:"description,omitempty" json:"description,omitempty"`
+}
+
+// Tag 接口分组
+type Tag struct {
+	Name        string `yaml:"name" json:"name"`
+	Description string `yaml:"description,omitempty" json:"description,omitempty"`
+}
+
+// PathItem 同一路径下不同方法的接口
+type PathItem struct {
+	Get    *Operation `yaml:"get,omitempty" json:"get,omitempty"`
+	Post   *Operation `yaml:"post,omitempty" json:"post,omitempty"`
+	Put    *Operation `yaml:"put,omitempty" json:"put,omitempty"`
+	Patch  *Operation `yaml:"patch,omitempty" json:"patch,omitempty"`
+	Delete *Operation `yaml:"delete,omitempty" json:"delete,omitempty"`
+}
+
+// Operation 单个接口
+type Operation struct {
+	Tags        []string              `yaml:"tags,omitempty" json:"tags,omitempty"`
+	Summary     string                `yaml:"summary,omitempty" json:"summary,omitempty"`
+	Description string                `yaml:"description,omitempty" json:"description,omitempty"`
+	OperationID string                `yaml:"operationId,omitempty" json:"operationId,omitempty"`
+	Parameters  []Parameter           `yaml:"parameters,omitempty" json:"parameters,omitempty"`
+	RequestBody *RequestBody          `yaml:"requestBody,omitempty" json:"requestBody,omitempty"`
+	Responses   map[string]*Response  `yaml:"responses" json:"responses"`
+	Security    []map[string][]string `yaml:"security,omitempty" json:"security,omitempty"`
+}
+
+// Parameter 路径、查询或请求头参数
+type Parameter struct {
+	Name     string  `yaml:"name" json:"name"`
+	In       string  `yaml:"in" json:"in"`
+	Required bool    `yaml:"required,omitempty" json:"required,omitempty"`
+	Schema   *Schema `yaml:"schema" json:"schema"`
+}
+
+// RequestBody 请求体
+type RequestBody struct {
+	Required bool                  `yaml:"required,omitempty" json:"required,omitempty"`
+	Content  map[string]*MediaType `yaml:"content" json:"content"`
+}
+
+// Response 响应
+type Response struct {
+	Description string                `yaml:"description" json:"description"`
+	Content     map[string]*MediaType `yaml:"content,omitempty" json:"content,omitempty"`
+}
+
+// MediaType 请求或响应的内容
+type MediaType struct {
+	Schema *Schema `yaml:"schema,omitempty" json:"schema,omitempty"`
+}
+
+// Components 可复用的 schema 与认证方式
+type Components struct {
+	Schemas         map[string]*Schema         `yaml:"schemas,omitempty" json:"schemas,omitempty"`
+	SecuritySchemes map[string]*SecurityScheme `yaml:"securitySchemes,omitempty" json:"securitySchemes,omitempty"`
+}
+
+// SecurityScheme 认证方式
+type SecurityScheme struct {
+	Type         string `yaml:"type" json:"type"`
+	Scheme       string `yaml:"scheme,omitempty" json:"scheme,omitempty"`
+	BearerFormat string `yaml:"bearerFormat,omitempty" json:"bearerFormat,omitempty"`
+}
+
+// Schema JSON Schema
+type Schema struct {
+	Ref                  string             `yaml:"$ref,omitempty" json:"$ref,omitempty"`
+	Type                 string             `yaml:"type,omitempty" json:"type,omitempty"`
+	Format               string             `yaml:"format,omitempty" json:"format,omitempty"`
+	Description          string             `yaml:"description,omitempty" json:"description,omitempty"`
+	Nullable             bool               `yaml:"nullable,omitempty" json:"nullable,omitempty"`
+	Enum                 []any              `yaml:"enum,omitempty" json:"enum,omitempty"`
+	Minimum              *float64           `yaml:"minimum,omitempty" json:"minimum,omitempty"`
+	Maximum              *float64           `yaml:"maximum,omitempty" json:"maximum,omitempty"`
+	MinLength            *int               `yaml:"minLength,omitempty" json:"minLength,omitempty"`
+	MaxLength            *int               `yaml:"maxLength,omitempty" json:"maxLength,omitempty"`
+	MinItems             *int               `yaml:"minItems,omitempty" json:"minItems,omitempty"`
+	MaxItems             *int               `yaml:"maxItems,omitempty" json:"maxItems,omitempty"`
+	Items                *Schema            `yaml:"items,omitempty" json:"items,omitempty"`
+	Properties           map[string]*Schema `yaml:"properties,omitempty" json:"properties,omitempty"`
+	Required             []string           `yaml:"required,omitempty" json:"required,omitempty"`
+	AdditionalProperties *Schema            `yaml:"additionalProperties,omitempty" json:"additionalProperties,omitempty"`
+	Example              any                `yaml:"example,omitempty" json:"example,omitempty"`
+}
+
+// operation 返回指定方法的接口，不存在时创建
+func (p *PathItem) operation(method string) **Operation {
+	switch method {
+	case "GET":
+		return &p.Get
+	case "PUT":
+		return &p.Put
+	case "PATCH":
+		return &p.Patch
+	case "DELETE":
+		return &p.Delete
+	default:
+		return &p.Post
+	}
+}
+
+// refSchema 引用 components 中的 schema
+func refSchema(name string) *Schema {
+	return &Schema{Ref: "#/components/schemas/" + name}
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"log"
 	"os"
+	"os/exec"
 	"path/filepath"
 	"strings"
 	"text/template"
@@ -19,20 +20,22 @@ package app
 
 import (
 	"github.com/google/wire"
+	"{{$.ModuleName}}/internal/taurus"
 {{- range .Imports}}
 	"{{$.ModuleName}}/{{.}}"
 {{- end}}
 )
 
-// Injector 应用程序结构
+// Injector 应用程序结构，Components 为构建时传入的组件
 type Injector struct {
+	Components *taurus.Components
 {{- range .Fields}}
 	{{.}}
 {{- end}}
 }
 
-// buildInjector 构建应用程序
-func buildInjector() (*Injector, func(), error) {
+// buildInjector 构建应用程序，provider 通过参数 *taurus.Components 获取组件，不需要读取全局的 taurus.Container
+func buildInjector(components *taurus.Components) (*Injector, func(), error) {
 	wire.Build(
 		// 应用结构
 		wire.Struct(new(Injector), "*"),
@@ -50,7 +53,7 @@ func GenerateProjectWire(scannerPath string) error {
 	projectRoot := filepath.Dir(scannerPath)
 
 	// 获取模块名称
-	moduleName, err := getModuleName(projectRoot)
+	moduleName, err := GetModuleName(projectRoot)
 	if err != nil {
 		return fmt.Errorf("获取模块名称失败: %v", err)
 	}
@@ -107,8 +110,28 @@ func GenerateProjectWire(scannerPath string) error {
 	return nil
 }
 
-// getModuleName 从 go.mod 文件中获取模块名称
-func getModuleName(projectRoot string) (string, error) {
+// RefreshProjectWire 重新扫描 app 目录生成 wire.go，并执行 wire 生成注入器
+func RefreshProjectWire(appPath string) error {
+	if err := GenerateProjectWire(appPath); err != nil {
+		return err
+	}
+
+	fmtCmd := exec.Command("go", "fmt", filepath.Join(appPath, "wire.go"))
+	if output, err := fmtCmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("执行 go fmt 失败: %v\n输出: %s", err, output)
+	}
+
+	wireCmd := exec.Command("wire")
+	wireCmd.Dir = appPath
+	if output, err := wireCmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("执行 wire 命令失败: %v\n输出: %s", err, output)
+	}
+
+	return nil
+}
+
+// GetModuleName 从 go.mod 文件中获取模块名称
+func GetModuleName(projectRoot string) (string, error) {
 	goModPath := filepath.Join(projectRoot, "go.mod")
 	content, err := os.ReadFile(goModPath)
 	if err != nil {
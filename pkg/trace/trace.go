@@ -0,0 +1,232 @@
+package trace
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+)
+
+// zeroSpanID 没有父 span 时 Parent.SpanID 的值
+const zeroSpanID = "0000000000000000"
+
+// spanKinds span 类型的名称，下标为 otel 的 SpanKind，internal 不显示
+var spanKinds = []string{"", "", "server", "client", "producer", "consumer"}
+
+// SpanContext span 的 trace/span ID
+type SpanContext struct {
+	TraceID string `json:"TraceID"`
+	SpanID  string `json:"SpanID"`
+}
+
+// Attribute span 属性，Value.Type 为 STRING、INT64、BOOL 等
+type Attribute struct {
+	Key   string `json:"Key"`
+	Value struct {
+		Type  string `json:"Type"`
+		Value any    `json:"Value"`
+	} `json:"Value"`
+}
+
+// Status span 状态，Code 为 Unset、Ok、Error
+type Status struct {
+	Code        string `json:"Code"`
+	Description string `json:"Description"`
+}
+
+// Span otel stdouttrace 导出器输出的一行 JSON，只解析展示需要的字段
+type Span struct {
+	Name        string      `json:"Name"`
+	SpanContext SpanContext `json:"SpanContext"`
+	Parent      SpanContext `json:"Parent"`
+	SpanKind    int         `json:"SpanKind"`
+	StartTime   time.Time   `json:"StartTime"`
+	EndTime     time.Time   `json:"EndTime"`
+	Attributes  []Attribute `json:"Attributes"`
+	Status      Status      `json:"Status"`
+}
+
+// Duration span 耗时
+func (s *Span) Duration() time.Duration {
+	return s.EndTime.Sub(s.StartTime)
+}
+
+// Node 树中的 span 节点
+type Node struct {
+	Span     *Span
+	Children []*Node
+}
+
+// Trace 一条链路，父 span 不在文件中（如来自上游服务）的 span 作为根节点
+type Trace struct {
+	ID    string
+	Roots []*Node
+	Spans int
+	Start time.Time
+	End   time.Time
+}
+
+// Options 渲染选项
+type Options struct {
+	TraceID    string // 只显示以此开头的 trace
+	Attributes bool   // 显示 span 属性
+}
+
+// Read 逐行读取 JSONL 格式的 span，非 JSON 或没有 TraceID 的行会被跳过，便于直接读取混有日志的标准输出
+func Read(r io.Reader) ([]*Span, error) {
+	spans := make([]*Span, 0)
+	reader := bufio.NewReader(r)
+	for {
+		line, err := reader.ReadBytes('\n')
+		if len(line) > 0 {
+			line = bytes.TrimSpace(line)
+			if bytes.HasPrefix(line, []byte("{")) {
+				var span Span
+				if json.Unmarshal(line, &span) == nil && span.SpanContext.TraceID != "" {
+					spans = append(spans, &span)
+				}
+			}
+		}
+		if errors.Is(err, io.EOF) {
+			return spans, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("读取 span 失败: %v", err)
+		}
+	}
+}
+
+// Build 按 TraceID 分组并组装成树，trace 与兄弟节点都按开始时间排序
+func Build(spans []*Span) []*Trace {
+	traces := make(map[string]*Trace)
+	nodes := make(map[string]*Node)
+	for _, span := range spans {
+		nodes[span.SpanContext.TraceID+"/"+span.SpanContext.SpanID] = &Node{Span: span}
+	}
+
+	for _, span := range spans {
+		t, ok := traces[span.SpanContext.TraceID]
+		if !ok {
+			t = &Trace{ID: span.SpanContext.TraceID, Start: span.StartTime, End: span.EndTime}
+			traces[t.ID] = t
+		}
+		t.Spans++
+		if span.StartTime.Before(t.Start) {
+			t.Start = span.StartTime
+		}
+		if span.EndTime.After(t.End) {
+			t.End = span.EndTime
+		}
+
+		node := nodes[span.SpanContext.TraceID+"/"+span.SpanContext.SpanID]
+		parent, ok := nodes[span.SpanContext.TraceID+"/"+span.Parent.SpanID]
+		if span.Parent.SpanID == "" || span.Parent.SpanID == zeroSpanID || !ok || parent == node {
+			t.Roots = append(t.Roots, node)
+			continue
+		}
+		parent.Children = append(parent.Children, node)
+	}
+
+	result := make([]*Trace, 0, len(traces))
+	for _, t := range traces {
+		sortNodes(t.Roots)
+		result = append(result, t)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Start.Equal(result[j].Start) {
+			return result[i].ID < result[j].ID
+		}
+		return result[i].Start.Before(result[j].Start)
+	})
+	return result
+}
+
+func sortNodes(nodes []*Node) {
+	sort.SliceStable(nodes, func(i, j int) bool {
+		return nodes[i].Span.StartTime.Before(nodes[j].Span.StartTime)
+	})
+	for _, node := range nodes {
+		sortNodes(node.Children)
+	}
+}
+
+// Render 以树形输出 trace，每个 span 显示类型、耗时与相对 trace 开始的偏移，错误的 span 标记 ✗
+func Render(w io.Writer, traces []*Trace, opts Options) error {
+	count := 0
+	for _, t := range traces {
+		if opts.TraceID != "" && !strings.HasPrefix(t.ID, opts.TraceID) {
+			continue
+		}
+		if count > 0 {
+			fmt.Fprintln(w)
+		}
+		count++
+
+		fmt.Fprintf(w, "trace %s  %d spans  %s  %s\n", t.ID, t.Spans, formatDuration(t.End.Sub(t.Start)), t.Start.Local().Format("2006-01-02 15:04:05.000"))
+		for i, root := range t.Roots {
+			renderNode(w, t, root, "", i == len(t.Roots)-1, opts)
+		}
+	}
+
+	if count == 0 {
+		if opts.TraceID != "" {
+			return fmt.Errorf("未找到 trace: %s", opts.TraceID)
+		}
+		return fmt.Errorf("没有可显示的 span")
+	}
+	return nil
+}
+
+func renderNode(w io.Writer, t *Trace, node *Node, prefix string, last bool, opts Options) {
+	branch, indent := "├─ ", "│  "
+	if last {
+		branch, indent = "└─ ", "   "
+	}
+
+	span := node.Span
+	line := prefix + branch + span.Name
+	if span.SpanKind > 0 && span.SpanKind < len(spanKinds) && spanKinds[span.SpanKind] != "" {
+		line += " [" + spanKinds[span.SpanKind] + "]"
+	}
+	line += "  " + formatDuration(span.Duration()) + "  +" + formatDuration(span.StartTime.Sub(t.Start))
+	if span.Status.Code == "Error" {
+		line += "  ✗"
+		if span.Status.Description != "" {
+			line += " " + span.Status.Description
+		}
+	}
+	fmt.Fprintln(w, line)
+
+	if opts.Attributes {
+		attrPrefix := prefix + indent
+		if len(node.Children) > 0 {
+			attrPrefix += "│ "
+		} else {
+			attrPrefix += "  "
+		}
+		for _, attr := range span.Attributes {
+			fmt.Fprintf(w, "%s%s=%v\n", attrPrefix, attr.Key, attr.Value.Value)
+		}
+	}
+
+	for i, child := range node.Children {
+		renderNode(w, t, child, prefix+indent, i == len(node.Children)-1, opts)
+	}
+}
+
+// formatDuration 按量级保留精度，如 850µs、12.3ms、1.25s
+func formatDuration(d time.Duration) string {
+	switch {
+	case d < time.Millisecond:
+		return d.Round(time.Microsecond).String()
+	case d < time.Second:
+		return fmt.Sprintf("%.1fms", float64(d)/float64(time.Millisecond))
+	default:
+		return fmt.Sprintf("%.2fs", d.Seconds())
+	}
+}
@@ -0,0 +1,50 @@
+package trace
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+// TestRender 测试 JSONL 解析与树形输出
+func TestRender(t *testing.T) {
+	input := strings.Join([]string{
+		`{"Name":"SELECT users","SpanContext":{"TraceID":"4bf92f3577b34da6a3ce929d0e0e4736","SpanID":"00f067aa0ba902b2"},"Parent":{"TraceID":"4bf92f3577b34da6a3ce929d0e0e4736","SpanID":"00f067aa0ba902b1"},"SpanKind":3,"StartTime":"2026-10-19T10:00:00.001Z","EndTime":"2026-10-19T10:00:00.003Z","Attributes":[{"Key":"db.name","Value":{"Type":"STRING","Value":"default"}}],"Status":{"Code":"Unset","Description":""}}`,
+		`🔗 -> Initialize otel components successfully.`,
+		`{"Name":"GET /users","SpanContext":{"TraceID":"4bf92f3577b34da6a3ce929d0e0e4736","SpanID":"00f067aa0ba902b1"},"Parent":{"TraceID":"00000000000000000000000000000000","SpanID":"0000000000000000"},"SpanKind":2,"StartTime":"2026-10-19T10:00:00Z","EndTime":"2026-10-19T10:00:00.010Z","Status":{"Code":"Unset","Description":""}}`,
+		`{"Name":"redis get","SpanContext":{"TraceID":"4bf92f3577b34da6a3ce929d0e0e4736","SpanID":"00f067aa0ba902b3"},"Parent":{"TraceID":"4bf92f3577b34da6a3ce929d0e0e4736","SpanID":"00f067aa0ba902b1"},"SpanKind":3,"StartTime":"2026-10-19T10:00:00.004Z","EndTime":"2026-10-19T10:00:00.0045Z","Status":{"Code":"Error","Description":"redis: nil"}}`,
+		``,
+	}, "\n")
+
+	spans, err := Read(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("Read failed: %v", err)
+	}
+	if len(spans) != 3 {
+		t.Fatalf("Expected 3 spans, got %d", len(spans))
+	}
+
+	traces := Build(spans)
+	var buf bytes.Buffer
+	if err := Render(&buf, traces, Options{Attributes: true}); err != nil {
+		t.Fatalf("Render failed: %v", err)
+	}
+
+	lines := strings.Split(strings.TrimRight(buf.String(), "\n"), "\n")
+	if !strings.HasPrefix(lines[0], "trace 4bf92f3577b34da6a3ce929d0e0e4736  3 spans  10.0ms") {
+		t.Errorf("Unexpected header: %s", lines[0])
+	}
+	expected := []string{
+		"└─ GET /users [server]  10.0ms  +0s",
+		"   ├─ SELECT users [client]  2.0ms  +1.0ms",
+		"   │    db.name=default",
+		"   └─ redis get [client]  500µs  +4.0ms  ✗ redis: nil",
+	}
+	if got := strings.Join(lines[1:], "\n"); got != strings.Join(expected, "\n") {
+		t.Errorf("Unexpected tree:\n%s\nexpected:\n%s", got, strings.Join(expected, "\n"))
+	}
+
+	if err := Render(&buf, traces, Options{TraceID: "ffff"}); err == nil {
+		t.Error("Expected error for unknown trace id")
+	}
+}